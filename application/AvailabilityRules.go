@@ -0,0 +1,167 @@
+package application
+
+import (
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"context"
+	"fmt"
+	"time"
+)
+
+// maxAvailabilityRuleNum はユーザーごとの繰り返しのルールの上限
+const maxAvailabilityRuleNum = 50
+
+// endOfDay は 1 日の終わりを表す時刻（ルールの終了時刻にのみ使える）
+const endOfDay = "24:00"
+
+// AvailabilityRuleInput は毎週繰り返すルールの入力を表す
+type AvailabilityRuleInput struct {
+	Weekday   int    // 0: 日曜 〜 6: 土曜
+	StartTime string // HH:MM
+	EndTime   string // HH:MM または 24:00
+	Kind      string // "available" または "unavailable"
+}
+
+// ListAvailabilityRules はユーザーの繰り返しのルールを返す
+func ListAvailabilityRules(ctx context.Context, userID string) ([]repository.AvailabilityRule, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+	return repo.ListAvailabilityRules(ctx, userID)
+}
+
+// ReplaceAvailabilityRules はユーザーの繰り返しのルールを rules で置き換える
+// ルールは以後のカレンダーからの取り込みと手入力の空き時間に、参加するすべてのイベントで適用する
+func ReplaceAvailabilityRules(ctx context.Context, userID string, rules []AvailabilityRuleInput) ([]repository.AvailabilityRule, error) {
+	verr := &validationErrors{}
+	if len(rules) > maxAvailabilityRuleNum {
+		verr.add("rules", FieldErrorTooMany, fmt.Sprintf("ルールは%d件以内で指定してください", maxAvailabilityRuleNum), maxAvailabilityRuleNum)
+		return nil, verr.err()
+	}
+
+	now := time.Now()
+	records := make([]repository.AvailabilityRule, 0, len(rules))
+	for i, r := range rules {
+		field := fmt.Sprintf("rules[%d]", i)
+		if r.Weekday < 0 || r.Weekday > 6 {
+			verr.add(field+".weekday", FieldErrorInvalidFormat, "曜日は0（日曜）〜6（土曜）で指定してください")
+		}
+		kind, ok := ParseAvailabilityRuleKind(r.Kind)
+		if !ok {
+			verr.add(field+".kind", FieldErrorInvalidFormat, "kind は available または unavailable で指定してください")
+		}
+		start, startOK := parseMinuteOfDay(r.StartTime, false)
+		if !startOK {
+			verr.add(field+".startTime", FieldErrorInvalidFormat, "開始時刻は HH:MM 形式で指定してください")
+		}
+		end, endOK := parseMinuteOfDay(r.EndTime, true)
+		if !endOK {
+			verr.add(field+".endTime", FieldErrorInvalidFormat, "終了時刻は HH:MM 形式で指定してください")
+		}
+		if startOK && endOK && end <= start {
+			verr.add(field+".endTime", FieldErrorTimeOrder, "終了時刻は開始時刻より後を指定してください")
+		}
+		records = append(records, repository.AvailabilityRule{
+			UserID:    userID,
+			Weekday:   int8(r.Weekday),
+			StartTime: r.StartTime,
+			EndTime:   r.EndTime,
+			Kind:      kind,
+			CreatedAt: now,
+		})
+	}
+	if err := verr.err(); err != nil {
+		return nil, err
+	}
+
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+	if err := repo.ReplaceAvailabilityRules(ctx, userID, records); err != nil {
+		return nil, err
+	}
+	return repo.ListAvailabilityRules(ctx, userID)
+}
+
+// AvailabilityRuleKindName はルールの種類を API で使う名前に変換する
+func AvailabilityRuleKindName(kind int8) string {
+	if kind == repository.AvailabilityRuleKindAvailable {
+		return "available"
+	}
+	return "unavailable"
+}
+
+// ParseAvailabilityRuleKind は API で使うルールの種類の名前をルールの種類に変換する
+func ParseAvailabilityRuleKind(name string) (int8, bool) {
+	switch name {
+	case "available":
+		return repository.AvailabilityRuleKindAvailable, true
+	case "unavailable":
+		return repository.AvailabilityRuleKindUnavailable, true
+	}
+	return 0, false
+}
+
+// parseMinuteOfDay は HH:MM 形式の時刻を 0:00 からの分に変換する。allowEndOfDay なら 24:00 も受け付ける
+func parseMinuteOfDay(value string, allowEndOfDay bool) (int, bool) {
+	if allowEndOfDay && value == endOfDay {
+		return 24 * 60, true
+	}
+	t, ok := parseHHMM(value)
+	if !ok {
+		return 0, false
+	}
+	return t.Hour()*60 + t.Minute(), true
+}
+
+// loadWeeklySchedule はユーザーのプロフィールの勤務時間とタイムゾーン、繰り返しのルールを読み込む
+// プロフィールもルールもないユーザー（ゲストを含む）は空のスケジュールになり、空き時間は変わらない
+func loadWeeklySchedule(ctx context.Context, repo *repository.SupabaseRepositoryImpl, userID string) (servise.WeeklySchedule, error) {
+	if userID == "" || userID == anonymousRespondentID {
+		return servise.WeeklySchedule{}, nil
+	}
+	u, _, err := getOrNewUser(ctx, repo, userID, time.Now())
+	if err != nil {
+		return servise.WeeklySchedule{}, err
+	}
+	rules, err := repo.ListAvailabilityRules(ctx, userID)
+	if err != nil {
+		return servise.WeeklySchedule{}, err
+	}
+	return newWeeklySchedule(u, rules)
+}
+
+// newWeeklySchedule はプロフィールとルールのレコードから WeeklySchedule を作る
+func newWeeklySchedule(u *repository.Users, rules []repository.AvailabilityRule) (servise.WeeklySchedule, error) {
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil || u.Timezone == "" {
+		if loc, err = time.LoadLocation(defaultTimezone); err != nil {
+			return servise.WeeklySchedule{}, fmt.Errorf("failed to load time zone: %w", err)
+		}
+	}
+
+	s := servise.WeeklySchedule{Location: loc}
+	if u.WorkStart.Valid && u.WorkEnd.Valid {
+		start, startOK := parseMinuteOfDay(u.WorkStart.String, false)
+		end, endOK := parseMinuteOfDay(u.WorkEnd.String, true)
+		if startOK && endOK && end > start {
+			s.WorkStart, s.WorkEnd = start, end
+		}
+	}
+	for _, r := range rules {
+		start, startOK := parseMinuteOfDay(r.StartTime, false)
+		end, endOK := parseMinuteOfDay(r.EndTime, true)
+		if !startOK || !endOK {
+			continue
+		}
+		s.Rules = append(s.Rules, servise.WeeklyRule{
+			Weekday:   time.Weekday(r.Weekday),
+			Start:     start,
+			End:       end,
+			Available: r.Kind == repository.AvailabilityRuleKindAvailable,
+		})
+	}
+	return s, nil
+}
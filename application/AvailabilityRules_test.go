@@ -0,0 +1,61 @@
+package application
+
+import (
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"database/sql"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNewWeeklySchedule(t *testing.T) {
+	u := &repository.Users{
+		Timezone:  "Not/AZone",
+		WorkStart: sql.NullString{String: "10:00", Valid: true},
+		WorkEnd:   sql.NullString{String: "24:00", Valid: true},
+	}
+	rules := []repository.AvailabilityRule{
+		{Weekday: 3, StartTime: "18:00", EndTime: "24:00", Kind: repository.AvailabilityRuleKindUnavailable},
+		{Weekday: 6, StartTime: "13:00", EndTime: "15:30", Kind: repository.AvailabilityRuleKindAvailable},
+		{Weekday: 1, StartTime: "broken", EndTime: "10:00"},
+	}
+
+	s, err := newWeeklySchedule(u, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 不明なタイムゾーンは既定のタイムゾーンで解釈する
+	if s.Location == nil || s.Location.String() != defaultTimezone {
+		t.Errorf("location = %v, want %s", s.Location, defaultTimezone)
+	}
+	if s.WorkStart != 10*60 || s.WorkEnd != 24*60 {
+		t.Errorf("working hours = %d-%d", s.WorkStart, s.WorkEnd)
+	}
+	want := []servise.WeeklyRule{
+		{Weekday: time.Wednesday, Start: 18 * 60, End: 24 * 60},
+		{Weekday: time.Saturday, Start: 13 * 60, End: 15*60 + 30, Available: true},
+	}
+	if !slices.Equal(s.Rules, want) {
+		t.Errorf("rules = %+v, want %+v", s.Rules, want)
+	}
+}
+
+func TestParseMinuteOfDay(t *testing.T) {
+	for _, tc := range []struct {
+		value         string
+		allowEndOfDay bool
+		want          int
+		ok            bool
+	}{
+		{"09:30", false, 9*60 + 30, true},
+		{"24:00", true, 24 * 60, true},
+		{"24:00", false, 0, false},
+		{"9:30", false, 0, false},
+	} {
+		got, ok := parseMinuteOfDay(tc.value, tc.allowEndOfDay)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("parseMinuteOfDay(%q, %v) = %d, %v, want %d, %v", tc.value, tc.allowEndOfDay, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package application
+
+import (
+	"adjuSche-back-end/domain"
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// calendarFeedName はフィードをカレンダーアプリに登録したときの表示名
+const calendarFeedName = "adjuSche"
+
+// CalendarFeed はユーザーの購読フィードの内容を表す
+// ETag は内容から計算するため、イベントが変わらない限り同じ値になる
+type CalendarFeed struct {
+	ICS          []byte
+	ETag         string
+	LastModified time.Time
+}
+
+// GetCalendarFeedToken はユーザーのフィードのトークンを返す。未発行の場合は発行する
+func GetCalendarFeedToken(ctx context.Context, userID string) (string, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return "", fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	ft, err := repo.GetCalendarFeedTokenByUserID(ctx, userID)
+	if err == nil {
+		return ft.Token, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return "", err
+	}
+	return issueCalendarFeedToken(ctx, repo, userID)
+}
+
+// RotateCalendarFeedToken はフィードのトークンを発行し直す。以前のフィードの URL は使えなくなる
+func RotateCalendarFeedToken(ctx context.Context, userID string) (string, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return "", fmt.Errorf("failed to init repository: %w", err)
+	}
+	return issueCalendarFeedToken(ctx, repo, userID)
+}
+
+// BuildCalendarFeed はトークンの持ち主が主催・参加しているイベントのうち、日時が確定したものの .ics を作る
+func BuildCalendarFeed(ctx context.Context, token string) (CalendarFeed, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return CalendarFeed{}, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	ft, err := repo.GetCalendarFeedTokenByToken(ctx, token)
+	if err != nil {
+		return CalendarFeed{}, err
+	}
+
+	evs, err := repo.ListDecidedEventsByUserID(ctx, ft.UserID)
+	if err != nil {
+		return CalendarFeed{}, err
+	}
+
+	ids := make([]int64, 0, len(evs))
+	for _, ev := range evs {
+		ids = append(ids, ev.ID)
+	}
+	participants, err := repo.ListEventParticipantsByEventIDs(ctx, ids)
+	if err != nil {
+		return CalendarFeed{}, err
+	}
+
+	// DTSTAMP を最終更新日時にそろえ、内容が変わらなければ同じ .ics になるようにする
+	lastModified := ft.CreatedAt
+	cal := servise.ICalendar{Name: calendarFeedName}
+	for i := range evs {
+		ev := &evs[i]
+		if ev.UpdatedAt.After(lastModified) {
+			lastModified = ev.UpdatedAt
+		}
+		cal.Events = append(cal.Events, newFinalizedICalEvent(ev, participants[ev.ID]))
+	}
+
+	return newCalendarFeed(servise.BuildICalendar(cal, lastModified), lastModified), nil
+}
+
+// newCalendarFeed は .ics の内容のハッシュを ETag にしたフィードを作る
+func newCalendarFeed(ics []byte, lastModified time.Time) CalendarFeed {
+	sum := sha256.Sum256(ics)
+	return CalendarFeed{
+		ICS:          ics,
+		ETag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastModified: lastModified,
+	}
+}
+
+func issueCalendarFeedToken(ctx context.Context, repo *repository.SupabaseRepositoryImpl, userID string) (string, error) {
+	token, err := generateNonce()
+	if err != nil {
+		return "", err
+	}
+	ft, err := repo.SaveCalendarFeedToken(ctx, userID, token)
+	if err != nil {
+		return "", err
+	}
+	return ft.Token, nil
+}
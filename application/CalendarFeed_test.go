@@ -0,0 +1,25 @@
+package application
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestNewCalendarFeedETag(t *testing.T) {
+	modified := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	a := newCalendarFeed([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), modified)
+
+	if !regexp.MustCompile(`^"[0-9a-f]{32}"$`).MatchString(a.ETag) {
+		t.Errorf("ETag = %s, want a quoted 32-digit hex string", a.ETag)
+	}
+	if !a.LastModified.Equal(modified) {
+		t.Errorf("LastModified = %s, want %s", a.LastModified, modified)
+	}
+	if b := newCalendarFeed([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), modified.Add(time.Hour)); b.ETag != a.ETag {
+		t.Errorf("the same content should have the same ETag: %s != %s", b.ETag, a.ETag)
+	}
+	if c := newCalendarFeed([]byte("BEGIN:VCALENDAR\r\nX-WR-CALNAME:x\r\nEND:VCALENDAR\r\n"), modified); c.ETag == a.ETag {
+		t.Errorf("different content should change the ETag: %s", c.ETag)
+	}
+}
@@ -0,0 +1,26 @@
+package application
+
+import (
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"context"
+	"fmt"
+	"time"
+)
+
+// GetCalendarFreeIntervals はカレンダーの予定から、範囲 [start, end) の空き時間を返す
+// userID（セッションのユーザー）が空でなければ、そのユーザーの繰り返しのルールと勤務時間の外の時間帯も除く
+func GetCalendarFreeIntervals(ctx context.Context, userID string, cal servise.CalendarProvider, start, end time.Time, durationMin int) ([]servise.TimeInterval, error) {
+	if userID != "" {
+		repo, err := repository.NewSupabaseRepository()
+		if err != nil {
+			return nil, fmt.Errorf("failed to init repository: %w", err)
+		}
+		schedule, err := loadWeeklySchedule(ctx, repo, userID)
+		if err != nil {
+			return nil, err
+		}
+		cal = servise.WithWeeklySchedule(cal, schedule)
+	}
+	return servise.GetFreeIntervals(ctx, cal, start, end, durationMin)
+}
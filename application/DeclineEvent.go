@@ -0,0 +1,140 @@
+package application
+
+import (
+	"adjuSche-back-end/domain"
+	"adjuSche-back-end/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxDeclineReasonLength は辞退の理由の最大文字数
+const maxDeclineReasonLength = 500
+
+// ErrAlreadyAccepted は参加を受諾済みの参加者が招待を辞退しようとしたことを表す
+var ErrAlreadyAccepted = domain.Conflict("already_accepted", "参加を受諾済みです。参加を取り消す場合は withdraw を使ってください")
+
+// DeclineEventInput は辞退・参加取り消しに必要な入力を表す
+// ログインユーザーは UserID（セッションのユーザー）、ゲストは GuestToken（編集用トークン）のどちらかを指定する
+type DeclineEventInput struct {
+	EventID    int64
+	UserID     string
+	GuestToken string
+	Reason     string // 任意
+}
+
+// DeclineEvent は招待を辞退する。まだ参加者として登録されていなければ辞退した参加者として登録する
+// 受諾済みの場合は WithdrawFromEvent を使う
+func DeclineEvent(ctx context.Context, in DeclineEventInput) error {
+	return leaveEvent(ctx, in, false)
+}
+
+// WithdrawFromEvent は受諾済みの参加を取り消す。提出済みの空き時間は削除し、候補日時の計算から外す
+// 取り消し済みの参加者がもう一度呼び出した場合は何もしない
+func WithdrawFromEvent(ctx context.Context, in DeclineEventInput) error {
+	return leaveEvent(ctx, in, true)
+}
+
+func leaveEvent(ctx context.Context, in DeclineEventInput, withdraw bool) error {
+	in.Reason = strings.TrimSpace(in.Reason)
+	verr := &validationErrors{}
+	if len([]rune(in.Reason)) > maxDeclineReasonLength {
+		verr.add("reason", FieldErrorTooLong, fmt.Sprintf("理由は%d文字以内で入力してください", maxDeclineReasonLength), maxDeclineReasonLength)
+	}
+	if err := verr.err(); err != nil {
+		return err
+	}
+
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	if in.GuestToken != "" {
+		guest, err := authenticateGuest(ctx, repo, in.EventID, in.GuestToken)
+		if err != nil {
+			return err
+		}
+		in.UserID = guest.UserID
+	}
+	if in.UserID == "" {
+		return ErrInvalidGuestToken.Wrapf("neither a session user nor a guest token was given")
+	}
+
+	ev, err := repo.GetEventByID(ctx, in.EventID)
+	if err != nil {
+		return err
+	}
+	if ev.Status == repository.EventStatusCanceled {
+		return ErrEventCanceled
+	}
+
+	p, err := repo.GetEventParticipant(ctx, in.EventID, in.UserID)
+	switch {
+	case err == nil:
+		if !withdraw && p.Status == repository.ParticipantStatusAccepted {
+			return ErrAlreadyAccepted
+		}
+		if withdraw && p.Status == repository.ParticipantStatusDeclined {
+			// 取り消し済み。再送されたリクエストでは辞退の日時を変えず、通知も繰り返さない
+			return nil
+		}
+	case errors.Is(err, repository.ErrParticipantNotFound) && !withdraw:
+		// 招待 URL を開いただけで、まだ参加者として登録されていない
+		p = &repository.EventParticipant{EventID: in.EventID, UserID: in.UserID}
+	default:
+		return err
+	}
+
+	now := time.Now()
+	p.Status = repository.ParticipantStatusDeclined
+	p.DeclineReason = sql.NullString{String: in.Reason, Valid: in.Reason != ""}
+	p.DeclinedAt = sql.NullTime{Time: now, Valid: true}
+
+	// ステータスの変更と空き時間の削除をまとめてコミットする
+	err = repo.UnitOfWork(ctx, func(tx *repository.SupabaseRepositoryImpl) error {
+		if err := tx.UpdateEventParticipant(ctx, p); err != nil {
+			return err
+		}
+		return tx.DeleteUserAvailabilitiesForEvent(ctx, in.EventID, in.UserID)
+	})
+	if err != nil {
+		return err
+	}
+
+	publishEventUpdated(in.EventID)
+	notifyParticipantDeclined(ev, in.UserID, in.Reason, withdraw)
+	return nil
+}
+
+// countDeclinedParticipants はイベントの辞退・参加を取り消した参加者の数を返す
+func countDeclinedParticipants(ctx context.Context, repo *repository.SupabaseRepositoryImpl, eventID int64) (int, error) {
+	counts, err := repo.CountEventParticipantsByStatus(ctx, []int64{eventID}, repository.ParticipantStatusDeclined)
+	if err != nil {
+		return 0, err
+	}
+	return counts[eventID], nil
+}
+
+// acceptEventParticipant はユーザーを参加を受諾した参加者として登録する
+// 辞退していた場合は、空き時間を提出し直したものとして受諾に戻す
+func acceptEventParticipant(ctx context.Context, repo *repository.SupabaseRepositoryImpl, eventID int64, userID string) (*repository.EventParticipant, error) {
+	p, err := repo.GetOrCreateEventParticipant(ctx, eventID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register event participant: %w", err)
+	}
+	if p.Status != repository.ParticipantStatusDeclined {
+		return p, nil
+	}
+
+	p.Status = repository.ParticipantStatusAccepted
+	p.DeclineReason = sql.NullString{}
+	p.DeclinedAt = sql.NullTime{}
+	if err := repo.UpdateEventParticipant(ctx, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
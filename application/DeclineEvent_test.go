@@ -0,0 +1,135 @@
+package application
+
+import (
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/repository/repositorytest"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+const testDeclineUserID = "decline-user"
+
+// expectDecline は参加者を辞退に更新し、同じトランザクションで空き時間を削除する
+func expectDecline(mock sqlmock.Sqlmock, userID, reason string) {
+	var wantReason driver.Value
+	if reason != "" {
+		wantReason = reason
+	}
+	mock.ExpectBegin()
+	// event_id, user_id, status, joined_at, decline_reason, declined_at, id の順
+	mock.ExpectExec(`UPDATE "EventParticipants" SET`).
+		WithArgs(int64(42), userID, repository.ParticipantStatusDeclined, sqlmock.AnyArg(), wantReason, recentTime{}, int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM "Availabilities" WHERE event_id = \$1 AND user_id = \$2`).
+		WithArgs(int64(42), userID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+	expectNoWebhooks(mock)
+}
+
+func TestDeclineEventRemovesAvailabilities(t *testing.T) {
+	mock := repositorytest.UseMock(t)
+	expectEvent(mock, 42, repository.EventStatusOpen)
+	expectParticipant(mock, 42, testDeclineUserID, repository.ParticipantStatusInvited)
+	expectDecline(mock, testDeclineUserID, "都合が合わない")
+
+	if err := DeclineEvent(context.Background(), DeclineEventInput{EventID: 42, UserID: testDeclineUserID, Reason: " 都合が合わない "}); err != nil {
+		t.Fatalf("DeclineEvent() error = %v", err)
+	}
+	waitForExpectations(t, mock)
+}
+
+func TestDeclineEventRejectsAcceptedParticipant(t *testing.T) {
+	mock := repositorytest.UseMock(t)
+	expectEvent(mock, 42, repository.EventStatusOpen)
+	expectParticipant(mock, 42, testDeclineUserID, repository.ParticipantStatusAccepted)
+
+	if err := DeclineEvent(context.Background(), DeclineEventInput{EventID: 42, UserID: testDeclineUserID}); !errors.Is(err, ErrAlreadyAccepted) {
+		t.Errorf("got %v, want ErrAlreadyAccepted", err)
+	}
+}
+
+func TestWithdrawFromEventIsIdempotent(t *testing.T) {
+	mock := repositorytest.UseMock(t)
+	expectEvent(mock, 42, repository.EventStatusOpen)
+	expectParticipant(mock, 42, testDeclineUserID, repository.ParticipantStatusAccepted)
+	expectDecline(mock, testDeclineUserID, "")
+
+	in := DeclineEventInput{EventID: 42, UserID: testDeclineUserID}
+	if err := WithdrawFromEvent(context.Background(), in); err != nil {
+		t.Fatalf("first WithdrawFromEvent() error = %v", err)
+	}
+	waitForExpectations(t, mock)
+
+	// 2 回目は取り消し済みのため、更新も削除も通知もしない
+	expectEvent(mock, 42, repository.EventStatusOpen)
+	expectParticipant(mock, 42, testDeclineUserID, repository.ParticipantStatusDeclined)
+	if err := WithdrawFromEvent(context.Background(), in); err != nil {
+		t.Fatalf("second WithdrawFromEvent() error = %v", err)
+	}
+}
+
+func TestDeclineEventWithGuestToken(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+	mock := repositorytest.UseMock(t)
+	mock.ExpectQuery(`SELECT \* FROM "EventGuests" WHERE event_id = \$1 AND user_id = \$2`).
+		WithArgs(int64(42), testGuestID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "user_id", "display_name", "email", "created_at"}).
+			AddRow(5, 42, testGuestID, "佐藤", nil, time.Now()))
+	expectEvent(mock, 42, repository.EventStatusOpen)
+	// ゲストのユーザーIDで参加者を探し、辞退にする
+	expectParticipant(mock, 42, testGuestID, repository.ParticipantStatusInvited)
+	expectDecline(mock, testGuestID, "")
+
+	if err := DeclineEvent(context.Background(), DeclineEventInput{EventID: 42, GuestToken: issueTestGuestToken(t, 42)}); err != nil {
+		t.Fatalf("DeclineEvent() error = %v", err)
+	}
+	waitForExpectations(t, mock)
+}
+
+func TestDeclineEventRejectsGuestTokenForAnotherEvent(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+	repositorytest.UseMock(t)
+
+	err := DeclineEvent(context.Background(), DeclineEventInput{EventID: 42, GuestToken: issueTestGuestToken(t, 41)})
+	if !errors.Is(err, ErrInvalidGuestToken) {
+		t.Errorf("got %v, want ErrInvalidGuestToken", err)
+	}
+}
+
+func TestEventProgressCountsDeclinedParticipantsSeparately(t *testing.T) {
+	mock := repositorytest.UseMock(t)
+	expectEvent(mock, 42, repository.EventStatusOpen)
+	mock.ExpectQuery(`SELECT \* FROM "EventConditions" WHERE event_id = \$1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "period_start", "period_end", "time_type", "time_start", "time_end", "duration_min", "created_at"}).
+			AddRow(1, 42, at(0, 0), at(23, 59), 0, nil, nil, 60, time.Now()))
+	// 辞退した参加者の空き時間は辞退と同時に削除されているため、回答者は残った 2 人だけになる
+	mock.ExpectQuery(`SELECT \* FROM "Availabilities" WHERE event_id = \$1`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "user_id", "available_date", "available_start", "available_end", "sourse", "created_at"}).
+			AddRow(1, 42, "p1", "2026-11-02", at(9, 0).Format(time.RFC3339), at(11, 0).Format(time.RFC3339), 1, time.Now()).
+			AddRow(2, 42, "p2", "2026-11-02", at(10, 0).Format(time.RFC3339), at(12, 0).Format(time.RFC3339), 1, time.Now()))
+	mock.ExpectQuery(`SELECT COUNT\(DISTINCT user_id\) AS cnt FROM "Availabilities" WHERE event_id = \$1`).
+		WillReturnRows(sqlmock.NewRows([]string{"cnt"}).AddRow(2))
+	mock.ExpectQuery(`SELECT event_id, COUNT\(\*\) AS cnt FROM "EventParticipants" WHERE event_id IN \(\$1\) AND status = \$2`).
+		WithArgs(int64(42), repository.ParticipantStatusDeclined).
+		WillReturnRows(sqlmock.NewRows([]string{"event_id", "cnt"}).AddRow(42, 1))
+	mock.ExpectQuery(`SELECT \* FROM "Users" WHERE id IN`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT \* FROM "EventGuests" WHERE event_id = \$1`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		t.Fatal(err)
+	}
+	progress, err := getEventProgress(context.Background(), repo, 42)
+	if err != nil {
+		t.Fatalf("getEventProgress() error = %v", err)
+	}
+	if progress.VotedCount != 2 || progress.DeclinedCount != 1 {
+		t.Errorf("voted = %d, declined = %d, want 2 and 1", progress.VotedCount, progress.DeclinedCount)
+	}
+}
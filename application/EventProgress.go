@@ -0,0 +1,128 @@
+package application
+
+import (
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// EventProgress は投票の途中経過（投票済み・辞退した人数と現在の候補日時）
+type EventProgress struct {
+	EventID       int64
+	VotedCount    int
+	DeclinedCount int
+	Slots         []PossibleSlot
+}
+
+// eventHub は空き時間が更新されたイベントを SSE の購読者に伝える
+var eventHub = servise.NewEventHub()
+
+// usePostgresEventHub は更新通知を Postgres の LISTEN/NOTIFY で配るかどうかを返す
+// 複数台で動かす場合は EVENT_HUB=postgres を設定し、どのサーバーで保存しても全サーバーの購読者に届くようにする
+func usePostgresEventHub() bool {
+	return os.Getenv("EVENT_HUB") == "postgres"
+}
+
+// StartEventUpdateListener は EVENT_HUB=postgres のとき、他のサーバーからの更新通知の受信を始める
+// ctx が終了するまでバックグラウンドで受信を続ける。サーバーの起動時に 1 回だけ呼び出す
+func StartEventUpdateListener(ctx context.Context) {
+	if !usePostgresEventHub() {
+		return
+	}
+	go repository.ListenEventUpdates(ctx, eventHub.Publish)
+}
+
+// EventProgressStream は 1 つの SSE の接続でのイベントの途中経過の購読
+// リポジトリは共有のコネクションプールを使うため、購読の数だけ接続が増えることはない
+type EventProgressStream struct {
+	EventID int64
+	Updates <-chan struct{} // イベントの空き時間が更新されるたびに通知される
+
+	repo        *repository.SupabaseRepositoryImpl
+	unsubscribe func()
+}
+
+// OpenEventProgressStream はイベントの途中経過の購読を始める。使い終えたら Close を呼び出す
+// 初期状態を読む前に購読するため、その間に保存された更新も Updates に届く
+func OpenEventProgressStream(eventID int64) (*EventProgressStream, error) {
+	updates, unsubscribe := eventHub.Subscribe(eventID)
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		unsubscribe()
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+	return &EventProgressStream{EventID: eventID, Updates: updates, repo: repo, unsubscribe: unsubscribe}, nil
+}
+
+// Progress はイベントの現在の途中経過を返す
+func (s *EventProgressStream) Progress(ctx context.Context) (EventProgress, error) {
+	return getEventProgress(ctx, s.repo, s.EventID)
+}
+
+// Close は購読をやめる
+func (s *EventProgressStream) Close() {
+	s.unsubscribe()
+}
+
+// getEventProgress はイベントの投票済みの人数と、提出済みの空き時間から求めた候補日時を返す
+func getEventProgress(ctx context.Context, repo *repository.SupabaseRepositoryImpl, eventID int64) (EventProgress, error) {
+	if _, err := repo.GetEventByID(ctx, eventID); err != nil {
+		return EventProgress{}, err
+	}
+	cond, err := repo.GetEventConditionByEventID(ctx, eventID)
+	if err != nil {
+		return EventProgress{}, err
+	}
+	avs, err := repo.ListAvailabilitiesByEventID(ctx, eventID)
+	if err != nil {
+		return EventProgress{}, err
+	}
+	voted, err := repo.CountDistinctAvailabilityUsersByEventID(ctx, eventID)
+	if err != nil {
+		return EventProgress{}, err
+	}
+
+	declined, err := countDeclinedParticipants(ctx, repo, eventID)
+	if err != nil {
+		return EventProgress{}, err
+	}
+
+	slots := calculateOverlappingSlots(avs, cond.DurationMin)
+	if err := nameSlotMembers(ctx, repo, eventID, slots); err != nil {
+		return EventProgress{}, err
+	}
+	return EventProgress{
+		EventID:       eventID,
+		VotedCount:    voted,
+		DeclinedCount: declined,
+		Slots:         slots,
+	}, nil
+}
+
+// publishEventUpdated はイベントの空き時間がコミットされたことを購読者に伝える
+// EVENT_HUB=postgres のときは NOTIFY し、自分を含む全サーバーが LISTEN で受け取る
+func publishEventUpdated(eventID int64) {
+	if !usePostgresEventHub() {
+		eventHub.Publish(eventID)
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		repo, err := repository.NewSupabaseRepository()
+		if err == nil {
+			err = repo.NotifyEventUpdated(ctx, eventID)
+		}
+		if err != nil {
+			// 少なくともこのサーバーの購読者には届ける
+			log.Printf("イベント更新の NOTIFY に失敗しました: eventID=%d: %v", eventID, err)
+			eventHub.Publish(eventID)
+		}
+	}()
+}
@@ -0,0 +1,214 @@
+package application
+
+import (
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// 表の見出しと参加可否の記号
+const (
+	matrixParticipantHeader = "参加者"
+	matrixCountLabel        = "参加可能人数"
+	matrixAvailable         = "○"
+	matrixUnavailable       = "×"
+)
+
+// AvailabilityMatrix は参加者 × 候補日時の参加可否の表を表す
+type AvailabilityMatrix struct {
+	EventTitle string
+	Slots      []PossibleSlot
+	Rows       []AvailabilityMatrixRow
+}
+
+// AvailabilityMatrixRow は参加者 1 人分の行を表す。Available[i] は Slots[i] に参加できるか
+type AvailabilityMatrixRow struct {
+	UserID      string
+	DisplayName string // プロフィールの表示名（なければユーザーID）
+	Available   []bool
+}
+
+// BuildAvailabilityMatrix はイベントの参加者と候補日時から参加可否の表を作る（主催者のみ）
+// 候補日時は ExportCandidatesICS と同じく ListAvailabilitiesByEventID から求め、空き時間を登録していない参加者はすべて × になる
+func BuildAvailabilityMatrix(ctx context.Context, eventID int64, userID string) (*AvailabilityMatrix, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	ev, err := getEventAsHost(ctx, repo, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	cond, err := repo.GetEventConditionByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	avs, err := repo.ListAvailabilitiesByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	participants, err := repo.ListEventParticipantsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := matrixUserIDs(participants, avs)
+	names, err := memberNames(ctx, repo, eventID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return newAvailabilityMatrix(ev.Title, userIDs, avs, cond.DurationMin, names), nil
+}
+
+// matrixUserIDs は表の行に並べる回答者を返す
+// 参加者の順に並べ、参加者として登録されていない回答者は最後に加える。辞退した参加者は空き時間も削除済みのため含めない
+func matrixUserIDs(participants []repository.EventParticipant, avs []repository.Availability) []string {
+	var userIDs []string
+	seen := make(map[string]bool)
+	addUser := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			userIDs = append(userIDs, id)
+		}
+	}
+	for _, p := range participants {
+		if p.Status == repository.ParticipantStatusDeclined {
+			continue
+		}
+		addUser(p.UserID)
+	}
+	for _, av := range avs {
+		addUser(av.UserID)
+	}
+	return userIDs
+}
+
+// newAvailabilityMatrix は空き時間から候補日時を求め、userIDs の順に参加可否の行を作る
+// names にない回答者はユーザーIDを表示名にする
+func newAvailabilityMatrix(title string, userIDs []string, avs []repository.Availability, durationMin int, names map[string]string) *AvailabilityMatrix {
+	intervals := make(map[string][]TimeSlot)
+	for _, av := range avs {
+		start, serr := time.Parse(time.RFC3339, av.AvailableStart)
+		end, eerr := time.Parse(time.RFC3339, av.AvailableEnd)
+		if serr != nil || eerr != nil {
+			continue
+		}
+		intervals[av.UserID] = append(intervals[av.UserID], TimeSlot{Start: start, End: end})
+	}
+
+	m := &AvailabilityMatrix{
+		EventTitle: title,
+		Slots:      calculateOverlappingSlots(avs, durationMin),
+	}
+	setSlotMemberNames(m.Slots, names)
+	for _, id := range userIDs {
+		name, ok := names[id]
+		if !ok {
+			name = id
+		}
+		row := AvailabilityMatrixRow{UserID: id, DisplayName: name, Available: make([]bool, len(m.Slots))}
+		for i, s := range m.Slots {
+			row.Available[i] = coversSlot(intervals[id], s.PeriodStart, s.PeriodEnd)
+		}
+		m.Rows = append(m.Rows, row)
+	}
+	return m
+}
+
+// Table は表を見出し行・参加者の行・参加可能人数の行からなる文字列の表にする（CSV / XLSX の書き出し用）
+func (m *AvailabilityMatrix) Table(loc *time.Location) [][]string {
+	header := []string{matrixParticipantHeader}
+	for _, s := range m.Slots {
+		header = append(header, formatSlotLabel(s.PeriodStart.In(loc), s.PeriodEnd.In(loc)))
+	}
+	table := [][]string{header}
+
+	counts := make([]int, len(m.Slots))
+	for _, r := range m.Rows {
+		row := []string{r.DisplayName}
+		for i, ok := range r.Available {
+			if ok {
+				row = append(row, matrixAvailable)
+				counts[i]++
+			} else {
+				row = append(row, matrixUnavailable)
+			}
+		}
+		table = append(table, row)
+	}
+
+	footer := []string{matrixCountLabel}
+	for _, n := range counts {
+		footer = append(footer, strconv.Itoa(n))
+	}
+	return append(table, footer)
+}
+
+// ExportAvailabilityCSV は参加可否の表を UTF-8 BOM 付きの CSV にして返す
+func ExportAvailabilityCSV(ctx context.Context, eventID int64, userID string) ([]byte, error) {
+	_, table, err := availabilityTable(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := servise.WriteCSV(&buf, table); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ExportAvailabilityXLSX は参加可否の表を .xlsx にして返す
+// シート名にはイベントのタイトルを使う
+func ExportAvailabilityXLSX(ctx context.Context, eventID int64, userID string) ([]byte, error) {
+	m, table, err := availabilityTable(ctx, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return servise.BuildXLSX(m.EventTitle, table)
+}
+
+func availabilityTable(ctx context.Context, eventID int64, userID string) (*AvailabilityMatrix, [][]string, error) {
+	m, err := BuildAvailabilityMatrix(ctx, eventID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc, err := time.LoadLocation(servise.ICalTimeZone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load time zone: %w", err)
+	}
+	return m, m.Table(loc), nil
+}
+
+// coversSlot は空き時間が [start, end) 全体を覆っているかを返す（隣り合う空き時間はつなげて扱う）
+func coversSlot(slots []TimeSlot, start, end time.Time) bool {
+	sorted := append([]TimeSlot{}, slots...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	covered := start
+	for _, s := range sorted {
+		if s.Start.After(covered) {
+			break
+		}
+		if s.End.After(covered) {
+			covered = s.End
+		}
+		if !covered.Before(end) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatSlotLabel は候補日時の見出しを "2026/10/19 10:00-11:00" の形式で返す（日をまたぐ場合は終了日も書く）
+func formatSlotLabel(start, end time.Time) string {
+	if start.Format("2006/01/02") == end.Format("2006/01/02") {
+		return start.Format("2006/01/02 15:04") + "-" + end.Format("15:04")
+	}
+	return start.Format("2006/01/02 15:04") + "-" + end.Format("2006/01/02 15:04")
+}
@@ -0,0 +1,87 @@
+package application
+
+import (
+	"adjuSche-back-end/repository"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCoversSlot(t *testing.T) {
+	slots := []TimeSlot{
+		{Start: at(13, 0), End: at(14, 0)},
+		{Start: at(9, 0), End: at(10, 0)},
+		{Start: at(10, 0), End: at(11, 30)}, // 9:00-10:00 と接している
+	}
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       bool
+	}{
+		{"inside one interval", at(9, 15), at(9, 45), true},
+		{"across adjacent intervals", at(9, 30), at(11, 0), true},
+		{"exact bounds", at(13, 0), at(14, 0), true},
+		{"gap in the middle", at(11, 0), at(13, 30), false},
+		{"starts before availability", at(8, 30), at(9, 30), false},
+		{"ends after availability", at(13, 30), at(14, 30), false},
+	}
+	for _, tt := range tests {
+		if got := coversSlot(slots, tt.start, tt.end); got != tt.want {
+			t.Errorf("%s: coversSlot(%s, %s) = %v, want %v", tt.name, tt.start.Format("15:04"), tt.end.Format("15:04"), got, tt.want)
+		}
+	}
+	if coversSlot(nil, at(9, 0), at(10, 0)) {
+		t.Error("no availability should not cover any slot")
+	}
+}
+
+func TestMatrixUserIDs(t *testing.T) {
+	participants := []repository.EventParticipant{
+		{UserID: "p1", Status: repository.ParticipantStatusAccepted},
+		{UserID: "declined", Status: repository.ParticipantStatusDeclined},
+		{UserID: "p2", Status: repository.ParticipantStatusAccepted},
+	}
+	avs := []repository.Availability{
+		testAvailability("p2", repository.AvailabilitySourceGoogleCalendar, at(9, 0), at(10, 0)),
+		testAvailability("respondent", repository.AvailabilitySourceManual, at(9, 0), at(10, 0)),
+	}
+	want := []string{"p1", "p2", "respondent"}
+	if got := matrixUserIDs(participants, avs); !slices.Equal(got, want) {
+		t.Errorf("matrixUserIDs = %v, want %v", got, want)
+	}
+}
+
+func TestAvailabilityMatrixTable(t *testing.T) {
+	avs := []repository.Availability{
+		testAvailability("a", repository.AvailabilitySourceGoogleCalendar, at(9, 0), at(11, 0)),
+		testAvailability("b", repository.AvailabilitySourceGoogleCalendar, at(10, 0), at(11, 0)),
+	}
+	m := newAvailabilityMatrix("定例", []string{"a", "b", "nobody"}, avs, 60, map[string]string{"a": "佐藤"})
+
+	want := [][]string{
+		{"参加者", "2026/11/02 09:00-11:00", "2026/11/02 10:00-11:00"},
+		{"佐藤", "○", "○"},
+		{"b", "×", "○"},
+		{"nobody", "×", "×"},
+		{"参加可能人数", "1", "2"},
+	}
+	got := m.Table(testLoc)
+	if len(got) != len(want) {
+		t.Fatalf("table = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !slices.Equal(got[i], want[i]) {
+			t.Errorf("row %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if m.EventTitle != "定例" || !slices.Equal(m.Slots[1].AvailableMembers, []string{"佐藤", unnamedMemberName}) {
+		t.Errorf("matrix = %+v", m)
+	}
+}
+
+func TestFormatSlotLabelAcrossMidnight(t *testing.T) {
+	start := time.Date(2026, 11, 2, 23, 0, 0, 0, testLoc)
+	if got, want := formatSlotLabel(start, start.Add(2*time.Hour)), "2026/11/02 23:00-2026/11/03 01:00"; got != want {
+		t.Errorf("formatSlotLabel = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,128 @@
+package application
+
+import (
+	"adjuSche-back-end/domain"
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"context"
+	"fmt"
+	"time"
+)
+
+// ErrEventNotFinalized は日程が確定していないイベントの .ics を求められたことを表す
+var ErrEventNotFinalized = domain.Conflict("event_not_finalized", "日程が確定していないイベントです")
+
+// icalUIDDomain は .ics の UID の右辺に使うドメイン
+const icalUIDDomain = "adjusche"
+
+// ExportEventICS は確定したイベントを参加者付きの VEVENT 1 件の .ics にして返す
+// 中止されたイベントは STATUS:CANCELLED として返し、取り込み済みのカレンダーに中止を反映できるようにする
+func ExportEventICS(ctx context.Context, eventID int64) ([]byte, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	ev, err := repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !ev.DecidedStart.Valid || !ev.DecidedEnd.Valid {
+		return nil, ErrEventNotFinalized
+	}
+
+	participants, err := repo.ListEventParticipantsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	cal := servise.ICalendar{
+		Name:   ev.Title,
+		Events: []servise.ICalEvent{newFinalizedICalEvent(ev, participants)},
+	}
+	return servise.BuildICalendar(cal, time.Now()), nil
+}
+
+// ExportCandidatesICS は現在の候補日時を仮予定 (TENTATIVE) の VEVENT として並べた .ics を返す
+func ExportCandidatesICS(ctx context.Context, eventID int64) ([]byte, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	ev, err := repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if ev.Status == repository.EventStatusCanceled {
+		return nil, ErrEventCanceled
+	}
+
+	cond, err := repo.GetEventConditionByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	avs, err := repo.ListAvailabilitiesByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := calculateOverlappingSlots(avs, cond.DurationMin)
+
+	cal := servise.ICalendar{Name: ev.Title}
+	for _, s := range slots {
+		cal.Events = append(cal.Events, servise.ICalEvent{
+			UID:         fmt.Sprintf("event-%d-candidate-%d@%s", ev.ID, s.PeriodStart.Unix(), icalUIDDomain),
+			Summary:     fmt.Sprintf("【候補】%s", ev.Title),
+			Description: fmt.Sprintf("日程調整中の候補日時です（%d人が参加可能）", s.ParticipateMemberNum),
+			Start:       s.PeriodStart,
+			End:         s.PeriodEnd,
+			Status:      servise.ICalStatusTentative,
+			Organizer:   userCalAddress(ev.HostUserID),
+		})
+	}
+	return servise.BuildICalendar(cal, time.Now()), nil
+}
+
+// newFinalizedICalEvent は確定したイベントを VEVENT に変換する
+func newFinalizedICalEvent(ev *repository.Events, participants []repository.EventParticipant) servise.ICalEvent {
+	status := servise.ICalStatusConfirmed
+	if ev.Status == repository.EventStatusCanceled {
+		status = servise.ICalStatusCancelled
+	}
+
+	ie := servise.ICalEvent{
+		UID:         fmt.Sprintf("event-%d@%s", ev.ID, icalUIDDomain),
+		Summary:     ev.Title,
+		Description: ev.Note.String,
+		Start:       ev.DecidedStart.Time,
+		End:         ev.DecidedEnd.Time,
+		Status:      status,
+		Organizer:   userCalAddress(ev.HostUserID),
+		Updated:     ev.UpdatedAt,
+	}
+	for _, p := range participants {
+		ie.Attendees = append(ie.Attendees, servise.ICalAttendee{
+			Address:  userCalAddress(p.UserID),
+			PartStat: participantPartStat(p.Status),
+		})
+	}
+	return ie
+}
+
+// userCalAddress はユーザーを表す ATTENDEE / ORGANIZER の URI を返す（メールアドレスは保持していないため UUID を使う）
+func userCalAddress(userID string) string {
+	return "urn:uuid:" + userID
+}
+
+func participantPartStat(status int8) string {
+	switch status {
+	case repository.ParticipantStatusAccepted:
+		return servise.ICalPartStatAccepted
+	case repository.ParticipantStatusDeclined:
+		return servise.ICalPartStatDeclined
+	default:
+		return servise.ICalPartStatNeedsAction
+	}
+}
@@ -0,0 +1,165 @@
+package application
+
+import (
+	"adjuSche-back-end/domain"
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ゲストの入力の上限
+const (
+	maxDisplayNameLength = 50 // ログインユーザーのプロフィールの表示名と共通
+	maxGuestEmailLength  = 254
+)
+
+// ErrInvalidGuestToken はゲストの編集用トークンが無効（改ざん・別のイベント・削除済みのゲスト）であることを表す
+var ErrInvalidGuestToken = domain.Unauthorized("invalid_guest_token", "ゲストのトークンが無効です")
+
+// JoinAsGuestInput はゲストとしての参加に必要な入力を表す
+type JoinAsGuestInput struct {
+	EventID     int64
+	DisplayName string
+	Email       string // 任意
+}
+
+// GuestSession は参加したゲストと、以後の編集に使うトークン
+type GuestSession struct {
+	Guest repository.EventGuest
+	Token string
+}
+
+// JoinEventAsGuest はアカウントを持たない人を、表示名（と任意のメールアドレス）だけでイベントの参加者にする
+// ゲストには UUID を発行し、ログインユーザーと同じく EventParticipants / Availabilities に記録する
+func JoinEventAsGuest(ctx context.Context, in JoinAsGuestInput) (*GuestSession, error) {
+	in.DisplayName = strings.TrimSpace(in.DisplayName)
+	in.Email = strings.TrimSpace(in.Email)
+	verr := &validationErrors{}
+	if in.DisplayName == "" {
+		verr.add("displayName", FieldErrorRequired, "表示名を入力してください")
+	} else if len([]rune(in.DisplayName)) > maxDisplayNameLength {
+		verr.add("displayName", FieldErrorTooLong, fmt.Sprintf("表示名は%d文字以内で入力してください", maxDisplayNameLength), maxDisplayNameLength)
+	}
+	if in.Email != "" {
+		if addr, err := mail.ParseAddress(in.Email); err != nil || addr.Address != in.Email || len(in.Email) > maxGuestEmailLength {
+			verr.add("email", FieldErrorInvalidFormat, "メールアドレスの形式が正しくありません")
+		}
+	}
+	if err := verr.err(); err != nil {
+		return nil, err
+	}
+
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	ev, err := repo.GetEventByID(ctx, in.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if ev.Status == repository.EventStatusCanceled {
+		return nil, ErrEventCanceled
+	}
+
+	now := time.Now()
+	guest := repository.EventGuest{
+		EventID:     in.EventID,
+		UserID:      uuid.NewString(),
+		DisplayName: in.DisplayName,
+		Email:       sql.NullString{String: in.Email, Valid: in.Email != ""},
+		CreatedAt:   now,
+	}
+	token, err := servise.IssueGuestToken(in.EventID, guest.UserID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	err = repo.UnitOfWork(ctx, func(tx *repository.SupabaseRepositoryImpl) error {
+		if err := tx.CreateEventGuest(ctx, &guest); err != nil {
+			return err
+		}
+		_, err := acceptEventParticipant(ctx, tx, in.EventID, guest.UserID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &GuestSession{Guest: guest, Token: token}, nil
+}
+
+// GuestDetail はゲストと、ゲストが登録した空き時間
+type GuestDetail struct {
+	Guest          repository.EventGuest
+	Availabilities []servise.TimeInterval
+}
+
+// GetGuest は編集用トークンのゲストと、登録済みの空き時間を返す
+func GetGuest(ctx context.Context, eventID int64, token string) (*GuestDetail, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	guest, err := authenticateGuest(ctx, repo, eventID, token)
+	if err != nil {
+		return nil, err
+	}
+	avs, err := repo.ListUserAvailabilitiesForEvent(ctx, eventID, guest.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &GuestDetail{Guest: *guest, Availabilities: make([]servise.TimeInterval, 0, len(avs))}
+	for _, av := range avs {
+		start, serr := time.Parse(time.RFC3339, av.AvailableStart)
+		end, eerr := time.Parse(time.RFC3339, av.AvailableEnd)
+		if serr != nil || eerr != nil {
+			continue
+		}
+		detail.Availabilities = append(detail.Availabilities, servise.TimeInterval{Start: start, End: end})
+	}
+	return detail, nil
+}
+
+// SubmitGuestAvailability はゲストの手入力の空き時間を intervals で置き換える
+func SubmitGuestAvailability(ctx context.Context, eventID int64, token string, intervals []ManualInterval) ([]servise.TimeInterval, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	guest, err := authenticateGuest(ctx, repo, eventID, token)
+	if err != nil {
+		return nil, err
+	}
+	return saveManualAvailability(ctx, repo, eventID, guest.UserID, intervals)
+}
+
+// authenticateGuest は編集用トークンを検証し、eventID のゲストを返す
+func authenticateGuest(ctx context.Context, repo *repository.SupabaseRepositoryImpl, eventID int64, token string) (*repository.EventGuest, error) {
+	claims, err := servise.ParseGuestToken(token)
+	if err != nil {
+		return nil, ErrInvalidGuestToken.Wrap(err)
+	}
+	if claims.EventID != eventID {
+		return nil, ErrInvalidGuestToken.Wrapf("guest token is for event %d, not %d", claims.EventID, eventID)
+	}
+
+	guest, err := repo.GetEventGuest(ctx, eventID, claims.GuestID)
+	if err != nil {
+		if errors.Is(err, repository.ErrGuestNotFound) {
+			return nil, ErrInvalidGuestToken.Wrap(err)
+		}
+		return nil, err
+	}
+	return guest, nil
+}
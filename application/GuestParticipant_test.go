@@ -0,0 +1,130 @@
+package application
+
+import (
+	"adjuSche-back-end/domain"
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/repository/repositorytest"
+	"adjuSche-back-end/servise"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+const testGuestID = "0b6f7c1e-3a52-4d7a-9d6e-2f1c8a4b5e60"
+
+func TestJoinEventAsGuestValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		in   JoinAsGuestInput
+		want []string // "<項目>:<コード>"
+	}{
+		{"missing display name", JoinAsGuestInput{DisplayName: "  "}, []string{"displayName:" + FieldErrorRequired}},
+		{"display name too long", JoinAsGuestInput{DisplayName: strings.Repeat("あ", maxDisplayNameLength+1)}, []string{
+			"displayName:" + FieldErrorTooLong,
+		}},
+		{"invalid email", JoinAsGuestInput{DisplayName: "佐藤", Email: "sato@"}, []string{"email:" + FieldErrorInvalidFormat}},
+		{"email with a name", JoinAsGuestInput{DisplayName: "佐藤", Email: "Sato <sato@example.com>"}, []string{
+			"email:" + FieldErrorInvalidFormat,
+		}},
+		{"email too long", JoinAsGuestInput{DisplayName: "佐藤", Email: strings.Repeat("a", maxGuestEmailLength) + "@example.com"}, []string{
+			"email:" + FieldErrorInvalidFormat,
+		}},
+		{"both", JoinAsGuestInput{Email: "not an address"}, []string{
+			"displayName:" + FieldErrorRequired, "email:" + FieldErrorInvalidFormat,
+		}},
+	}
+	for _, tt := range tests {
+		tt.in.EventID = 42
+		_, err := JoinEventAsGuest(context.Background(), tt.in)
+		var derr *domain.Error
+		if !errors.As(err, &derr) || derr.Kind != domain.KindValidation {
+			t.Errorf("%s: got %v, want a validation error", tt.name, err)
+			continue
+		}
+		var got []string
+		for _, f := range derr.Fields {
+			got = append(got, f.Field+":"+f.Code)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got fields %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func issueTestGuestToken(t *testing.T, eventID int64) string {
+	t.Helper()
+	token, err := servise.IssueGuestToken(eventID, testGuestID, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestAuthenticateGuestRoundTrip(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+	mock := repositorytest.UseMock(t)
+	mock.ExpectQuery(`SELECT \* FROM "EventGuests" WHERE event_id = \$1 AND user_id = \$2`).
+		WithArgs(int64(42), testGuestID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "user_id", "display_name", "email", "created_at"}).
+			AddRow(5, 42, testGuestID, "佐藤", nil, time.Now()))
+
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		t.Fatal(err)
+	}
+	guest, err := authenticateGuest(context.Background(), repo, 42, issueTestGuestToken(t, 42))
+	if err != nil {
+		t.Fatalf("authenticateGuest() error = %v", err)
+	}
+	if guest.UserID != testGuestID || guest.DisplayName != "佐藤" {
+		t.Errorf("guest = %+v", guest)
+	}
+}
+
+func TestAuthenticateGuestRejectsInvalidTokens(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+	session, _, err := servise.IssueSessionToken(testGuestID, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"another event", issueTestGuestToken(t, 41)},
+		{"session token", session},
+		{"tampered", issueTestGuestToken(t, 42) + "x"},
+	}
+	for _, tt := range tests {
+		// トークンを検証できない場合はデータベースを読まない
+		repositorytest.UseMock(t)
+		repo, err := repository.NewSupabaseRepository()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := authenticateGuest(context.Background(), repo, 42, tt.token); !errors.Is(err, ErrInvalidGuestToken) {
+			t.Errorf("%s: got %v, want ErrInvalidGuestToken", tt.name, err)
+		}
+	}
+}
+
+func TestAuthenticateGuestRejectsDeletedGuest(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+	mock := repositorytest.UseMock(t)
+	mock.ExpectQuery(`SELECT \* FROM "EventGuests"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := authenticateGuest(context.Background(), repo, 42, issueTestGuestToken(t, 42)); !errors.Is(err, ErrInvalidGuestToken) {
+		t.Errorf("got %v, want ErrInvalidGuestToken", err)
+	}
+}
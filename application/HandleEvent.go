@@ -21,15 +21,24 @@ type CreateEventInput struct {
 	DurationMin      int
 }
 
-// CreateEventAndCondition は Events と EventConditions を作成し、作成したイベントIDを返す
+// CreateEventAndCondition は Events と EventConditions を 1 つのトランザクションで作成し、作成したイベントIDを返す
+// 入力はすべて DB に触れる前に検証するため、不正な入力で下書きイベントだけが残ることはない
 func CreateEventAndCondition(ctx context.Context, in CreateEventInput) (int64, error) {
+	if err := ValidateCreateEventInput(in); err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	cond, err := buildEventCondition(0, in.PeriodStart, in.PeriodEnd, in.TimeStart, in.TimeEnd, in.DurationMin, now)
+	if err != nil {
+		return 0, err
+	}
+
 	repo, err := repository.NewSupabaseRepository()
 	if err != nil {
 		return 0, fmt.Errorf("failed to init repository: %w", err)
 	}
 
-	now := time.Now()
-
 	ev := &repository.Events{
 		HostUserID:       in.HostUserID,
 		Title:            in.Title,
@@ -39,48 +48,56 @@ func CreateEventAndCondition(ctx context.Context, in CreateEventInput) (int64, e
 		CreatedAt:        now,
 		UpdatedAt:        now,
 	}
-	if err := repo.CreateEvent(ctx, ev); err != nil {
+	err = repo.UnitOfWork(ctx, func(tx *repository.SupabaseRepositoryImpl) error {
+		if err := tx.CreateEvent(ctx, ev); err != nil {
+			return err
+		}
+		cond.EventID = ev.ID
+		return tx.CreateEventCondition(ctx, cond)
+	})
+	if err != nil {
 		return 0, err
 	}
 
+	notifyEventCreated(ev, cond)
+	return ev.ID, nil
+}
+
+// buildEventCondition は入力文字列から EventCondition を組み立てる
+func buildEventCondition(eventID int64, periodStart, periodEnd, timeStart, timeEnd string, durationMin int, now time.Time) (*repository.EventCondition, error) {
 	// 期間のパース（RFC3339 もしくは日付のみ 2006-01-02 を許容）
-	ps, err := parseRFC3339OrDate(in.PeriodStart)
+	ps, err := parseRFC3339OrDate(periodStart)
 	if err != nil {
-		return 0, fmt.Errorf("invalid periodStart: %w", err)
+		return nil, fmt.Errorf("invalid periodStart: %w", err)
 	}
-	pe, err := parseRFC3339OrDate(in.PeriodEnd)
+	pe, err := parseRFC3339OrDate(periodEnd)
 	if err != nil {
-		return 0, fmt.Errorf("invalid periodEnd: %w", err)
+		return nil, fmt.Errorf("invalid periodEnd: %w", err)
 	}
 
 	// time_type 判定: デフォルトは all_day(4)、開始/終了が指定されれば custom(3)
 	timeType := 4
 	var tStart, tEnd sql.NullString
-	if in.TimeStart != "" || in.TimeEnd != "" {
+	if timeStart != "" || timeEnd != "" {
 		timeType = 3
-		if in.TimeStart != "" {
-			tStart = sql.NullString{String: in.TimeStart, Valid: true}
+		if timeStart != "" {
+			tStart = sql.NullString{String: timeStart, Valid: true}
 		}
-		if in.TimeEnd != "" {
-			tEnd = sql.NullString{String: in.TimeEnd, Valid: true}
+		if timeEnd != "" {
+			tEnd = sql.NullString{String: timeEnd, Valid: true}
 		}
 	}
 
-	cond := &repository.EventCondition{
-		EventID:     ev.ID,
+	return &repository.EventCondition{
+		EventID:     eventID,
 		PeriodStart: ps,
 		PeriodEnd:   pe,
 		TimeType:    timeType,
 		TimeStart:   tStart,
 		TimeEnd:     tEnd,
-		DurationMin: in.DurationMin,
+		DurationMin: durationMin,
 		CreatedAt:   now,
-	}
-	if err := repo.CreateEventCondition(ctx, cond); err != nil {
-		return 0, err
-	}
-
-	return ev.ID, nil
+	}, nil
 }
 
 func parseRFC3339OrDate(value string) (time.Time, error) {
@@ -0,0 +1,53 @@
+package application
+
+import (
+	"adjuSche-back-end/repository/repositorytest"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestCreateEventAndConditionRollsBackEventWhenConditionFails(t *testing.T) {
+	mock := repositorytest.UseMock(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "Events"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(77))
+	mock.ExpectQuery(`INSERT INTO "EventConditions"`).
+		WithArgs(int64(77), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnError(errors.New("violates check constraint"))
+	// Events の行も取り消し、作成の通知もしない
+	mock.ExpectRollback()
+
+	id, err := CreateEventAndCondition(context.Background(), validCreateEventInput())
+	if err == nil {
+		t.Fatalf("CreateEventAndCondition() = %d, want an error", id)
+	}
+	if id != 0 {
+		t.Errorf("CreateEventAndCondition() id = %d, want 0", id)
+	}
+}
+
+func TestCreateEventAndConditionCommitsBoth(t *testing.T) {
+	mock := repositorytest.UseMock(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "Events"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(77))
+	mock.ExpectQuery(`INSERT INTO "EventConditions"`).
+		WithArgs(int64(77), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT \* FROM "WebhookSubscriptions" WHERE host_user_id = \$1`).
+		WithArgs("host").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	id, err := CreateEventAndCondition(context.Background(), validCreateEventInput())
+	if err != nil {
+		t.Fatalf("CreateEventAndCondition() error = %v", err)
+	}
+	if id != 77 {
+		t.Errorf("CreateEventAndCondition() id = %d, want 77", id)
+	}
+	waitForExpectations(t, mock)
+}
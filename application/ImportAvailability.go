@@ -0,0 +1,159 @@
+package application
+
+import (
+	"adjuSche-back-end/domain"
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"context"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrInvalidICS は取り込んだ .ics を解釈できなかったことを表す
+	ErrInvalidICS = domain.BadRequest("invalid_ics", "カレンダーファイル (.ics) を読み取れませんでした")
+	// ErrICSFetchFailed は URL から .ics を取得できなかったことを表す
+	ErrICSFetchFailed = domain.BadRequest("ics_fetch_failed", "カレンダーファイル (.ics) の URL から取得できませんでした")
+	// ErrCalendarFetchFailed は CalDAV サーバーから予定を取得できなかったことを表す
+	ErrCalendarFetchFailed = domain.BadRequest("calendar_fetch_failed", "カレンダーから予定を取得できませんでした。URL・ユーザー名・パスワードを確認してください")
+)
+
+// ImportAvailabilityInput は .ics からの空き時間の取り込みに必要な入力を表す
+// ICS が空の場合は URL から取得する
+type ImportAvailabilityInput struct {
+	EventID int64
+	UserID  string
+	ICS     []byte
+	URL     string
+}
+
+// ImportAvailabilityFromICS は .ics の予定からイベントの期間の空き時間を求め、参加者の空き時間として保存する
+// 空き時間の計算は Google カレンダーと同じく servise.GetFreeIntervals を使う
+func ImportAvailabilityFromICS(ctx context.Context, in ImportAvailabilityInput) ([]servise.TimeInterval, error) {
+	verr := &validationErrors{}
+	if in.UserID == "" {
+		verr.add("userId", FieldErrorRequired, "ユーザーIDを指定してください")
+	}
+	if len(in.ICS) == 0 && in.URL == "" {
+		verr.add("file", FieldErrorRequired, ".ics ファイルまたは URL を指定してください")
+	}
+	if err := verr.err(); err != nil {
+		return nil, err
+	}
+
+	data := in.ICS
+	if len(data) == 0 {
+		var err error
+		data, err = servise.FetchICS(ctx, in.URL)
+		if err != nil {
+			return nil, ErrICSFetchFailed.Wrap(err)
+		}
+	}
+
+	loc, err := time.LoadLocation(servise.ICalTimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load time zone: %w", err)
+	}
+	provider, err := servise.NewICSProvider(data, loc)
+	if err != nil {
+		return nil, ErrInvalidICS.Wrap(err)
+	}
+	return saveAvailabilityFromProvider(ctx, in.EventID, in.UserID, repository.AvailabilitySourceICS, provider, ErrInvalidICS)
+}
+
+// ImportCalDAVInput は CalDAV のカレンダーからの空き時間の取り込みに必要な入力を表す
+type ImportCalDAVInput struct {
+	EventID     int64
+	UserID      string
+	CalendarURL string
+	Username    string
+	Password    string
+}
+
+// ImportAvailabilityFromCalDAV は CalDAV のカレンダー (Nextcloud, iCloud, Fastmail など) の予定からイベントの期間の空き時間を求め、参加者の空き時間として保存する
+// 接続情報は取り込みのたびに受け取り、保存しない
+func ImportAvailabilityFromCalDAV(ctx context.Context, in ImportCalDAVInput) ([]servise.TimeInterval, error) {
+	verr := &validationErrors{}
+	if in.UserID == "" {
+		verr.add("userId", FieldErrorRequired, "ユーザーIDを指定してください")
+	}
+	if in.CalendarURL == "" {
+		verr.add("url", FieldErrorRequired, "カレンダーの URL を指定してください")
+	}
+	if err := verr.err(); err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(servise.ICalTimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load time zone: %w", err)
+	}
+	provider, err := servise.NewCalDAVProvider(servise.CalDAVConfig{
+		CalendarURL: in.CalendarURL,
+		Username:    in.Username,
+		Password:    in.Password,
+	}, loc)
+	if err != nil {
+		verr.add("url", FieldErrorInvalidFormat, "カレンダーの URL は http または https で指定してください")
+		return nil, verr.err()
+	}
+	return saveAvailabilityFromProvider(ctx, in.EventID, in.UserID, repository.AvailabilitySourceCalDAV, provider, ErrCalendarFetchFailed)
+}
+
+// saveAvailabilityFromProvider は provider の予定からイベントの期間の空き時間を求め、参加者の空き時間として保存する
+// 予定を取得できなかった場合は fetchErr を返す
+func saveAvailabilityFromProvider(ctx context.Context, eventID int64, userID string, source int8, provider servise.CalendarProvider, fetchErr *domain.Error) ([]servise.TimeInterval, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	ev, err := repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if ev.Status == repository.EventStatusCanceled {
+		return nil, ErrEventCanceled
+	}
+	cond, err := repo.GetEventConditionByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := loadWeeklySchedule(ctx, repo, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	free, err := servise.GetFreeIntervals(ctx, servise.WithWeeklySchedule(provider, schedule), cond.PeriodStart, cond.PeriodEnd, cond.DurationMin)
+	if err != nil {
+		return nil, fetchErr.Wrap(err)
+	}
+
+	avs := newAvailabilities(eventID, userID, source, free, time.Now())
+	if err := commitUserAvailabilities(ctx, repo, ev, userID, repository.CalendarAvailabilitySources, avs, nil); err != nil {
+		return nil, err
+	}
+	return free, nil
+}
+
+// commitUserAvailabilities はユーザーを参加者として登録して sources の空き時間を avs で置き換え、
+// コミット後に SSE の購読者と主催者の Webhook に通知する。inTx は同じトランザクションの中で先に実行する
+func commitUserAvailabilities(ctx context.Context, repo *repository.SupabaseRepositoryImpl, ev *repository.Events, userID string, sources []int8, avs []repository.Availability, inTx func(tx *repository.SupabaseRepositoryImpl) error) error {
+	err := repo.UnitOfWork(ctx, func(tx *repository.SupabaseRepositoryImpl) error {
+		if inTx != nil {
+			if err := inTx(tx); err != nil {
+				return err
+			}
+		}
+		if _, err := acceptEventParticipant(ctx, tx, ev.ID, userID); err != nil {
+			return err
+		}
+		return tx.ReplaceUserAvailabilitiesBySources(ctx, ev.ID, userID, sources, avs)
+	})
+	if err != nil {
+		return err
+	}
+	publishEventUpdated(ev.ID)
+	notifyAvailabilitySubmitted(ev, userID, len(avs))
+	return nil
+}
@@ -1,21 +1,24 @@
 package application
 
 import (
+	"adjuSche-back-end/domain"
 	"adjuSche-back-end/repository"
 	"adjuSche-back-end/servise"
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"time"
 )
 
 type InviteSummary struct {
-	EventName   string
-	VotedCount  int
-	Memo        string
-	PeriodStart time.Time
-	PeriodEnd   time.Time
-	DurationMin int
+	EventName     string
+	VotedCount    int
+	DeclinedCount int // 辞退・参加を取り消した参加者の数
+	Memo          string
+	PeriodStart   time.Time
+	PeriodEnd     time.Time
+	DurationMin   int
 }
 
 type PossibleSlot struct {
@@ -23,7 +26,9 @@ type PossibleSlot struct {
 	Date                 string
 	PeriodStart          time.Time
 	PeriodEnd            time.Time
-	ParticipateMemberNum int
+	ParticipateMemberNum int      // この区間に参加可能な人数
+	AvailableUserIDs     []string // この区間に参加可能な回答者
+	AvailableMembers     []string // この区間に参加可能な回答者の表示名（AvailableUserIDs と同じ順）
 }
 
 // TimeSlot は時間スロットを表す構造体
@@ -32,12 +37,11 @@ type TimeSlot struct {
 	End   time.Time
 }
 
-// calculateOverlappingSlots は全参加者の空き時間の重複期間を計算します
-func calculateOverlappingSlots(allAvailabilities []repository.Availability, newUserSlots []servise.TimeInterval, durationMin int) []PossibleSlot {
-	// 全てのユーザーの空き時間を TimeSlot に変換
+// calculateOverlappingSlots は回答者の空き時間から候補日時を求める
+// 候補日時は、durationMin 分の会議をその中のどこに置いても参加できる回答者が同じ区間で、参加可能な人数とともに時刻順に返す
+// 全員が参加できる区間に限らず、1 人以上が参加できる区間を返す。同じユーザーの空き時間が重なっていても 1 人として数える
+func calculateOverlappingSlots(allAvailabilities []repository.Availability, durationMin int) []PossibleSlot {
 	userSlots := make(map[string][]TimeSlot)
-
-	// 既存参加者の空き時間を追加
 	for _, av := range allAvailabilities {
 		start, err := time.Parse(time.RFC3339, av.AvailableStart)
 		if err != nil {
@@ -47,186 +51,370 @@ func calculateOverlappingSlots(allAvailabilities []repository.Availability, newU
 		if err != nil {
 			continue
 		}
-
-		if userSlots[av.UserID] == nil {
-			userSlots[av.UserID] = make([]TimeSlot, 0)
-		}
 		userSlots[av.UserID] = append(userSlots[av.UserID], TimeSlot{Start: start, End: end})
 	}
 
-	// 新しいユーザーの空き時間を追加（仮のユーザーIDを使用）
-	newUserID := "new_user"
-	for _, interval := range newUserSlots {
-		if userSlots[newUserID] == nil {
-			userSlots[newUserID] = make([]TimeSlot, 0)
-		}
-		userSlots[newUserID] = append(userSlots[newUserID], TimeSlot{Start: interval.Start, End: interval.End})
-	}
-
-	// 参加者数を計算
-	participantCount := len(userSlots)
-	if participantCount == 0 {
-		return []PossibleSlot{}
-	}
-
-	// 全ユーザーの重複期間を計算
-	overlapping := findOverlappingTimeSlots(userSlots, durationMin)
-
-	// PossibleSlot に変換
-	slots := make([]PossibleSlot, 0, len(overlapping))
-	for i, slot := range overlapping {
-		dateStr := slot.Start.Format("2006-01-02")
+	segments := findAvailabilitySegments(userSlots, durationMin)
+	slots := make([]PossibleSlot, 0, len(segments))
+	for i, seg := range segments {
 		slots = append(slots, PossibleSlot{
 			ID:                   i + 1,
-			Date:                 dateStr,
-			PeriodStart:          slot.Start,
-			PeriodEnd:            slot.End,
-			ParticipateMemberNum: participantCount,
+			Date:                 seg.Start.Format("2006-01-02"),
+			PeriodStart:          seg.Start,
+			PeriodEnd:            seg.End,
+			ParticipateMemberNum: len(seg.UserIDs),
+			AvailableUserIDs:     seg.UserIDs,
 		})
 	}
-
 	return slots
 }
 
-// findOverlappingTimeSlots は全ユーザーの空き時間の重複期間を見つけます
-func findOverlappingTimeSlots(userSlots map[string][]TimeSlot, durationMin int) []TimeSlot {
-	if len(userSlots) == 0 {
-		return []TimeSlot{}
-	}
+// availabilitySegment は durationMin 分の会議をどこに置いても UserIDs の全員が参加できる区間
+type availabilitySegment struct {
+	TimeSlot
+	UserIDs []string // 参加可能な回答者（昇順）
+}
 
-	// 全てのイベント（開始・終了）を収集
-	type Event struct {
-		Time    time.Time
-		IsStart bool
-		UserID  string
-	}
+// availabilityBlock は 1 人の回答者が途切れずに空いている時間
+type availabilityBlock struct {
+	TimeSlot
+	UserID string
+}
 
-	var events []Event
+// findAvailabilitySegments は会議の開始時刻ごとに、開始から durationMin 分を途切れずに空けている回答者を求め、
+// 顔ぶれが同じ開始時刻の範囲 [lo, hi] を区間 [lo, hi+durationMin) として返す
+// 顔ぶれの変わる時刻で単純に区切ると、区切られた区間が所要時間に満たないために実際に参加できる回答者まで落ちてしまうため、
+// 回答者ごとに所要時間の分だけ続けて空いているかを確かめる
+func findAvailabilitySegments(userSlots map[string][]TimeSlot, durationMin int) []availabilitySegment {
+	duration := time.Duration(durationMin) * time.Minute
+
+	// 回答者ごとに重なった・接した空き時間をつなげ、所要時間に満たないものは除く
+	var blocks []availabilityBlock
 	for userID, slots := range userSlots {
-		for _, slot := range slots {
-			events = append(events, Event{Time: slot.Start, IsStart: true, UserID: userID})
-			events = append(events, Event{Time: slot.End, IsStart: false, UserID: userID})
+		for _, b := range mergeTimeSlots(slots) {
+			if b.End.Sub(b.Start) >= duration {
+				blocks = append(blocks, availabilityBlock{TimeSlot: b, UserID: userID})
+			}
 		}
 	}
+	if len(blocks) == 0 {
+		return nil
+	}
 
-	// 時間順にソート
-	sort.Slice(events, func(i, j int) bool {
-		if events[i].Time.Equal(events[j].Time) {
-			// 同じ時刻の場合、終了イベントを先に処理
-			return !events[i].IsStart && events[j].IsStart
-		}
-		return events[i].Time.Before(events[j].Time)
-	})
-
-	// アクティブなユーザーを追跡
-	activeUsers := make(map[string]bool)
-	var result []TimeSlot
-	var currentStart time.Time
-	totalUsers := len(userSlots)
-
-	for _, event := range events {
-		currentActiveCount := len(activeUsers)
-
-		// 全ユーザーがアクティブな期間を記録
-		if currentActiveCount == totalUsers && !currentStart.IsZero() {
-			duration := event.Time.Sub(currentStart)
-			if duration >= time.Duration(durationMin)*time.Minute {
-				result = append(result, TimeSlot{Start: currentStart, End: event.Time})
+	// 開始時刻の候補の範囲 [Start, End-duration] の端で、参加できる顔ぶれが変わる
+	var points []time.Time
+	for _, b := range blocks {
+		points = append(points, b.Start, b.End.Add(-duration))
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Before(points[j]) })
+	points = slices.CompactFunc(points, func(a, b time.Time) bool { return a.Equal(b) })
+
+	membersAt := func(start time.Time) []string {
+		seen := make(map[string]bool)
+		var users []string
+		for _, b := range blocks {
+			if !start.Before(b.Start) && !start.After(b.End.Add(-duration)) && !seen[b.UserID] {
+				seen[b.UserID] = true
+				users = append(users, b.UserID)
 			}
 		}
+		sort.Strings(users)
+		return users
+	}
 
-		// アクティブユーザーリストを更新
-		if event.IsStart {
-			activeUsers[event.UserID] = true
-		} else {
-			delete(activeUsers, event.UserID)
+	// 端の時刻そのものと、隣り合う端の間（中点で代表する）の顔ぶれを順に見て、同じ顔ぶれが続く範囲をまとめる
+	var result []availabilitySegment
+	var current *availabilitySegment
+	var lo, hi time.Time
+	extend := func(from, to time.Time, users []string) {
+		if current != nil && slices.Equal(current.UserIDs, users) {
+			hi = to
+			return
 		}
-
-		// 新しい期間の開始を記録
-		if len(activeUsers) == totalUsers {
-			currentStart = event.Time
-		} else {
-			currentStart = time.Time{} // リセット
+		if current != nil {
+			current.Start, current.End = lo, hi.Add(duration)
+			result = append(result, *current)
+			current = nil
+		}
+		if len(users) > 0 {
+			current = &availabilitySegment{UserIDs: users}
+			lo, hi = from, to
+		}
+	}
+	for i, p := range points {
+		extend(p, p, membersAt(p))
+		if i+1 < len(points) {
+			next := points[i+1]
+			extend(p, next, membersAt(p.Add(next.Sub(p)/2)))
 		}
 	}
+	extend(time.Time{}, time.Time{}, nil)
 
 	return result
 }
 
-// BuildInviteResponse はイベントIDとGoogleトークンから空き時間候補を構築する
-func BuildInviteResponse(ctx context.Context, eventID int64, tokenString string, credFile string) (InviteSummary, []PossibleSlot, error) {
+// mergeTimeSlots は重なった・接した時間をつなげ、開始時刻順に返す
+func mergeTimeSlots(slots []TimeSlot) []TimeSlot {
+	sorted := make([]TimeSlot, 0, len(slots))
+	for _, s := range slots {
+		if s.End.After(s.Start) {
+			sorted = append(sorted, s)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	var merged []TimeSlot
+	for _, s := range sorted {
+		if n := len(merged); n > 0 && !s.Start.After(merged[n-1].End) {
+			if s.End.After(merged[n-1].End) {
+				merged[n-1].End = s.End
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// InviteUser の provider に指定するカレンダーの種類
+const (
+	CalendarProviderGoogle    = "google"
+	CalendarProviderMicrosoft = "microsoft"
+)
+
+var (
+	// ErrInvalidCalendarProvider は対応していないカレンダーの種類が指定されたことを表す
+	ErrInvalidCalendarProvider = domain.BadRequest("invalid_calendar_provider", "provider には google または microsoft を指定してください")
+	// ErrCalendarAccessFailed は OAuth のトークンでカレンダーの予定を取得できなかったことを表す
+	ErrCalendarAccessFailed = domain.BadRequest("calendar_access_failed", "カレンダーから予定を取得できませんでした。カレンダーへのアクセスを許可し直してください")
+)
+
+// CalendarAccess はユーザーのカレンダーを読むのに必要な情報を表す
+type CalendarAccess struct {
+	Provider string // CalendarProviderGoogle（省略時）または CalendarProviderMicrosoft
+	Token    string // アクセストークン、または JSON 形式の oauth2.Token
+	CredFile string // Google の OAuth クライアントシークレットファイル
+}
+
+// Validate は対応しているカレンダーの種類かを確認する
+func (a CalendarAccess) Validate() error {
+	switch a.Provider {
+	case "", CalendarProviderGoogle, CalendarProviderMicrosoft:
+		return nil
+	}
+	return ErrInvalidCalendarProvider
+}
+
+// Source はこのカレンダーから求めた空き時間を保存するときの sourse を返す
+func (a CalendarAccess) Source() int8 {
+	if a.Provider == CalendarProviderMicrosoft {
+		return repository.AvailabilitySourceOutlook
+	}
+	return repository.AvailabilitySourceGoogleCalendar
+}
+
+// open はカレンダーの種類に応じた CalendarProvider を作る
+func (a CalendarAccess) open() (servise.CalendarProvider, error) {
+	if a.Provider == CalendarProviderMicrosoft {
+		return servise.NewOutlookCalendarServiceFromTokenString(a.Token)
+	}
+	return servise.NewCalendarServiceFromTokenString(a.Token, a.CredFile)
+}
+
+// anonymousRespondentID は userId なしで呼び出したユーザーの空き時間を候補日時の計算に含めるための仮のユーザーID
+const anonymousRespondentID = "anonymous"
+
+// InviteInput は BuildInviteResponse の入力を表す
+type InviteInput struct {
+	EventID int64
+	UserID  string
+	// SessionUser は UserID をセッションで確かめたかどうか
+	// 従来のフロントエンドが本文で送る userId は確かめられないため、Google のプロフィールを同期しない
+	SessionUser bool
+	Access      CalendarAccess
+}
+
+// BuildInviteResponse はイベントIDとユーザーのカレンダー（Google / Microsoft 365）から空き時間候補を構築する
+// UserID が空の場合も呼び出したユーザーの空き時間を候補日時に含めるが、投票済みの人数には数えない
+// 戻り値の []servise.TimeInterval は呼び出したユーザー自身の空き時間
+func BuildInviteResponse(ctx context.Context, in InviteInput) (InviteSummary, []PossibleSlot, []servise.TimeInterval, error) {
+	eventID, userID, access := in.EventID, in.UserID, in.Access
 	fmt.Printf("BuildInviteResponse: eventID=%d を開始します\n", eventID)
 
+	if err := access.Validate(); err != nil {
+		return InviteSummary{}, nil, nil, err
+	}
+
 	repo, err := repository.NewSupabaseRepository()
 	if err != nil {
 		fmt.Printf("repository初期化エラー: %v\n", err)
-		return InviteSummary{}, nil, fmt.Errorf("failed to init repository: %w", err)
+		return InviteSummary{}, nil, nil, fmt.Errorf("failed to init repository: %w", err)
 	}
 
 	fmt.Printf("GetEventByID を呼び出します: eventID=%d\n", eventID)
 	ev, err := repo.GetEventByID(ctx, eventID)
 	if err != nil {
 		fmt.Printf("GetEventByID エラー: %v\n", err)
-		return InviteSummary{}, nil, err
+		return InviteSummary{}, nil, nil, err
 	}
 	fmt.Printf("GetEventByID 成功: title=%s\n", ev.Title)
+	// 他の提出経路と同じく、中止されたイベントには空き時間を提出できない
+	if ev.Status == repository.EventStatusCanceled {
+		return InviteSummary{}, nil, nil, ErrEventCanceled
+	}
 
 	fmt.Printf("GetEventConditionByEventID を呼び出します: eventID=%d\n", eventID)
 	cond, err := repo.GetEventConditionByEventID(ctx, eventID)
 	if err != nil {
 		fmt.Printf("GetEventConditionByEventID エラー: %v\n", err)
-		return InviteSummary{}, nil, err
+		return InviteSummary{}, nil, nil, err
 	}
 	fmt.Printf("GetEventConditionByEventID 成功: period=%s to %s\n", cond.PeriodStart.Format("2006-01-02"), cond.PeriodEnd.Format("2006-01-02"))
 
-	// Google カレンダーから空き時間抽出
-	cal, err := servise.NewCalendarServiceFromTokenString(tokenString, credFile)
+	// ユーザーのカレンダーから空き時間抽出（繰り返しのルールと勤務時間の外は除く）
+	cal, err := access.open()
 	if err != nil {
-		return InviteSummary{}, nil, fmt.Errorf("failed to init calendar service: %w", err)
+		return InviteSummary{}, nil, nil, fmt.Errorf("failed to init calendar service: %w", err)
+	}
+	schedule, err := loadWeeklySchedule(ctx, repo, userID)
+	if err != nil {
+		return InviteSummary{}, nil, nil, err
 	}
 
-	free, err := cal.GetFreeIntervalsInRange(cond.PeriodStart, cond.PeriodEnd, cond.DurationMin)
+	free, err := servise.GetFreeIntervals(ctx, servise.WithWeeklySchedule(cal, schedule), cond.PeriodStart, cond.PeriodEnd, cond.DurationMin)
 	if err != nil {
-		return InviteSummary{}, nil, err
+		return InviteSummary{}, nil, nil, err
+	}
+	if in.SessionUser {
+		syncGoogleProfile(ctx, repo, userID, access, cal)
 	}
 
 	// 既存参加者の空き時間を取得
 	allAvailabilities, err := repo.ListAvailabilitiesByEventID(ctx, eventID)
 	if err != nil {
 		fmt.Printf("ListAvailabilitiesByEventID エラー: %v\n", err)
-		return InviteSummary{}, nil, err
+		return InviteSummary{}, nil, nil, err
 	}
 	fmt.Printf("既存参加者の空き時間レコード数: %d\n", len(allAvailabilities))
 
-	// ユニーク投票者数（Availabilities に提出済みのユーザー + 新規ユーザー）
-	voted, _ := repo.CountDistinctAvailabilityUsersByEventID(ctx, eventID)
-	// 新規ユーザーが追加されるため +1
-	voted = voted + 1
+	// 呼び出したユーザーの提出済みの空き時間は、今回のカレンダーの空き時間で置き換えて数える
+	avs, voted := inviteAvailabilities(allAvailabilities, eventID, userID, access.Source(), free, time.Now())
+	slots := calculateOverlappingSlots(avs, cond.DurationMin)
+	fmt.Printf("計算された候補日時の数: %d\n", len(slots))
+	if err := nameSlotMembers(ctx, repo, eventID, slots); err != nil {
+		return InviteSummary{}, nil, nil, err
+	}
+
+	declined, err := countDeclinedParticipants(ctx, repo, eventID)
+	if err != nil {
+		return InviteSummary{}, nil, nil, err
+	}
+
+	return newInviteSummary(ev, cond, voted, declined), slots, free, nil
+}
+
+// inviteAvailabilities は提出済みの空き時間に呼び出したユーザーの空き時間 free を加えたものと、投票済みの人数を返す
+// userID が提出済みなら同じユーザーとして置き換え、空なら仮のユーザーとして加えて人数には数えない
+func inviteAvailabilities(existing []repository.Availability, eventID int64, userID string, source int8, free []servise.TimeInterval, now time.Time) ([]repository.Availability, int) {
+	if userID == "" {
+		avs := append(slices.Clone(existing), newAvailabilities(eventID, anonymousRespondentID, source, free, now)...)
+		return avs, countAvailabilityUsers(existing)
+	}
+	avs := previewAvailabilities(existing, newAvailabilities(eventID, userID, source, free, now), userID)
+	return avs, countAvailabilityUsers(avs)
+}
+
+// GetInviteSummary はイベントの概要と、提出済みの空き時間から求めた現在の候補日時を返す
+// カレンダーには触れないため、トークンなしで招待 URL を開いた人にも見せられる
+func GetInviteSummary(ctx context.Context, eventID int64) (InviteSummary, []PossibleSlot, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return InviteSummary{}, nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	ev, err := repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return InviteSummary{}, nil, err
+	}
+	cond, err := repo.GetEventConditionByEventID(ctx, eventID)
+	if err != nil {
+		return InviteSummary{}, nil, err
+	}
+	avs, err := repo.ListAvailabilitiesByEventID(ctx, eventID)
+	if err != nil {
+		return InviteSummary{}, nil, err
+	}
+	voted, err := repo.CountDistinctAvailabilityUsersByEventID(ctx, eventID)
+	if err != nil {
+		return InviteSummary{}, nil, err
+	}
+
+	declined, err := countDeclinedParticipants(ctx, repo, eventID)
+	if err != nil {
+		return InviteSummary{}, nil, err
+	}
 
-	// 全員（既存 + 新規）の空き時間の重複期間を計算
-	slots := calculateOverlappingSlots(allAvailabilities, free, cond.DurationMin)
-	fmt.Printf("計算された重複期間数: %d\n", len(slots))
+	slots := calculateOverlappingSlots(avs, cond.DurationMin)
+	if err := nameSlotMembers(ctx, repo, eventID, slots); err != nil {
+		return InviteSummary{}, nil, err
+	}
+	return newInviteSummary(ev, cond, voted, declined), slots, nil
+}
 
+func newInviteSummary(ev *repository.Events, cond *repository.EventCondition, voted, declined int) InviteSummary {
 	memo := ""
 	if ev.Note.Valid {
 		memo = ev.Note.String
 	}
+	return InviteSummary{
+		EventName:     ev.Title,
+		VotedCount:    voted,
+		DeclinedCount: declined,
+		Memo:          memo,
+		PeriodStart:   cond.PeriodStart,
+		PeriodEnd:     cond.PeriodEnd,
+		DurationMin:   cond.DurationMin,
+	}
+}
+
+// SubmitCalendarAvailabilityInput はカレンダー（Google / Microsoft 365）からの空き時間の提出に必要な入力を表す
+type SubmitCalendarAvailabilityInput struct {
+	EventID int64
+	UserID  string
+	Access  CalendarAccess
+}
+
+// SubmitAvailabilityFromCalendar はユーザーのカレンダーの予定からイベントの期間の空き時間を求め、参加者の空き時間として保存する
+// 候補日時の閲覧 (GetInviteSummary) とは分け、カレンダーへのアクセスは提出するときだけ行う
+func SubmitAvailabilityFromCalendar(ctx context.Context, in SubmitCalendarAvailabilityInput) ([]servise.TimeInterval, error) {
+	verr := &validationErrors{}
+	if in.UserID == "" {
+		verr.add("userId", FieldErrorRequired, "ユーザーIDを指定してください")
+	}
+	if err := verr.err(); err != nil {
+		return nil, err
+	}
+	if err := in.Access.Validate(); err != nil {
+		return nil, err
+	}
+
+	cal, err := in.Access.open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init calendar service: %w", err)
+	}
 
-	summary := InviteSummary{
-		EventName:   ev.Title,
-		VotedCount:  voted,
-		Memo:        memo,
-		PeriodStart: cond.PeriodStart,
-		PeriodEnd:   cond.PeriodEnd,
-		DurationMin: cond.DurationMin,
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
 	}
+	syncGoogleProfile(ctx, repo, in.UserID, in.Access, cal)
 
-	return summary, slots, nil
+	return saveAvailabilityFromProvider(ctx, in.EventID, in.UserID, in.Access.Source(), cal, ErrCalendarAccessFailed)
 }
 
 // SaveUserAvailabilitiesFromCalendar は、与えられた空き時間を Availabilities に保存する
-// available_start/end は RFC3339 の時刻文字列、available_date は YYYY-MM-DD
-func SaveUserAvailabilitiesFromCalendar(ctx context.Context, eventID int64, userID string, intervals []servise.TimeInterval) error {
+// available_start/end は RFC3339 の時刻文字列、available_date は YYYY-MM-DD。source は取り込んだカレンダーの sourse
+func SaveUserAvailabilitiesFromCalendar(ctx context.Context, eventID int64, userID string, source int8, intervals []servise.TimeInterval) error {
 	fmt.Printf("SaveUserAvailabilitiesFromCalendar: eventID=%d, userID=%s, intervals=%d\n", eventID, userID, len(intervals))
 
 	repo, err := repository.NewSupabaseRepository()
@@ -234,23 +422,9 @@ func SaveUserAvailabilitiesFromCalendar(ctx context.Context, eventID int64, user
 		return fmt.Errorf("failed to init repository: %w", err)
 	}
 
-	avs := make([]repository.Availability, 0, len(intervals))
-	now := time.Now()
-	for i, iv := range intervals {
-		dateStr := iv.Start.Format("2006-01-02")
-		startStr := iv.Start.Format(time.RFC3339)
-		endStr := iv.End.Format(time.RFC3339)
-		av := repository.Availability{
-			EventID:        eventID,
-			UserID:         userID,
-			AvailableDate:  dateStr,
-			AvailableStart: startStr,
-			AvailableEnd:   endStr,
-			Sourse:         0, // 0: google_calendar
-			CreatedAt:      now,
-		}
-		avs = append(avs, av)
-		fmt.Printf("  [%d] %s: %s - %s\n", i, dateStr, startStr, endStr)
+	avs := newAvailabilities(eventID, userID, source, intervals, time.Now())
+	for i, av := range avs {
+		fmt.Printf("  [%d] %s: %s - %s\n", i, av.AvailableDate, av.AvailableStart, av.AvailableEnd)
 	}
 
 	fmt.Printf("ReplaceUserAvailabilitiesForEvent を呼び出します\n")
@@ -259,9 +433,35 @@ func SaveUserAvailabilitiesFromCalendar(ctx context.Context, eventID int64, user
 		return err
 	}
 	fmt.Printf("ReplaceUserAvailabilitiesForEvent 完了\n")
+	publishEventUpdated(eventID)
+
+	ev, err := repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		// 空き時間は保存できているため、通知できなくてもエラーにしない
+		fmt.Printf("Webhook の通知のためのイベント取得に失敗しました: %v\n", err)
+		return nil
+	}
+	notifyAvailabilitySubmitted(ev, userID, len(avs))
 	return nil
 }
 
+// newAvailabilities は空き区間を Availabilities のレコードに変換する
+func newAvailabilities(eventID int64, userID string, source int8, intervals []servise.TimeInterval, now time.Time) []repository.Availability {
+	avs := make([]repository.Availability, 0, len(intervals))
+	for _, iv := range intervals {
+		avs = append(avs, repository.Availability{
+			EventID:        eventID,
+			UserID:         userID,
+			AvailableDate:  iv.Start.Format("2006-01-02"),
+			AvailableStart: iv.Start.Format(time.RFC3339),
+			AvailableEnd:   iv.End.Format(time.RFC3339),
+			Sourse:         source,
+			CreatedAt:      now,
+		})
+	}
+	return avs
+}
+
 // RegisterEventParticipant はユーザーをイベントの参加者として登録します
 func RegisterEventParticipant(ctx context.Context, eventID int64, userID string) error {
 	fmt.Printf("RegisterEventParticipant: eventID=%d, userID=%s\n", eventID, userID)
@@ -271,9 +471,9 @@ func RegisterEventParticipant(ctx context.Context, eventID int64, userID string)
 		return fmt.Errorf("failed to init repository: %w", err)
 	}
 
-	participant, err := repo.GetOrCreateEventParticipant(ctx, eventID, userID)
+	participant, err := acceptEventParticipant(ctx, repo, eventID, userID)
 	if err != nil {
-		return fmt.Errorf("failed to register event participant: %w", err)
+		return err
 	}
 
 	fmt.Printf("参加者登録完了: participantID=%d, status=%d\n", participant.ID, participant.Status)
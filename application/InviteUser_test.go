@@ -0,0 +1,161 @@
+package application
+
+import (
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"slices"
+	"testing"
+	"time"
+)
+
+var testLoc = time.FixedZone("JST", 9*60*60)
+
+func at(hour, min int) time.Time {
+	return time.Date(2026, 11, 2, hour, min, 0, 0, testLoc)
+}
+
+func testAvailability(userID string, source int8, start, end time.Time) repository.Availability {
+	return repository.Availability{
+		EventID:        1,
+		UserID:         userID,
+		AvailableDate:  start.Format("2006-01-02"),
+		AvailableStart: start.Format(time.RFC3339),
+		AvailableEnd:   end.Format(time.RFC3339),
+		Sourse:         source,
+	}
+}
+
+type wantSlot struct {
+	start, end time.Time
+	users      []string
+}
+
+func assertSlots(t *testing.T, got []PossibleSlot, want []wantSlot) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d slots, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.ID != i+1 || !g.PeriodStart.Equal(w.start) || !g.PeriodEnd.Equal(w.end) {
+			t.Errorf("slot %d = #%d %s-%s, want %s-%s", i, g.ID, g.PeriodStart, g.PeriodEnd, w.start, w.end)
+		}
+		if g.ParticipateMemberNum != len(w.users) || !slices.Equal(g.AvailableUserIDs, w.users) {
+			t.Errorf("slot %d members = %d %v, want %v", i, g.ParticipateMemberNum, g.AvailableUserIDs, w.users)
+		}
+	}
+}
+
+func TestCalculateOverlappingSlotsCountsMembersPerSlot(t *testing.T) {
+	avs := []repository.Availability{
+		testAvailability("a", repository.AvailabilitySourceGoogleCalendar, at(9, 0), at(12, 0)),
+		testAvailability("b", repository.AvailabilitySourceGoogleCalendar, at(10, 0), at(13, 0)),
+		testAvailability("c", repository.AvailabilitySourceGoogleCalendar, at(11, 0), at(12, 0)),
+	}
+
+	// 区間は、その顔ぶれで 60 分の会議をどこに置いても参加できる範囲
+	assertSlots(t, calculateOverlappingSlots(avs, 60), []wantSlot{
+		{at(9, 0), at(11, 0), []string{"a"}},
+		{at(10, 0), at(12, 0), []string{"a", "b"}},
+		{at(11, 0), at(12, 0), []string{"a", "b", "c"}},
+		{at(11, 0), at(13, 0), []string{"b"}},
+	})
+}
+
+func TestCalculateOverlappingSlotsCountsEachUserOnce(t *testing.T) {
+	// 同じユーザーの重なった空き時間・接した空き時間は 1 人として数え、区間も途切れさせない
+	avs := []repository.Availability{
+		testAvailability("a", repository.AvailabilitySourceGoogleCalendar, at(9, 0), at(11, 0)),
+		testAvailability("a", repository.AvailabilitySourceManual, at(10, 0), at(12, 0)),
+		testAvailability("a", repository.AvailabilitySourceManual, at(12, 0), at(13, 0)),
+		testAvailability("b", repository.AvailabilitySourceGoogleCalendar, at(9, 0), at(13, 0)),
+	}
+
+	assertSlots(t, calculateOverlappingSlots(avs, 60), []wantSlot{
+		{at(9, 0), at(13, 0), []string{"a", "b"}},
+	})
+}
+
+func TestCalculateOverlappingSlotsDropsShortSlots(t *testing.T) {
+	avs := []repository.Availability{
+		testAvailability("a", repository.AvailabilitySourceGoogleCalendar, at(9, 0), at(11, 0)),
+		testAvailability("b", repository.AvailabilitySourceGoogleCalendar, at(10, 30), at(12, 0)),
+		{UserID: "c", AvailableStart: "broken", AvailableEnd: "broken"},
+	}
+
+	// 2 人が参加可能な 10:30-11:00 は所要時間に満たないが、それぞれ 1 人では参加できる
+	assertSlots(t, calculateOverlappingSlots(avs, 60), []wantSlot{
+		{at(9, 0), at(11, 0), []string{"a"}},
+		{at(10, 30), at(12, 0), []string{"b"}},
+	})
+	if got := calculateOverlappingSlots(nil, 60); len(got) != 0 {
+		t.Errorf("no availabilities: got %+v", got)
+	}
+}
+
+func TestCalculateOverlappingSlotsKeepsMembersAcrossShortChanges(t *testing.T) {
+	// b は 40 分しか空いていないが、a は b の出入りをまたいで 10:00-11:00 に 1 人で参加できる
+	avs := []repository.Availability{
+		testAvailability("a", repository.AvailabilitySourceGoogleCalendar, at(10, 0), at(12, 0)),
+		testAvailability("b", repository.AvailabilitySourceGoogleCalendar, at(10, 40), at(11, 20)),
+	}
+	assertSlots(t, calculateOverlappingSlots(avs, 60), []wantSlot{
+		{at(10, 0), at(12, 0), []string{"a"}},
+	})
+
+	// 顔ぶれが 20 分ごとに変わっても、60 分続けて空いている回答者はそれぞれ候補に残る
+	avs = []repository.Availability{
+		testAvailability("a", repository.AvailabilitySourceGoogleCalendar, at(10, 0), at(11, 0)),
+		testAvailability("b", repository.AvailabilitySourceGoogleCalendar, at(10, 20), at(11, 20)),
+		testAvailability("c", repository.AvailabilitySourceGoogleCalendar, at(10, 40), at(11, 40)),
+	}
+	assertSlots(t, calculateOverlappingSlots(avs, 60), []wantSlot{
+		{at(10, 0), at(11, 0), []string{"a"}},
+		{at(10, 20), at(11, 20), []string{"b"}},
+		{at(10, 40), at(11, 40), []string{"c"}},
+	})
+}
+
+func TestInviteAvailabilitiesDeduplicatesCaller(t *testing.T) {
+	existing := []repository.Availability{
+		testAvailability("a", repository.AvailabilitySourceGoogleCalendar, at(9, 0), at(12, 0)),
+		testAvailability("caller", repository.AvailabilitySourceGoogleCalendar, at(9, 0), at(10, 0)),
+		testAvailability("caller", repository.AvailabilitySourceManual, at(15, 0), at(16, 0)),
+	}
+	free := []servise.TimeInterval{{Start: at(11, 0), End: at(12, 0)}}
+
+	avs, voted := inviteAvailabilities(existing, 1, "caller", repository.AvailabilitySourceGoogleCalendar, free, at(8, 0))
+	if voted != 2 {
+		t.Errorf("voted = %d, want 2 (caller already voted)", voted)
+	}
+	// カレンダー由来の 9:00-10:00 は今回の空き時間で置き換え、手入力の 15:00-16:00 は残す
+	assertSlots(t, calculateOverlappingSlots(avs, 60), []wantSlot{
+		{at(9, 0), at(12, 0), []string{"a"}},
+		{at(11, 0), at(12, 0), []string{"a", "caller"}},
+		{at(15, 0), at(16, 0), []string{"caller"}},
+	})
+
+	_, voted = inviteAvailabilities(existing, 1, "newcomer", repository.AvailabilitySourceGoogleCalendar, free, at(8, 0))
+	if voted != 3 {
+		t.Errorf("voted = %d, want 3 (new voter)", voted)
+	}
+}
+
+func TestInviteAvailabilitiesWithoutUserID(t *testing.T) {
+	existing := []repository.Availability{
+		testAvailability("a", repository.AvailabilitySourceGoogleCalendar, at(9, 0), at(12, 0)),
+	}
+	free := []servise.TimeInterval{{Start: at(11, 0), End: at(12, 0)}}
+
+	avs, voted := inviteAvailabilities(existing, 1, "", repository.AvailabilitySourceGoogleCalendar, free, at(8, 0))
+	if voted != 1 {
+		t.Errorf("voted = %d, want 1 (anonymous caller is not counted)", voted)
+	}
+	slots := calculateOverlappingSlots(avs, 60)
+	if len(slots) != 2 || slots[1].ParticipateMemberNum != 2 {
+		t.Errorf("slots = %+v, want the caller included in the 11:00-12:00 slot", slots)
+	}
+	if len(existing) != 1 {
+		t.Errorf("existing availabilities were modified: %+v", existing)
+	}
+}
@@ -0,0 +1,98 @@
+package application
+
+import (
+	"adjuSche-back-end/domain"
+	"adjuSche-back-end/repository"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// lineLinkNonceTTL はアカウント連携用 nonce の有効期間
+const lineLinkNonceTTL = 10 * time.Minute
+
+// lineAccountLinkURL は LINE のアカウント連携エンドポイント
+const lineAccountLinkURL = "https://access.line.me/dialog/bot/accountLink"
+
+// IssueLineLinkNonce はログイン済みユーザーと linkToken から nonce を発行し、
+// ユーザーをリダイレクトさせる LINE のアカウント連携 URL を返す
+func IssueLineLinkNonce(ctx context.Context, userID, linkToken string) (string, error) {
+	if userID == "" || linkToken == "" {
+		return "", fmt.Errorf("userID and linkToken are required")
+	}
+
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return "", fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	nonce, err := generateNonce()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if err := repo.CreateLineLinkNonce(ctx, &repository.LineLinkNonce{
+		Nonce:     nonce,
+		UserID:    userID,
+		CreatedAt: now,
+		ExpiredAt: now.Add(lineLinkNonceTTL),
+	}); err != nil {
+		return "", err
+	}
+
+	q := url.Values{}
+	q.Set("linkToken", linkToken)
+	q.Set("nonce", nonce)
+	return lineAccountLinkURL + "?" + q.Encode(), nil
+}
+
+// CompleteLineAccountLink は accountLink イベントの nonce を検証し、LINE userId とアプリのユーザーを紐付ける
+func CompleteLineAccountLink(ctx context.Context, lineUserID, nonce string) (string, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return "", fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	n, err := repo.ConsumeLineLinkNonce(ctx, nonce, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	link, err := repo.UpsertLineAccountLink(ctx, lineUserID, n.UserID)
+	if err != nil {
+		return "", err
+	}
+	return link.UserID, nil
+}
+
+// GetUserIDByLineUserID は LINE userId に紐付いたアプリのユーザーID(UUID)を返す
+// 連携されていない場合は ErrLineAccountNotLinked を返す
+func GetUserIDByLineUserID(ctx context.Context, lineUserID string) (string, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return "", fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	link, err := repo.GetLineAccountLinkByLineUserID(ctx, lineUserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", ErrLineAccountNotLinked.Wrap(err)
+		}
+		return "", err
+	}
+	return link.UserID, nil
+}
+
+// generateNonce は推測困難な nonce (128bit 以上) を生成する
+func generateNonce() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
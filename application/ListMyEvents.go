@@ -0,0 +1,145 @@
+package application
+
+import (
+	"adjuSche-back-end/repository"
+	"context"
+	"fmt"
+	"time"
+)
+
+// マイイベント一覧の 1 ページあたりの件数
+const (
+	DefaultMyEventsLimit = 20
+	MaxMyEventsLimit     = 100
+)
+
+// イベントにおけるユーザーの役割
+const (
+	EventRoleHost        = "host"
+	EventRoleParticipant = "participant"
+)
+
+// ListMyEventsInput はマイイベント一覧の取得条件を表す
+type ListMyEventsInput struct {
+	UserID string
+	Status *int64
+	Limit  int
+	Offset int
+}
+
+// MyEventSummary はマイイベント一覧の 1 件分を表す
+type MyEventSummary struct {
+	EventID          int64
+	Title            string
+	Role             string
+	Status           int64
+	VotedCount       int
+	DeclinedCount    int
+	ParticipantCount int64
+	DecidedStart     *time.Time
+	DecidedEnd       *time.Time
+	CreatedAt        time.Time
+}
+
+// ListMyEvents はユーザーが主催または参加しているイベントを投票状況とともに返す
+func ListMyEvents(ctx context.Context, in ListMyEventsInput) ([]MyEventSummary, int64, error) {
+	if in.UserID == "" {
+		return nil, 0, fmt.Errorf("userID is required")
+	}
+	if in.Limit <= 0 {
+		in.Limit = DefaultMyEventsLimit
+	}
+	if in.Limit > MaxMyEventsLimit {
+		in.Limit = MaxMyEventsLimit
+	}
+	if in.Offset < 0 {
+		in.Offset = 0
+	}
+
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	evs, total, err := repo.ListEventsByUserID(ctx, repository.EventListFilter{
+		UserID: in.UserID,
+		Status: in.Status,
+		Limit:  in.Limit,
+		Offset: in.Offset,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]int64, 0, len(evs))
+	for _, ev := range evs {
+		ids = append(ids, ev.ID)
+	}
+	voted, err := repo.CountDistinctAvailabilityUsersByEventIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	declined, err := repo.CountEventParticipantsByStatus(ctx, ids, repository.ParticipantStatusDeclined)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]MyEventSummary, 0, len(evs))
+	for _, ev := range evs {
+		role := EventRoleParticipant
+		if ev.HostUserID == in.UserID {
+			role = EventRoleHost
+		}
+		item := MyEventSummary{
+			EventID:          ev.ID,
+			Title:            ev.Title,
+			Role:             role,
+			Status:           ev.Status,
+			VotedCount:       voted[ev.ID],
+			DeclinedCount:    declined[ev.ID],
+			ParticipantCount: ev.ParticipantCount,
+			CreatedAt:        ev.CreatedAt,
+		}
+		if ev.DecidedStart.Valid {
+			t := ev.DecidedStart.Time
+			item.DecidedStart = &t
+		}
+		if ev.DecidedEnd.Valid {
+			t := ev.DecidedEnd.Time
+			item.DecidedEnd = &t
+		}
+		items = append(items, item)
+	}
+	return items, total, nil
+}
+
+// EventStatusName はイベントのステータスを API で使う名前に変換する
+func EventStatusName(status int64) string {
+	switch status {
+	case repository.EventStatusDraft:
+		return "draft"
+	case repository.EventStatusOpen:
+		return "open"
+	case repository.EventStatusClosed:
+		return "closed"
+	case repository.EventStatusCanceled:
+		return "canceled"
+	}
+	return "unknown"
+}
+
+// ParseEventStatus は API で使うステータス名をイベントのステータスに変換する
+func ParseEventStatus(name string) (int64, bool) {
+	switch name {
+	case "draft":
+		return repository.EventStatusDraft, true
+	case "open":
+		return repository.EventStatusOpen, true
+	case "closed":
+		return repository.EventStatusClosed, true
+	case "canceled":
+		return repository.EventStatusCanceled, true
+	}
+	return 0, false
+}
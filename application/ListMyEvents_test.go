@@ -0,0 +1,120 @@
+package application
+
+import (
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/repository/repositorytest"
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+const testMyEventsUserID = "me"
+
+// expectListMyEvents は ListEventsByUserID の件数と一覧のクエリを期待する
+// status が nil でなければステータスで絞り込み、一覧には limit・offset を渡す（offset が 0 なら OFFSET は付かない）
+func expectListMyEvents(mock sqlmock.Sqlmock, status *int64, limit, offset int, total int64, rows *sqlmock.Rows) {
+	where := `WHERE \(host_user_id = \$1 OR id IN \(SELECT event_id FROM "EventParticipants" WHERE user_id = \$2\)\)`
+	args := []driver.Value{testMyEventsUserID, testMyEventsUserID}
+	if status != nil {
+		where = `WHERE \(` + where[len(`WHERE `):] + `\) AND status = \$3`
+		args = append(args, *status)
+	}
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "Events" ` + where + `$`).
+		WithArgs(args...).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(total))
+	page := append(args, limit)
+	if offset > 0 {
+		page = append(page, offset)
+	}
+	mock.ExpectQuery(`SELECT \* FROM "Events" ` + where + ` ORDER BY created_at DESC,id DESC LIMIT`).
+		WithArgs(page...).
+		WillReturnRows(rows)
+}
+
+func TestListMyEventsFiltersByStatus(t *testing.T) {
+	open := int64(repository.EventStatusOpen)
+	mock := repositorytest.UseMock(t)
+	expectListMyEvents(mock, &open, DefaultMyEventsLimit, 0, 1, sqlmock.NewRows(eventColumns).
+		AddRow(1, testMyEventsUserID, "定例会", nil, 3, open, nil, nil, time.Now(), time.Now()))
+	mock.ExpectQuery(`SELECT event_id, COUNT\(DISTINCT user_id\) AS cnt FROM "Availabilities"`).
+		WillReturnRows(sqlmock.NewRows([]string{"event_id", "cnt"}))
+	mock.ExpectQuery(`SELECT event_id, COUNT\(\*\) AS cnt FROM "EventParticipants"`).
+		WillReturnRows(sqlmock.NewRows([]string{"event_id", "cnt"}))
+
+	items, total, err := ListMyEvents(context.Background(), ListMyEventsInput{UserID: testMyEventsUserID, Status: &open})
+	if err != nil {
+		t.Fatalf("ListMyEvents() error = %v", err)
+	}
+	if total != 1 || len(items) != 1 || items[0].Status != open {
+		t.Errorf("items = %+v, total = %d", items, total)
+	}
+}
+
+func TestListMyEventsClampsPagination(t *testing.T) {
+	tests := []struct {
+		name                  string
+		limit, offset         int
+		wantLimit, wantOffset int
+	}{
+		{"default limit", 0, 0, DefaultMyEventsLimit, 0},
+		{"limit above max", MaxMyEventsLimit + 1, 40, MaxMyEventsLimit, 40},
+		// 負のオフセットは 0 に丸め、1 ページ目として OFFSET を付けずに取得する
+		{"negative offset", 5, -1, 5, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := repositorytest.UseMock(t)
+			// 総件数は 1 ページに収まらなくても、ページングを考慮せずに返す
+			expectListMyEvents(mock, nil, tt.wantLimit, tt.wantOffset, 250, sqlmock.NewRows(eventColumns))
+
+			items, total, err := ListMyEvents(context.Background(), ListMyEventsInput{UserID: testMyEventsUserID, Limit: tt.limit, Offset: tt.offset})
+			if err != nil {
+				t.Fatalf("ListMyEvents() error = %v", err)
+			}
+			if total != 250 || len(items) != 0 {
+				t.Errorf("items = %+v, total = %d, want none of 250", items, total)
+			}
+		})
+	}
+}
+
+func TestListMyEventsRolesAndCounts(t *testing.T) {
+	decided := time.Date(2026, 11, 2, 10, 0, 0, 0, testLoc)
+	mock := repositorytest.UseMock(t)
+	expectListMyEvents(mock, nil, DefaultMyEventsLimit, 0, 2, sqlmock.NewRows(eventColumns).
+		AddRow(2, "someone-else", "勉強会", nil, 5, repository.EventStatusClosed, decided, decided.Add(time.Hour), time.Now(), time.Now()).
+		AddRow(1, testMyEventsUserID, "定例会", nil, 3, repository.EventStatusOpen, nil, nil, time.Now(), time.Now()))
+	mock.ExpectQuery(`SELECT event_id, COUNT\(DISTINCT user_id\) AS cnt FROM "Availabilities" WHERE event_id IN \(\$1,\$2\)`).
+		WithArgs(int64(2), int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"event_id", "cnt"}).AddRow(2, 4).AddRow(1, 1))
+	mock.ExpectQuery(`SELECT event_id, COUNT\(\*\) AS cnt FROM "EventParticipants" WHERE event_id IN \(\$1,\$2\) AND status = \$3`).
+		WithArgs(int64(2), int64(1), repository.ParticipantStatusDeclined).
+		WillReturnRows(sqlmock.NewRows([]string{"event_id", "cnt"}).AddRow(2, 1))
+
+	items, _, err := ListMyEvents(context.Background(), ListMyEventsInput{UserID: testMyEventsUserID})
+	if err != nil {
+		t.Fatalf("ListMyEvents() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("items = %+v, want 2", items)
+	}
+	participant, host := items[0], items[1]
+	if participant.Role != EventRoleParticipant || participant.VotedCount != 4 || participant.DeclinedCount != 1 ||
+		participant.DecidedStart == nil || !participant.DecidedStart.Equal(decided) {
+		t.Errorf("participant event = %+v", participant)
+	}
+	if host.Role != EventRoleHost || host.VotedCount != 1 || host.DeclinedCount != 0 || host.DecidedStart != nil {
+		t.Errorf("host event = %+v", host)
+	}
+}
+
+func TestListMyEventsRequiresUserID(t *testing.T) {
+	repositorytest.UseMock(t)
+
+	if _, _, err := ListMyEvents(context.Background(), ListMyEventsInput{}); err == nil {
+		t.Error("ListMyEvents() without a user ID returned no error")
+	}
+}
@@ -0,0 +1,60 @@
+package application
+
+import (
+	"adjuSche-back-end/domain"
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"context"
+	"log"
+	"time"
+)
+
+// ErrLineAccountNotLinked は LINE アカウントがアプリのユーザーに連携されていないことを表す
+var ErrLineAccountNotLinked = domain.Forbidden("line_account_not_linked", "LINEアカウントが連携されていません。LINEで「アカウント連携」と送信してください")
+
+// ErrInvalidLineIDToken は LIFF の ID トークンの検証に失敗したことを表す
+var ErrInvalidLineIDToken = domain.Unauthorized("invalid_line_id_token", "IDトークンの検証に失敗しました")
+
+// LiffSession は LIFF からのログインで発行したセッション
+type LiffSession struct {
+	UserID       string
+	LineUserID   string
+	DisplayName  string
+	PictureURL   string
+	SessionToken string
+	ExpiresAt    time.Time
+}
+
+// LoginWithLineIDToken は LIFF の ID トークンを検証し、連携済みのユーザーに対してセッションを発行する
+func LoginWithLineIDToken(ctx context.Context, idToken string) (LiffSession, error) {
+	claims, err := servise.VerifyLineIDToken(ctx, idToken)
+	if err != nil {
+		return LiffSession{}, ErrInvalidLineIDToken.Wrap(err)
+	}
+
+	userID, err := GetUserIDByLineUserID(ctx, claims.Sub)
+	if err != nil {
+		return LiffSession{}, err
+	}
+
+	// LINE のプロフィールで表示名・アイコンの未設定の項目を補う。失敗してもログインは続ける
+	if repo, err := repository.NewSupabaseRepository(); err != nil {
+		log.Printf("プロフィールの同期に失敗しました: %v", err)
+	} else if err := syncUserProfile(ctx, repo, userID, providerProfile{DisplayName: claims.Name, AvatarURL: claims.Picture}); err != nil {
+		log.Printf("プロフィールの同期に失敗しました: userID=%s, err=%v", userID, err)
+	}
+
+	token, expiresAt, err := servise.IssueSessionToken(userID, time.Now())
+	if err != nil {
+		return LiffSession{}, err
+	}
+
+	return LiffSession{
+		UserID:       userID,
+		LineUserID:   claims.Sub,
+		DisplayName:  claims.Name,
+		PictureURL:   claims.Picture,
+		SessionToken: token,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
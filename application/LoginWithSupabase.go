@@ -0,0 +1,42 @@
+package application
+
+import (
+	"adjuSche-back-end/domain"
+	"adjuSche-back-end/servise"
+	"context"
+	"time"
+)
+
+// ErrInvalidAccessToken は Supabase のアクセストークンの検証に失敗したことを表す
+var ErrInvalidAccessToken = domain.Unauthorized("invalid_access_token", "アクセストークンの検証に失敗しました")
+
+// AppSession はアプリのセッション
+type AppSession struct {
+	UserID       string
+	SessionToken string
+	ExpiresAt    time.Time
+}
+
+// AuthenticateSupabaseUser はフロントエンドがサインインで得た Supabase のアクセストークンを検証し、ユーザーIDを返す
+func AuthenticateSupabaseUser(ctx context.Context, accessToken string) (string, error) {
+	user, err := servise.VerifySupabaseAccessToken(ctx, accessToken)
+	if err != nil {
+		return "", ErrInvalidAccessToken.Wrap(err)
+	}
+	return user.ID, nil
+}
+
+// LoginWithSupabaseToken は Supabase のアクセストークンを検証してアプリのセッションを発行する
+// LINE アカウントの連携前でもセッションを得られるようにする、最初のログインの入り口
+func LoginWithSupabaseToken(ctx context.Context, accessToken string) (AppSession, error) {
+	userID, err := AuthenticateSupabaseUser(ctx, accessToken)
+	if err != nil {
+		return AppSession{}, err
+	}
+
+	token, expiresAt, err := servise.IssueSessionToken(userID, time.Now())
+	if err != nil {
+		return AppSession{}, err
+	}
+	return AppSession{UserID: userID, SessionToken: token, ExpiresAt: expiresAt}, nil
+}
@@ -0,0 +1,404 @@
+package application
+
+import (
+	"adjuSche-back-end/domain"
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrNotEventHost は主催者以外がイベントを操作しようとしたことを表す
+	ErrNotEventHost = domain.Forbidden("not_event_host", "イベントの主催者のみ操作できます")
+	// ErrEventCanceled は中止済みのイベントを変更しようとしたことを表す
+	ErrEventCanceled = domain.Closed("event_canceled", "中止されたイベントは変更できません")
+)
+
+// EventDetail はイベントと最新の条件を表す
+type EventDetail struct {
+	Event     repository.Events
+	Condition repository.EventCondition
+}
+
+// UpdateEventInput はイベントの更新内容を表す（nil の項目は変更しない）
+type UpdateEventInput struct {
+	EventID          int64
+	UserID           string
+	Title            *string
+	Memo             *string
+	ParticipantCount *int
+}
+
+// UpdateEventConditionInput はイベント条件の更新内容を表す（nil の項目は変更しない）
+// TimeStart / TimeEnd に空文字を指定すると終日扱いに戻す
+type UpdateEventConditionInput struct {
+	EventID     int64
+	UserID      string
+	PeriodStart *string
+	PeriodEnd   *string
+	TimeStart   *string
+	TimeEnd     *string
+	DurationMin *int
+}
+
+// FinalizeEventInput は確定する日時を表す（RFC3339 形式）
+type FinalizeEventInput struct {
+	EventID int64
+	UserID  string
+	Start   string
+	End     string
+}
+
+// GetEventDetail は主催者向けにイベントと最新の条件を返す
+func GetEventDetail(ctx context.Context, eventID int64, userID string) (EventDetail, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return EventDetail{}, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	ev, err := getEventAsHost(ctx, repo, eventID, userID)
+	if err != nil {
+		return EventDetail{}, err
+	}
+	cond, err := repo.GetEventConditionByEventID(ctx, eventID)
+	if err != nil {
+		return EventDetail{}, err
+	}
+	return EventDetail{Event: *ev, Condition: *cond}, nil
+}
+
+// ListEventConditionHistory は主催者向けにイベント条件の全バージョンを新しい順に返す
+func ListEventConditionHistory(ctx context.Context, eventID int64, userID string) ([]repository.EventCondition, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	if _, err := getEventAsHost(ctx, repo, eventID, userID); err != nil {
+		return nil, err
+	}
+	return repo.ListEventConditionsByEventID(ctx, eventID)
+}
+
+// UpdateEvent はイベントのタイトル・メモ・参加人数を更新する
+func UpdateEvent(ctx context.Context, in UpdateEventInput) (EventDetail, error) {
+	verr := &validationErrors{}
+	if in.Title != nil && strings.TrimSpace(*in.Title) == "" {
+		verr.add("title", FieldErrorRequired, "タイトルを入力してください")
+	}
+	if in.ParticipantCount != nil && *in.ParticipantCount <= 0 {
+		verr.add("participantCount", FieldErrorMustBePositive, "参加人数は1以上で指定してください")
+	}
+	if err := verr.err(); err != nil {
+		return EventDetail{}, err
+	}
+
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return EventDetail{}, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	ev, err := getEventAsHost(ctx, repo, in.EventID, in.UserID)
+	if err != nil {
+		return EventDetail{}, err
+	}
+	if ev.Status == repository.EventStatusCanceled {
+		return EventDetail{}, ErrEventCanceled
+	}
+
+	if in.Title != nil {
+		ev.Title = *in.Title
+	}
+	if in.Memo != nil {
+		ev.Note = sql.NullString{String: *in.Memo, Valid: *in.Memo != ""}
+	}
+	if in.ParticipantCount != nil {
+		ev.ParticipantCount = int64(*in.ParticipantCount)
+	}
+	ev.UpdatedAt = time.Now()
+	if err := repo.UpdateEvent(ctx, ev); err != nil {
+		return EventDetail{}, err
+	}
+
+	cond, err := repo.GetEventConditionByEventID(ctx, in.EventID)
+	if err != nil {
+		return EventDetail{}, err
+	}
+	return EventDetail{Event: *ev, Condition: *cond}, nil
+}
+
+// UpdateEventCondition はイベント条件の新しいバージョンを作成する
+// 期間が変わった場合は、新しい期間から外れた空き時間と確定日時を無効にする
+func UpdateEventCondition(ctx context.Context, in UpdateEventConditionInput) (EventDetail, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return EventDetail{}, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	ev, err := getEventAsHost(ctx, repo, in.EventID, in.UserID)
+	if err != nil {
+		return EventDetail{}, err
+	}
+	if ev.Status == repository.EventStatusCanceled {
+		return EventDetail{}, ErrEventCanceled
+	}
+
+	prev, err := repo.GetEventConditionByEventID(ctx, in.EventID)
+	if err != nil {
+		return EventDetail{}, err
+	}
+
+	periodStart := prev.PeriodStart.Format(time.RFC3339)
+	if in.PeriodStart != nil {
+		periodStart = *in.PeriodStart
+	}
+	periodEnd := prev.PeriodEnd.Format(time.RFC3339)
+	if in.PeriodEnd != nil {
+		periodEnd = *in.PeriodEnd
+	}
+	timeStart := prev.TimeStart.String
+	if in.TimeStart != nil {
+		timeStart = *in.TimeStart
+	}
+	timeEnd := prev.TimeEnd.String
+	if in.TimeEnd != nil {
+		timeEnd = *in.TimeEnd
+	}
+	durationMin := prev.DurationMin
+	if in.DurationMin != nil {
+		durationMin = *in.DurationMin
+	}
+
+	if err := validateEventCondition("", periodStart, periodEnd, timeStart, timeEnd, durationMin); err != nil {
+		return EventDetail{}, err
+	}
+
+	now := time.Now()
+	cond, err := buildEventCondition(in.EventID, periodStart, periodEnd, timeStart, timeEnd, durationMin, now)
+	if err != nil {
+		return EventDetail{}, err
+	}
+	windowChanged := !cond.PeriodStart.Equal(prev.PeriodStart) || !cond.PeriodEnd.Equal(prev.PeriodEnd)
+	if windowChanged && ev.DecidedStart.Valid && (ev.DecidedStart.Time.Before(cond.PeriodStart) || ev.DecidedEnd.Time.After(cond.PeriodEnd)) {
+		ev.DecidedStart = sql.NullTime{}
+		ev.DecidedEnd = sql.NullTime{}
+	}
+	ev.UpdatedAt = now
+
+	// 条件の追加・空き時間の無効化・イベントの更新をまとめてコミットする
+	err = repo.UnitOfWork(ctx, func(tx *repository.SupabaseRepositoryImpl) error {
+		if err := tx.CreateEventCondition(ctx, cond); err != nil {
+			return err
+		}
+		if windowChanged || conditionSlotsChanged(prev, cond) {
+			if err := invalidateStaleAvailabilities(ctx, tx, in.EventID, cond); err != nil {
+				return err
+			}
+		}
+		return tx.UpdateEvent(ctx, ev)
+	})
+	if err != nil {
+		return EventDetail{}, err
+	}
+	// 期間や所要時間が変わると候補日時も変わる
+	publishEventUpdated(in.EventID)
+	return EventDetail{Event: *ev, Condition: *cond}, nil
+}
+
+// FinalizeEvent は日時を確定し、イベントを締切状態にする
+// 確定後に呼び出した場合は確定日時を変更する
+func FinalizeEvent(ctx context.Context, in FinalizeEventInput) (EventDetail, error) {
+	verr := &validationErrors{}
+	start, startErr := time.Parse(time.RFC3339, in.Start)
+	if in.Start == "" {
+		verr.add("start", FieldErrorRequired, "確定する開始日時を指定してください")
+	} else if startErr != nil {
+		verr.add("start", FieldErrorInvalidFormat, "確定する開始日時は RFC3339 形式で指定してください")
+	}
+	end, endErr := time.Parse(time.RFC3339, in.End)
+	if in.End == "" {
+		verr.add("end", FieldErrorRequired, "確定する終了日時を指定してください")
+	} else if endErr != nil {
+		verr.add("end", FieldErrorInvalidFormat, "確定する終了日時は RFC3339 形式で指定してください")
+	}
+	if startErr == nil && endErr == nil && !end.After(start) {
+		verr.add("end", FieldErrorTimeOrder, "終了日時は開始日時より後を指定してください")
+	}
+	if err := verr.err(); err != nil {
+		return EventDetail{}, err
+	}
+
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return EventDetail{}, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	ev, err := getEventAsHost(ctx, repo, in.EventID, in.UserID)
+	if err != nil {
+		return EventDetail{}, err
+	}
+	if ev.Status == repository.EventStatusCanceled {
+		return EventDetail{}, ErrEventCanceled
+	}
+
+	cond, err := repo.GetEventConditionByEventID(ctx, in.EventID)
+	if err != nil {
+		return EventDetail{}, err
+	}
+	if start.Before(cond.PeriodStart) || end.After(cond.PeriodEnd) {
+		verr.add("start", FieldErrorOutsidePeriod, "確定する日時はイベントの期間内で指定してください")
+		return EventDetail{}, verr.err()
+	}
+
+	ev.DecidedStart = sql.NullTime{Time: start, Valid: true}
+	ev.DecidedEnd = sql.NullTime{Time: end, Valid: true}
+	ev.Status = repository.EventStatusClosed
+	ev.UpdatedAt = time.Now()
+	if err := repo.UpdateEvent(ctx, ev); err != nil {
+		return EventDetail{}, err
+	}
+	notifyEventFinalized(ev)
+	return EventDetail{Event: *ev, Condition: *cond}, nil
+}
+
+// CancelEvent はイベントを中止状態にする（レコードは残す）
+func CancelEvent(ctx context.Context, eventID int64, userID string) error {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	ev, err := getEventAsHost(ctx, repo, eventID, userID)
+	if err != nil {
+		return err
+	}
+	if ev.Status == repository.EventStatusCanceled {
+		return nil
+	}
+
+	ev.Status = repository.EventStatusCanceled
+	ev.UpdatedAt = time.Now()
+	return repo.UpdateEvent(ctx, ev)
+}
+
+// DeleteEvent はイベントと関連するデータを削除する
+func DeleteEvent(ctx context.Context, eventID int64, userID string) error {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	if _, err := getEventAsHost(ctx, repo, eventID, userID); err != nil {
+		return err
+	}
+	return repo.DeleteEvent(ctx, eventID)
+}
+
+// getEventAsHost はイベントを取得し、userID が主催者であることを確認する
+func getEventAsHost(ctx context.Context, repo *repository.SupabaseRepositoryImpl, eventID int64, userID string) (*repository.Events, error) {
+	ev, err := repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if ev.HostUserID != userID {
+		return nil, ErrNotEventHost
+	}
+	return ev, nil
+}
+
+// conditionSlotsChanged は時間帯・所要時間が変わり、提出済みの空き時間を見直す必要があるかを返す
+func conditionSlotsChanged(prev, cond *repository.EventCondition) bool {
+	return prev.TimeStart != cond.TimeStart || prev.TimeEnd != cond.TimeEnd || prev.DurationMin != cond.DurationMin
+}
+
+// invalidateStaleAvailabilities は新しい条件から外れた空き時間を削除し、はみ出した部分を切り詰める
+// 期間と 1 日の時間帯の外は切り詰め、切り詰めた結果が所要時間に満たない空き時間は削除する
+func invalidateStaleAvailabilities(ctx context.Context, repo *repository.SupabaseRepositoryImpl, eventID int64, cond *repository.EventCondition) error {
+	avs, err := repo.ListAvailabilitiesByEventID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if len(avs) == 0 {
+		return nil
+	}
+	loc, err := time.LoadLocation(servise.ICalTimeZone)
+	if err != nil {
+		return fmt.Errorf("failed to load time zone: %w", err)
+	}
+
+	kept := make([]repository.Availability, 0, len(avs))
+	for _, av := range avs {
+		kept = append(kept, clipAvailabilityToCondition(av, cond, loc)...)
+	}
+	return repo.ReplaceAvailabilitiesForEvent(ctx, eventID, kept)
+}
+
+// clipAvailabilityToCondition は空き時間を条件の期間と 1 日の時間帯（loc の時刻）に切り詰め、所要時間以上残る部分を返す
+// 時間帯で切り詰めると日ごとに分かれるため、複数の空き時間になることがある
+func clipAvailabilityToCondition(av repository.Availability, cond *repository.EventCondition, loc *time.Location) []repository.Availability {
+	s, err := time.Parse(time.RFC3339, av.AvailableStart)
+	if err != nil {
+		return nil
+	}
+	e, err := time.Parse(time.RFC3339, av.AvailableEnd)
+	if err != nil {
+		return nil
+	}
+	if s.Before(cond.PeriodStart) {
+		s = cond.PeriodStart
+	}
+	if e.After(cond.PeriodEnd) {
+		e = cond.PeriodEnd
+	}
+	if !e.After(s) {
+		return nil
+	}
+
+	pieces := []TimeSlot{{Start: s, End: e}}
+	if cond.TimeStart.Valid && cond.TimeEnd.Valid {
+		ts, tsOK := parseMinuteOfDay(cond.TimeStart.String, false)
+		te, teOK := parseMinuteOfDay(cond.TimeEnd.String, true)
+		if tsOK && teOK && te > ts {
+			pieces = clipToDailyWindow(s, e, ts, te, loc)
+		}
+	}
+
+	minDuration := time.Duration(cond.DurationMin) * time.Minute
+	var kept []repository.Availability
+	for _, p := range pieces {
+		if p.End.Sub(p.Start) < minDuration {
+			continue
+		}
+		clipped := av
+		clipped.ID = 0
+		clipped.AvailableDate = p.Start.In(loc).Format("2006-01-02")
+		clipped.AvailableStart = p.Start.In(loc).Format(time.RFC3339)
+		clipped.AvailableEnd = p.End.In(loc).Format(time.RFC3339)
+		kept = append(kept, clipped)
+	}
+	return kept
+}
+
+// clipToDailyWindow は [start, end) を、毎日の startMin 分から endMin 分まで（loc の時刻）の部分に分けて返す
+func clipToDailyWindow(start, end time.Time, startMin, endMin int, loc *time.Location) []TimeSlot {
+	var pieces []TimeSlot
+	ls := start.In(loc)
+	for day := time.Date(ls.Year(), ls.Month(), ls.Day(), 0, 0, 0, 0, loc); day.Before(end); day = day.AddDate(0, 0, 1) {
+		ws := day.Add(time.Duration(startMin) * time.Minute)
+		we := day.Add(time.Duration(endMin) * time.Minute)
+		if ws.Before(start) {
+			ws = start
+		}
+		if we.After(end) {
+			we = end
+		}
+		if we.After(ws) {
+			pieces = append(pieces, TimeSlot{Start: ws, End: we})
+		}
+	}
+	return pieces
+}
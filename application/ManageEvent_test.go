@@ -0,0 +1,85 @@
+package application
+
+import (
+	"adjuSche-back-end/repository"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+func TestConditionSlotsChanged(t *testing.T) {
+	base := repository.EventCondition{
+		TimeStart:   sql.NullString{String: "09:00", Valid: true},
+		TimeEnd:     sql.NullString{String: "18:00", Valid: true},
+		DurationMin: 60,
+	}
+	tests := []struct {
+		name   string
+		modify func(c *repository.EventCondition)
+		want   bool
+	}{
+		{"unchanged", func(c *repository.EventCondition) {}, false},
+		{"time start", func(c *repository.EventCondition) { c.TimeStart.String = "10:00" }, true},
+		{"time end", func(c *repository.EventCondition) { c.TimeEnd.String = "17:00" }, true},
+		{"back to all day", func(c *repository.EventCondition) { c.TimeStart, c.TimeEnd = sql.NullString{}, sql.NullString{} }, true},
+		{"duration", func(c *repository.EventCondition) { c.DurationMin = 90 }, true},
+	}
+	for _, tt := range tests {
+		cond := base
+		tt.modify(&cond)
+		if got := conditionSlotsChanged(&base, &cond); got != tt.want {
+			t.Errorf("%s: conditionSlotsChanged = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClipAvailabilityToCondition(t *testing.T) {
+	day := func(d, hour, min int) time.Time {
+		return time.Date(2026, 11, d, hour, min, 0, 0, testLoc)
+	}
+	cond := &repository.EventCondition{
+		PeriodStart: day(2, 0, 0),
+		PeriodEnd:   day(5, 0, 0),
+		TimeStart:   sql.NullString{String: "10:00", Valid: true},
+		TimeEnd:     sql.NullString{String: "12:00", Valid: true},
+		DurationMin: 60,
+	}
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       [][2]time.Time
+	}{
+		{"inside the window", day(2, 10, 0), day(2, 11, 30), [][2]time.Time{{day(2, 10, 0), day(2, 11, 30)}}},
+		{"clipped to the window", day(2, 8, 0), day(2, 15, 0), [][2]time.Time{{day(2, 10, 0), day(2, 12, 0)}}},
+		{"split across days", day(2, 11, 0), day(3, 11, 0), [][2]time.Time{{day(2, 11, 0), day(2, 12, 0)}, {day(3, 10, 0), day(3, 11, 0)}}},
+		{"too short after clipping", day(2, 11, 30), day(2, 13, 0), nil},
+		{"outside the window", day(2, 13, 0), day(2, 18, 0), nil},
+		{"outside the period", day(1, 10, 0), day(1, 12, 0), nil},
+		{"clipped to the period", day(4, 10, 0), day(5, 12, 0), [][2]time.Time{{day(4, 10, 0), day(4, 12, 0)}}},
+	}
+	for _, tt := range tests {
+		av := testAvailability("u1", 1, tt.start, tt.end)
+		av.ID = 42
+		got := clipAvailabilityToCondition(av, cond, testLoc)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %d availabilities, want %d: %+v", tt.name, len(got), len(tt.want), got)
+			continue
+		}
+		for i, w := range tt.want {
+			g := got[i]
+			if g.ID != 0 || g.UserID != "u1" || g.Sourse != 1 {
+				t.Errorf("%s[%d]: got %+v, want a new availability of u1 with the same source", tt.name, i, g)
+			}
+			if g.AvailableStart != w[0].Format(time.RFC3339) || g.AvailableEnd != w[1].Format(time.RFC3339) || g.AvailableDate != w[0].Format("2006-01-02") {
+				t.Errorf("%s[%d]: got %s %s-%s, want %s-%s", tt.name, i, g.AvailableDate, g.AvailableStart, g.AvailableEnd, w[0].Format(time.RFC3339), w[1].Format(time.RFC3339))
+			}
+		}
+	}
+
+	allDay := *cond
+	allDay.TimeStart, allDay.TimeEnd = sql.NullString{}, sql.NullString{}
+	got := clipAvailabilityToCondition(testAvailability("u1", 1, day(2, 22, 0), day(3, 2, 0)), &allDay, testLoc)
+	if len(got) != 1 || got[0].AvailableEnd != day(3, 2, 0).Format(time.RFC3339) {
+		t.Errorf("all-day condition should keep the availability across midnight, got %+v", got)
+	}
+}
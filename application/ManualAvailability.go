@@ -0,0 +1,98 @@
+package application
+
+import (
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"context"
+	"fmt"
+	"time"
+)
+
+// maxManualAvailabilityNum は一度に手入力できる空き時間の上限
+const maxManualAvailabilityNum = 100
+
+// ManualInterval は手入力の空き時間（RFC3339 形式）
+type ManualInterval struct {
+	Start string
+	End   string
+}
+
+// SubmitManualAvailability はログインユーザーの手入力の空き時間を intervals で置き換える
+// カレンダー由来の空き時間は残し、繰り返しのルールと勤務時間の外の時間帯は除いて保存する
+func SubmitManualAvailability(ctx context.Context, eventID int64, userID string, intervals []ManualInterval) ([]servise.TimeInterval, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+	return saveManualAvailability(ctx, repo, eventID, userID, intervals)
+}
+
+// saveManualAvailability は手入力の空き時間を検証し、ユーザーの繰り返しのルールを適用して保存する
+func saveManualAvailability(ctx context.Context, repo *repository.SupabaseRepositoryImpl, eventID int64, userID string, intervals []ManualInterval) ([]servise.TimeInterval, error) {
+	ev, err := repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if ev.Status == repository.EventStatusCanceled {
+		return nil, ErrEventCanceled
+	}
+	cond, err := repo.GetEventConditionByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	free, err := parseManualIntervals(intervals, cond)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := loadWeeklySchedule(ctx, repo, userID)
+	if err != nil {
+		return nil, err
+	}
+	free = servise.ClipToWeeklySchedule(free, schedule, 0)
+
+	avs := newAvailabilities(eventID, userID, repository.AvailabilitySourceManual, free, time.Now())
+	if err := commitUserAvailabilities(ctx, repo, ev, userID, []int8{repository.AvailabilitySourceManual}, avs, nil); err != nil {
+		return nil, err
+	}
+	return free, nil
+}
+
+// parseManualIntervals は手入力の空き時間を検証して TimeInterval に変換する
+// 空き時間はイベントの期間内に収まっている必要がある
+func parseManualIntervals(intervals []ManualInterval, cond *repository.EventCondition) ([]servise.TimeInterval, error) {
+	verr := &validationErrors{}
+	if len(intervals) > maxManualAvailabilityNum {
+		verr.add("availabilities", FieldErrorTooMany, fmt.Sprintf("空き時間は%d件以内で指定してください", maxManualAvailabilityNum), maxManualAvailabilityNum)
+		return nil, verr.err()
+	}
+
+	free := make([]servise.TimeInterval, 0, len(intervals))
+	for i, iv := range intervals {
+		field := fmt.Sprintf("availabilities[%d]", i)
+		start, startErr := time.Parse(time.RFC3339, iv.Start)
+		if startErr != nil {
+			verr.add(field+".start", FieldErrorInvalidFormat, "開始日時は RFC3339 形式で指定してください")
+		}
+		end, endErr := time.Parse(time.RFC3339, iv.End)
+		if endErr != nil {
+			verr.add(field+".end", FieldErrorInvalidFormat, "終了日時は RFC3339 形式で指定してください")
+		}
+		if startErr != nil || endErr != nil {
+			continue
+		}
+		if !end.After(start) {
+			verr.add(field+".end", FieldErrorTimeOrder, "終了日時は開始日時より後を指定してください")
+			continue
+		}
+		if start.Before(cond.PeriodStart) || end.After(cond.PeriodEnd) {
+			verr.add(field+".start", FieldErrorOutsidePeriod, "日時はイベントの期間内で指定してください")
+			continue
+		}
+		free = append(free, servise.TimeInterval{Start: start, End: end})
+	}
+	if err := verr.err(); err != nil {
+		return nil, err
+	}
+	return free, nil
+}
@@ -0,0 +1,206 @@
+package application
+
+import (
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// availabilityPreviewTTL はプレビューを確定できる期間
+const availabilityPreviewTTL = 30 * time.Minute
+
+// AvailabilityPreview はカレンダーから求めた空き時間と、それを含めた場合の候補日時（まだ保存していない）
+type AvailabilityPreview struct {
+	Token          string // ConfirmAvailabilityPreview に渡すトークン
+	ExpiresAt      time.Time
+	Availabilities []servise.TimeInterval
+	Summary        InviteSummary
+	Slots          []PossibleSlot
+}
+
+// previewInterval は AvailabilityPreviews.intervals に保存する空き時間
+type previewInterval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// PreviewAvailabilityFromCalendar はユーザーのカレンダーから空き時間を求め、それを含めた場合の候補日時を返す
+// 参加者の登録も空き時間の保存もせず、確認後に ConfirmAvailabilityPreview で保存する
+// 確定までの間、求めた空き時間は下書きとして AvailabilityPreviews に availabilityPreviewTTL の間だけ残す
+// （確定時にカレンダーを読み直すと、確認した内容と保存する内容が食い違いうるため）
+func PreviewAvailabilityFromCalendar(ctx context.Context, in SubmitCalendarAvailabilityInput) (*AvailabilityPreview, error) {
+	verr := &validationErrors{}
+	if in.UserID == "" {
+		verr.add("userId", FieldErrorRequired, "ユーザーIDを指定してください")
+	}
+	if err := verr.err(); err != nil {
+		return nil, err
+	}
+	if err := in.Access.Validate(); err != nil {
+		return nil, err
+	}
+
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	ev, err := repo.GetEventByID(ctx, in.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if ev.Status == repository.EventStatusCanceled {
+		return nil, ErrEventCanceled
+	}
+	cond, err := repo.GetEventConditionByEventID(ctx, in.EventID)
+	if err != nil {
+		return nil, err
+	}
+
+	cal, err := in.Access.open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init calendar service: %w", err)
+	}
+	schedule, err := loadWeeklySchedule(ctx, repo, in.UserID)
+	if err != nil {
+		return nil, err
+	}
+	free, err := servise.GetFreeIntervals(ctx, servise.WithWeeklySchedule(cal, schedule), cond.PeriodStart, cond.PeriodEnd, cond.DurationMin)
+	if err != nil {
+		return nil, ErrCalendarAccessFailed.Wrap(err)
+	}
+	syncGoogleProfile(ctx, repo, in.UserID, in.Access, cal)
+
+	existing, err := repo.ListAvailabilitiesByEventID(ctx, in.EventID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	avs, voted := inviteAvailabilities(existing, in.EventID, in.UserID, in.Access.Source(), free, now)
+
+	declined, err := countDeclinedParticipants(ctx, repo, in.EventID)
+	if err != nil {
+		return nil, err
+	}
+	slots := calculateOverlappingSlots(avs, cond.DurationMin)
+	if err := nameSlotMembers(ctx, repo, in.EventID, slots); err != nil {
+		return nil, err
+	}
+
+	token, err := generateNonce()
+	if err != nil {
+		return nil, err
+	}
+	intervals := make([]previewInterval, 0, len(free))
+	for _, iv := range free {
+		intervals = append(intervals, previewInterval{Start: iv.Start, End: iv.End})
+	}
+	data, err := json.Marshal(intervals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode preview intervals: %w", err)
+	}
+	expiresAt := now.Add(availabilityPreviewTTL)
+	if err := repo.CreateAvailabilityPreview(ctx, &repository.AvailabilityPreview{
+		Token:     token,
+		EventID:   in.EventID,
+		UserID:    in.UserID,
+		Sourse:    in.Access.Source(),
+		Intervals: string(data),
+		CreatedAt: now,
+		ExpiredAt: expiresAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	return &AvailabilityPreview{
+		Token:          token,
+		ExpiresAt:      expiresAt,
+		Availabilities: free,
+		Summary:        newInviteSummary(ev, cond, voted, declined),
+		Slots:          slots,
+	}, nil
+}
+
+// previewAvailabilities はユーザーのカレンダー由来の空き時間を replacement に置き換えた場合の、イベントの全空き時間を返す
+// ReplaceUserAvailabilitiesForEvent と同じく、手入力の分は残す
+func previewAvailabilities(existing, replacement []repository.Availability, userID string) []repository.Availability {
+	avs := make([]repository.Availability, 0, len(existing)+len(replacement))
+	for _, av := range existing {
+		if av.UserID == userID && slices.Contains(repository.CalendarAvailabilitySources, av.Sourse) {
+			continue
+		}
+		avs = append(avs, av)
+	}
+	return append(avs, replacement...)
+}
+
+// countAvailabilityUsers は空き時間を提出したユーザーの数を返す
+func countAvailabilityUsers(avs []repository.Availability) int {
+	users := make(map[string]struct{})
+	for _, av := range avs {
+		users[av.UserID] = struct{}{}
+	}
+	return len(users)
+}
+
+// ConfirmAvailabilityInput はプレビューした空き時間の確定に必要な入力を表す
+type ConfirmAvailabilityInput struct {
+	EventID int64
+	UserID  string
+	Token   string
+}
+
+// ConfirmAvailabilityPreview はプレビューした空き時間を参加者の空き時間として保存する
+// カレンダーは読み直さず、プレビューで確認した内容をそのまま保存する
+func ConfirmAvailabilityPreview(ctx context.Context, in ConfirmAvailabilityInput) ([]servise.TimeInterval, error) {
+	verr := &validationErrors{}
+	if in.UserID == "" {
+		verr.add("userId", FieldErrorRequired, "ユーザーIDを指定してください")
+	}
+	if in.Token == "" {
+		verr.add("previewToken", FieldErrorRequired, "プレビューのトークンを指定してください")
+	}
+	if err := verr.err(); err != nil {
+		return nil, err
+	}
+
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	preview, err := repo.GetAvailabilityPreview(ctx, in.Token, in.EventID, in.UserID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	ev, err := repo.GetEventByID(ctx, in.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if ev.Status == repository.EventStatusCanceled {
+		return nil, ErrEventCanceled
+	}
+
+	var intervals []previewInterval
+	if err := json.Unmarshal([]byte(preview.Intervals), &intervals); err != nil {
+		return nil, fmt.Errorf("failed to decode preview intervals: %w", err)
+	}
+	free := make([]servise.TimeInterval, 0, len(intervals))
+	for _, iv := range intervals {
+		free = append(free, servise.TimeInterval{Start: iv.Start, End: iv.End})
+	}
+
+	avs := newAvailabilities(in.EventID, in.UserID, preview.Sourse, free, time.Now())
+	err = commitUserAvailabilities(ctx, repo, ev, in.UserID, repository.CalendarAvailabilitySources, avs, func(tx *repository.SupabaseRepositoryImpl) error {
+		// 同じプレビューを二重に確定しないよう、保存と同じトランザクションで削除する
+		return tx.DeleteAvailabilityPreview(ctx, preview.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return free, nil
+}
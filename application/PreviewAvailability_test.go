@@ -0,0 +1,94 @@
+package application
+
+import (
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/repository/repositorytest"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+const (
+	testPreviewUserID = "preview-user"
+	testPreviewToken  = "preview-token"
+)
+
+var previewColumns = []string{"id", "token", "event_id", "user_id", "sourse", "intervals", "created_at", "expired_at"}
+
+// expectPreviewLookup はプレビューの検索で、トークン・イベント・ユーザー・現在時刻の条件が渡されることを確かめる
+func expectPreviewLookup(mock sqlmock.Sqlmock, userID string) *sqlmock.ExpectedQuery {
+	return mock.ExpectQuery(`SELECT \* FROM "AvailabilityPreviews" WHERE token = \$1 AND event_id = \$2 AND user_id = \$3 AND expired_at > \$4`).
+		WithArgs(testPreviewToken, int64(42), userID, recentTime{}, 1)
+}
+
+func TestConfirmAvailabilityPreviewRejectsExpiredPreview(t *testing.T) {
+	mock := repositorytest.UseMock(t)
+	// 有効期限の切れたプレビューは expired_at > 現在時刻 の条件で見つからない
+	expectPreviewLookup(mock, testPreviewUserID).WillReturnRows(sqlmock.NewRows(previewColumns))
+
+	_, err := ConfirmAvailabilityPreview(context.Background(), ConfirmAvailabilityInput{EventID: 42, UserID: testPreviewUserID, Token: testPreviewToken})
+	if !errors.Is(err, repository.ErrPreviewNotFound) {
+		t.Errorf("got %v, want ErrPreviewNotFound", err)
+	}
+}
+
+func TestConfirmAvailabilityPreviewRejectsAnotherUsersToken(t *testing.T) {
+	mock := repositorytest.UseMock(t)
+	// 他のユーザーのプレビューは、確定するユーザーの user_id の条件で見つからない
+	expectPreviewLookup(mock, "another-user").WillReturnRows(sqlmock.NewRows(previewColumns))
+
+	_, err := ConfirmAvailabilityPreview(context.Background(), ConfirmAvailabilityInput{EventID: 42, UserID: "another-user", Token: testPreviewToken})
+	if !errors.Is(err, repository.ErrPreviewNotFound) {
+		t.Errorf("got %v, want ErrPreviewNotFound", err)
+	}
+}
+
+func TestConfirmAvailabilityPreviewStoresPreviewedIntervals(t *testing.T) {
+	first := [2]time.Time{time.Date(2026, 11, 2, 10, 0, 0, 0, testLoc), time.Date(2026, 11, 2, 12, 0, 0, 0, testLoc)}
+	second := [2]time.Time{time.Date(2026, 11, 3, 14, 30, 0, 0, testLoc), time.Date(2026, 11, 3, 16, 0, 0, 0, testLoc)}
+	intervals := `[{"start":"2026-11-02T10:00:00+09:00","end":"2026-11-02T12:00:00+09:00"},` +
+		`{"start":"2026-11-03T14:30:00+09:00","end":"2026-11-03T16:00:00+09:00"}]`
+
+	mock := repositorytest.UseMock(t)
+	expectPreviewLookup(mock, testPreviewUserID).WillReturnRows(sqlmock.NewRows(previewColumns).
+		AddRow(5, testPreviewToken, 42, testPreviewUserID, repository.AvailabilitySourceOutlook, intervals, time.Now(), time.Now().Add(time.Hour)))
+	expectEvent(mock, 42, repository.EventStatusOpen)
+	mock.ExpectBegin()
+	// 保存と同じトランザクションで下書きを削除する
+	mock.ExpectExec(`DELETE FROM "AvailabilityPreviews" WHERE "AvailabilityPreviews"."id" = \$1`).
+		WithArgs(int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectParticipant(mock, 42, testPreviewUserID, repository.ParticipantStatusAccepted)
+	expectReplaceAvailabilities(mock, repository.CalendarAvailabilitySources,
+		availabilityInsertArgs(42, testPreviewUserID, repository.AvailabilitySourceOutlook, first, second))
+	mock.ExpectCommit()
+	expectNoWebhooks(mock)
+
+	free, err := ConfirmAvailabilityPreview(context.Background(), ConfirmAvailabilityInput{EventID: 42, UserID: testPreviewUserID, Token: testPreviewToken})
+	if err != nil {
+		t.Fatalf("ConfirmAvailabilityPreview() error = %v", err)
+	}
+	if len(free) != 2 || !free[0].Start.Equal(first[0]) || !free[0].End.Equal(first[1]) || !free[1].Start.Equal(second[0]) || !free[1].End.Equal(second[1]) {
+		t.Errorf("confirmed intervals = %v, want the previewed intervals", free)
+	}
+	waitForExpectations(t, mock)
+}
+
+func TestConfirmAvailabilityPreviewRollsBackWhenAlreadyConfirmed(t *testing.T) {
+	mock := repositorytest.UseMock(t)
+	expectPreviewLookup(mock, testPreviewUserID).WillReturnRows(sqlmock.NewRows(previewColumns).
+		AddRow(5, testPreviewToken, 42, testPreviewUserID, repository.AvailabilitySourceGoogleCalendar, `[]`, time.Now(), time.Now().Add(time.Hour)))
+	expectEvent(mock, 42, repository.EventStatusOpen)
+	mock.ExpectBegin()
+	// 同時に確定した別のリクエストが先に下書きを削除していた
+	mock.ExpectExec(`DELETE FROM "AvailabilityPreviews"`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	_, err := ConfirmAvailabilityPreview(context.Background(), ConfirmAvailabilityInput{EventID: 42, UserID: testPreviewUserID, Token: testPreviewToken})
+	if !errors.Is(err, repository.ErrPreviewNotFound) {
+		t.Errorf("got %v, want ErrPreviewNotFound", err)
+	}
+}
@@ -0,0 +1,293 @@
+package application
+
+import (
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultTimezone はプロフィールのタイムゾーンが未設定のときに使うタイムゾーン
+	defaultTimezone = "Asia/Tokyo"
+	// unnamedMemberName はプロフィールのない回答者の表示名
+	unnamedMemberName = "名前未設定"
+	// maxAvatarURLLength はアイコンの URL の上限
+	maxAvatarURLLength = 2048
+)
+
+// UpdateUserProfileInput はプロフィールの更新内容を表す（nil の項目は変更しない）
+// WorkStart / WorkEnd に空文字を指定すると勤務時間の設定を外す
+type UpdateUserProfileInput struct {
+	UserID      string
+	DisplayName *string
+	AvatarURL   *string
+	Timezone    *string
+	WorkStart   *string
+	WorkEnd     *string
+}
+
+// providerProfile は Google / LINE から取得したプロフィール
+type providerProfile struct {
+	DisplayName string
+	AvatarURL   string
+	Timezone    string
+	GoogleID    string
+}
+
+// GetUserProfile はユーザーのプロフィールを返す。まだ作られていなければ既定値のプロフィールを返す
+func GetUserProfile(ctx context.Context, userID string) (repository.Users, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return repository.Users{}, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	u, _, err := getOrNewUser(ctx, repo, userID, time.Now())
+	if err != nil {
+		return repository.Users{}, err
+	}
+	return *u, nil
+}
+
+// UpdateUserProfile はユーザーが編集したプロフィールを保存する
+// ここで設定した表示名・アイコンは、以後 Google / LINE のプロフィールで上書きしない
+func UpdateUserProfile(ctx context.Context, in UpdateUserProfileInput) (repository.Users, error) {
+	verr := &validationErrors{}
+	if in.DisplayName != nil {
+		name := strings.TrimSpace(*in.DisplayName)
+		in.DisplayName = &name
+		if name == "" {
+			verr.add("displayName", FieldErrorRequired, "表示名を入力してください")
+		} else if len([]rune(name)) > maxDisplayNameLength {
+			verr.add("displayName", FieldErrorTooLong, fmt.Sprintf("表示名は%d文字以内で入力してください", maxDisplayNameLength), maxDisplayNameLength)
+		}
+	}
+	if in.AvatarURL != nil && *in.AvatarURL != "" {
+		if u, err := url.Parse(*in.AvatarURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(*in.AvatarURL) > maxAvatarURLLength {
+			verr.add("avatarUrl", FieldErrorInvalidFormat, "アイコンの URL は http または https で指定してください")
+		}
+	}
+	if in.Timezone != nil {
+		if _, err := time.LoadLocation(*in.Timezone); err != nil || *in.Timezone == "" {
+			verr.add("timezone", FieldErrorInvalidFormat, "タイムゾーンは Asia/Tokyo のような IANA の名前で指定してください")
+		}
+	}
+	if in.WorkStart != nil || in.WorkEnd != nil {
+		if in.WorkStart == nil || in.WorkEnd == nil {
+			verr.add("workEnd", FieldErrorTimeRangeRequired, "勤務時間の開始時刻と終了時刻は両方指定してください")
+		} else {
+			validateWorkingHours(verr, *in.WorkStart, *in.WorkEnd)
+		}
+	}
+	if err := verr.err(); err != nil {
+		return repository.Users{}, err
+	}
+
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return repository.Users{}, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	now := time.Now()
+	u, _, err := getOrNewUser(ctx, repo, in.UserID, now)
+	if err != nil {
+		return repository.Users{}, err
+	}
+	if in.DisplayName != nil {
+		u.DisplayName = *in.DisplayName
+	}
+	if in.AvatarURL != nil {
+		u.AvatarURL = *in.AvatarURL
+	}
+	if in.Timezone != nil {
+		u.Timezone = *in.Timezone
+	}
+	if in.WorkStart != nil && in.WorkEnd != nil {
+		u.WorkStart = sql.NullString{String: *in.WorkStart, Valid: *in.WorkStart != ""}
+		u.WorkEnd = sql.NullString{String: *in.WorkEnd, Valid: *in.WorkEnd != ""}
+	}
+	u.UpdatedAt = now
+
+	if err := repo.SaveUser(ctx, u); err != nil {
+		return repository.Users{}, err
+	}
+	return *u, nil
+}
+
+// validateWorkingHours は勤務時間 (HH:MM、終了は 24:00 も可) を検証する。両方空なら設定なし
+func validateWorkingHours(verr *validationErrors, workStart, workEnd string) {
+	if workStart == "" && workEnd == "" {
+		return
+	}
+	if (workStart == "") != (workEnd == "") {
+		field := "workEnd"
+		if workStart == "" {
+			field = "workStart"
+		}
+		verr.add(field, FieldErrorTimeRangeRequired, "勤務時間の開始時刻と終了時刻は両方指定してください")
+		return
+	}
+
+	ws, wsOK := parseMinuteOfDay(workStart, false)
+	if !wsOK {
+		verr.add("workStart", FieldErrorInvalidFormat, "勤務時間の開始時刻は HH:MM 形式で指定してください")
+	}
+	we, weOK := parseMinuteOfDay(workEnd, true)
+	if !weOK {
+		verr.add("workEnd", FieldErrorInvalidFormat, "勤務時間の終了時刻は HH:MM 形式で指定してください")
+	}
+	if wsOK && weOK && we <= ws {
+		verr.add("workEnd", FieldErrorTimeOrder, "勤務時間の終了時刻は開始時刻より後を指定してください")
+	}
+}
+
+// getOrNewUser はユーザーのプロフィールと、保存済みかどうかを返す
+// まだ作られていなければ保存前の既定値のプロフィールを返す
+func getOrNewUser(ctx context.Context, repo *repository.SupabaseRepositoryImpl, userID string, now time.Time) (*repository.Users, bool, error) {
+	u, err := repo.GetUser(ctx, userID)
+	if err == nil {
+		return u, true, nil
+	}
+	if !errors.Is(err, repository.ErrUserNotFound) {
+		return nil, false, err
+	}
+	return &repository.Users{ID: userID, Timezone: defaultTimezone, CreatedAt: now, UpdatedAt: now}, false, nil
+}
+
+// syncUserProfile は Google / LINE のプロフィールで、ユーザーのプロフィールの空の項目を補う
+// ユーザーが設定した項目は上書きしない
+func syncUserProfile(ctx context.Context, repo *repository.SupabaseRepositoryImpl, userID string, p providerProfile) error {
+	now := time.Now()
+	u, exists, err := getOrNewUser(ctx, repo, userID, now)
+	if err != nil {
+		return err
+	}
+	if !mergeProviderProfile(u, p) && exists {
+		return nil
+	}
+	u.UpdatedAt = now
+	return repo.SaveUser(ctx, u)
+}
+
+// mergeProviderProfile は u の空の項目を p で埋め、変更があったかを返す
+// タイムゾーンは既定値のままの場合に限り、カレンダーの設定で置き換える
+func mergeProviderProfile(u *repository.Users, p providerProfile) bool {
+	changed := false
+	if u.DisplayName == "" && p.DisplayName != "" {
+		name := []rune(strings.TrimSpace(p.DisplayName))
+		if len(name) > maxDisplayNameLength {
+			name = name[:maxDisplayNameLength]
+		}
+		u.DisplayName = string(name)
+		changed = true
+	}
+	if u.AvatarURL == "" && p.AvatarURL != "" && len(p.AvatarURL) <= maxAvatarURLLength {
+		u.AvatarURL = p.AvatarURL
+		changed = true
+	}
+	if (u.Timezone == "" || u.Timezone == defaultTimezone) && p.Timezone != "" && p.Timezone != u.Timezone {
+		if _, err := time.LoadLocation(p.Timezone); err == nil {
+			u.Timezone = p.Timezone
+			changed = true
+		}
+	}
+	if !u.GoogleID.Valid && p.GoogleID != "" {
+		u.GoogleID = sql.NullString{String: p.GoogleID, Valid: true}
+		changed = true
+	}
+	return changed
+}
+
+// syncGoogleProfile はプロフィールがまだないユーザーについて、Google のプロフィールとカレンダーのタイムゾーンからプロフィールを作る
+// userID は必ずセッションのユーザー（トークンのカレンダーの持ち主）を渡す。リクエストの本文の userId を渡すと他人のプロフィールを作れてしまう
+// 取得できなかった項目は空のまま作り、以後は問い合わせない。失敗しても空き時間の提出は続けられるようにログに残すだけにする
+func syncGoogleProfile(ctx context.Context, repo *repository.SupabaseRepositoryImpl, userID string, access CalendarAccess, cal servise.CalendarProvider) {
+	if userID == "" || access.Source() != repository.AvailabilitySourceGoogleCalendar {
+		return
+	}
+	if _, err := repo.GetUser(ctx, userID); !errors.Is(err, repository.ErrUserNotFound) {
+		return
+	}
+
+	var p providerProfile
+	if gp, err := servise.FetchGoogleProfile(ctx, access.Token); err != nil {
+		log.Printf("Google のプロフィールを取得できませんでした: userID=%s, err=%v", userID, err)
+	} else {
+		p.DisplayName, p.AvatarURL, p.GoogleID = gp.Name, gp.Picture, gp.Sub
+	}
+	if cs, ok := cal.(*servise.CalendarService); ok {
+		if tz, err := cs.TimeZone(ctx); err != nil {
+			log.Printf("カレンダーのタイムゾーンを取得できませんでした: userID=%s, err=%v", userID, err)
+		} else {
+			p.Timezone = tz
+		}
+	}
+
+	if err := syncUserProfile(ctx, repo, userID, p); err != nil {
+		log.Printf("プロフィールの保存に失敗しました: userID=%s, err=%v", userID, err)
+	}
+}
+
+// memberNames は回答者の表示名を返す。ログインユーザーはプロフィール、ゲストは参加時に入力した名前を使う
+// 表示名のない回答者は結果に含めない
+func memberNames(ctx context.Context, repo *repository.SupabaseRepositoryImpl, eventID int64, userIDs []string) (map[string]string, error) {
+	names := make(map[string]string, len(userIDs))
+	users, err := repo.ListUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		if u.DisplayName != "" {
+			names[u.ID] = u.DisplayName
+		}
+	}
+	guests, err := repo.ListEventGuests(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range guests {
+		names[g.UserID] = g.DisplayName
+	}
+	return names, nil
+}
+
+// nameSlotMembers は候補日時ごとに、参加可能な回答者の表示名を設定する
+func nameSlotMembers(ctx context.Context, repo *repository.SupabaseRepositoryImpl, eventID int64, slots []PossibleSlot) error {
+	var userIDs []string
+	seen := make(map[string]bool)
+	for _, s := range slots {
+		for _, id := range s.AvailableUserIDs {
+			if !seen[id] && id != anonymousRespondentID {
+				seen[id] = true
+				userIDs = append(userIDs, id)
+			}
+		}
+	}
+	names, err := memberNames(ctx, repo, eventID, userIDs)
+	if err != nil {
+		return err
+	}
+	setSlotMemberNames(slots, names)
+	return nil
+}
+
+// setSlotMemberNames は names から候補日時ごとの参加可能な回答者の表示名を設定する
+func setSlotMemberNames(slots []PossibleSlot, names map[string]string) {
+	for i := range slots {
+		members := make([]string, 0, len(slots[i].AvailableUserIDs))
+		for _, id := range slots[i].AvailableUserIDs {
+			name, ok := names[id]
+			if !ok {
+				name = unnamedMemberName
+			}
+			members = append(members, name)
+		}
+		slots[i].AvailableMembers = members
+	}
+}
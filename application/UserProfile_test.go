@@ -0,0 +1,54 @@
+package application
+
+import (
+	"adjuSche-back-end/repository"
+	"database/sql"
+	"slices"
+	"testing"
+)
+
+func TestMergeProviderProfileFillsOnlyEmptyFields(t *testing.T) {
+	u := &repository.Users{ID: "a", Timezone: defaultTimezone}
+	changed := mergeProviderProfile(u, providerProfile{
+		DisplayName: " 山田 太郎 ",
+		AvatarURL:   "https://example.com/a.png",
+		Timezone:    "America/New_York",
+		GoogleID:    "123",
+	})
+	if !changed {
+		t.Fatal("expected the profile to change")
+	}
+	want := repository.Users{
+		ID:          "a",
+		DisplayName: "山田 太郎",
+		AvatarURL:   "https://example.com/a.png",
+		Timezone:    "America/New_York",
+		GoogleID:    sql.NullString{String: "123", Valid: true},
+	}
+	if *u != want {
+		t.Errorf("profile = %+v, want %+v", *u, want)
+	}
+
+	// ユーザーが設定した項目は上書きしない
+	if mergeProviderProfile(u, providerProfile{DisplayName: "Taro", AvatarURL: "https://example.com/b.png", Timezone: "Europe/London", GoogleID: "456"}) {
+		t.Errorf("profile was overwritten: %+v", *u)
+	}
+	if mergeProviderProfile(&repository.Users{Timezone: defaultTimezone}, providerProfile{Timezone: "Not/AZone"}) {
+		t.Error("an unknown time zone was accepted")
+	}
+}
+
+func TestSetSlotMemberNames(t *testing.T) {
+	slots := []PossibleSlot{
+		{AvailableUserIDs: []string{"a", "guest"}},
+		{AvailableUserIDs: []string{"a", "b", anonymousRespondentID}},
+	}
+	setSlotMemberNames(slots, map[string]string{"a": "山田", "guest": "佐藤（ゲスト）"})
+
+	if !slices.Equal(slots[0].AvailableMembers, []string{"山田", "佐藤（ゲスト）"}) {
+		t.Errorf("slot 0 members = %v", slots[0].AvailableMembers)
+	}
+	if !slices.Equal(slots[1].AvailableMembers, []string{"山田", unnamedMemberName, unnamedMemberName}) {
+		t.Errorf("slot 1 members = %v", slots[1].AvailableMembers)
+	}
+}
@@ -0,0 +1,153 @@
+package application
+
+import (
+	"adjuSche-back-end/domain"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxEventPeriodDays は候補期間として指定できる最大日数
+const maxEventPeriodDays = 90
+
+// フィールドエラーのコード
+const (
+	FieldErrorRequired          = "required"
+	FieldErrorInvalidFormat     = "invalid_format"
+	FieldErrorMustBePositive    = "must_be_positive"
+	FieldErrorPeriodOrder       = "period_order"
+	FieldErrorPeriodTooLong     = "period_too_long"
+	FieldErrorTimeOrder         = "time_order"
+	FieldErrorDurationTooLong   = "duration_exceeds_time_window"
+	FieldErrorTimeRangeRequired = "time_range_incomplete"
+	FieldErrorOutsidePeriod     = "outside_period"
+	FieldErrorTooLong           = "too_long"
+	FieldErrorTooMany           = "too_many"
+	FieldErrorInvalidType       = "invalid_type"
+)
+
+var hhmmPattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// validationErrors は入力項目ごとの検証エラーを集める
+type validationErrors struct {
+	fields []domain.FieldError
+}
+
+func (v *validationErrors) add(field, code, message string, params ...any) {
+	v.fields = append(v.fields, domain.FieldError{Field: field, Code: code, Message: message, Params: params})
+}
+
+// err は検証エラーがあれば domain.KindValidation のエラーを返す
+func (v *validationErrors) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return domain.Validation(v.fields)
+}
+
+// ValidateCreateEventInput はイベント作成の入力をすべて検証し、問題があれば domain.KindValidation のエラーを返す
+func ValidateCreateEventInput(in CreateEventInput) error {
+	verr := &validationErrors{}
+
+	if strings.TrimSpace(in.HostUserID) == "" {
+		verr.add("hostUserID", FieldErrorRequired, "主催者のユーザーIDを指定してください")
+	}
+	if strings.TrimSpace(in.Title) == "" {
+		verr.add("title", FieldErrorRequired, "タイトルを入力してください")
+	}
+	if in.ParticipantCount <= 0 {
+		verr.add("participantCount", FieldErrorMustBePositive, "参加人数は1以上で指定してください")
+	}
+	validateConditionFields(verr, "conditions.", in.PeriodStart, in.PeriodEnd, in.TimeStart, in.TimeEnd, in.DurationMin)
+
+	return verr.err()
+}
+
+// validateEventCondition はイベント条件の入力を検証する（prefix はエラーのフィールド名の接頭辞）
+func validateEventCondition(prefix, periodStart, periodEnd, timeStart, timeEnd string, durationMin int) error {
+	verr := &validationErrors{}
+	validateConditionFields(verr, prefix, periodStart, periodEnd, timeStart, timeEnd, durationMin)
+	return verr.err()
+}
+
+func validateConditionFields(verr *validationErrors, prefix, periodStart, periodEnd, timeStart, timeEnd string, durationMin int) {
+	// 期間: 両方正しく解釈できた場合のみ順序と長さを検証する
+	var ps, pe time.Time
+	var psOK, peOK bool
+	if periodStart == "" {
+		verr.add(prefix+"periodStart", FieldErrorRequired, "期間の開始日を指定してください")
+	} else if t, err := parseRFC3339OrDate(periodStart); err != nil {
+		verr.add(prefix+"periodStart", FieldErrorInvalidFormat, "期間の開始日は YYYY-MM-DD または RFC3339 形式で指定してください")
+	} else {
+		ps, psOK = t, true
+	}
+	if periodEnd == "" {
+		verr.add(prefix+"periodEnd", FieldErrorRequired, "期間の終了日を指定してください")
+	} else if t, err := parseRFC3339OrDate(periodEnd); err != nil {
+		verr.add(prefix+"periodEnd", FieldErrorInvalidFormat, "期間の終了日は YYYY-MM-DD または RFC3339 形式で指定してください")
+	} else {
+		pe, peOK = t, true
+	}
+	if psOK && peOK {
+		if pe.Before(ps) {
+			verr.add(prefix+"periodEnd", FieldErrorPeriodOrder, "期間の終了日は開始日以降を指定してください")
+		} else if pe.Sub(ps) > maxEventPeriodDays*24*time.Hour {
+			verr.add(prefix+"periodEnd", FieldErrorPeriodTooLong, fmt.Sprintf("期間は%d日以内で指定してください", maxEventPeriodDays), maxEventPeriodDays)
+		}
+	}
+
+	// 時間帯: HH:MM 形式。片方だけの指定は許可しない
+	var ts, te time.Time
+	var tsOK, teOK bool
+	if timeStart != "" {
+		if t, ok := parseHHMM(timeStart); ok {
+			ts, tsOK = t, true
+		} else {
+			verr.add(prefix+"timeStart", FieldErrorInvalidFormat, "開始時刻は HH:MM 形式で指定してください")
+		}
+	}
+	if timeEnd != "" {
+		if t, ok := parseHHMM(timeEnd); ok {
+			te, teOK = t, true
+		} else {
+			verr.add(prefix+"timeEnd", FieldErrorInvalidFormat, "終了時刻は HH:MM 形式で指定してください")
+		}
+	}
+	if (timeStart == "") != (timeEnd == "") {
+		field := prefix + "timeEnd"
+		if timeStart == "" {
+			field = prefix + "timeStart"
+		}
+		verr.add(field, FieldErrorTimeRangeRequired, "開始時刻と終了時刻は両方指定してください")
+	}
+
+	// 所要時間: 正の値で、時間帯（未指定なら 1 日）に収まること
+	if durationMin <= 0 {
+		verr.add(prefix+"durationMin", FieldErrorMustBePositive, "所要時間は1分以上で指定してください")
+		return
+	}
+	window := 24 * time.Hour
+	if tsOK && teOK {
+		if !te.After(ts) {
+			verr.add(prefix+"timeEnd", FieldErrorTimeOrder, "終了時刻は開始時刻より後を指定してください")
+			return
+		}
+		window = te.Sub(ts)
+	}
+	if time.Duration(durationMin)*time.Minute > window {
+		verr.add(prefix+"durationMin", FieldErrorDurationTooLong, "所要時間が指定した時間帯に収まりません")
+	}
+}
+
+// parseHHMM は HH:MM 形式の時刻を解釈する
+func parseHHMM(value string) (time.Time, bool) {
+	if !hhmmPattern.MatchString(value) {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,120 @@
+package application
+
+import (
+	"adjuSche-back-end/domain"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func validCreateEventInput() CreateEventInput {
+	return CreateEventInput{
+		HostUserID:       "host",
+		Title:            "定例会",
+		ParticipantCount: 3,
+		PeriodStart:      "2026-11-01",
+		PeriodEnd:        "2026-11-07",
+		TimeStart:        "09:00",
+		TimeEnd:          "18:00",
+		DurationMin:      60,
+	}
+}
+
+func TestValidateCreateEventInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(in *CreateEventInput)
+		want   []string // "<項目>:<コード>"
+	}{
+		{"valid", func(in *CreateEventInput) {}, nil},
+		{"all day", func(in *CreateEventInput) { in.TimeStart, in.TimeEnd, in.DurationMin = "", "", 24*60 }, nil},
+		{"rfc3339 period", func(in *CreateEventInput) {
+			in.PeriodStart, in.PeriodEnd = "2026-11-01T00:00:00+09:00", "2026-11-07T23:59:00+09:00"
+		}, nil},
+		{"missing host and title", func(in *CreateEventInput) { in.HostUserID, in.Title = " ", "" }, []string{
+			"hostUserID:" + FieldErrorRequired, "title:" + FieldErrorRequired,
+		}},
+		{"participant count", func(in *CreateEventInput) { in.ParticipantCount = 0 }, []string{
+			"participantCount:" + FieldErrorMustBePositive,
+		}},
+		{"missing period", func(in *CreateEventInput) { in.PeriodStart, in.PeriodEnd = "", "" }, []string{
+			"conditions.periodStart:" + FieldErrorRequired, "conditions.periodEnd:" + FieldErrorRequired,
+		}},
+		{"invalid period format", func(in *CreateEventInput) { in.PeriodStart, in.PeriodEnd = "2026/11/01", "tomorrow" }, []string{
+			"conditions.periodStart:" + FieldErrorInvalidFormat, "conditions.periodEnd:" + FieldErrorInvalidFormat,
+		}},
+		{"period order", func(in *CreateEventInput) { in.PeriodEnd = "2026-10-31" }, []string{
+			"conditions.periodEnd:" + FieldErrorPeriodOrder,
+		}},
+		{"period too long", func(in *CreateEventInput) { in.PeriodEnd = "2027-03-01" }, []string{
+			"conditions.periodEnd:" + FieldErrorPeriodTooLong,
+		}},
+		{"invalid time format", func(in *CreateEventInput) { in.TimeStart, in.TimeEnd = "9:00", "24:00" }, []string{
+			"conditions.timeStart:" + FieldErrorInvalidFormat, "conditions.timeEnd:" + FieldErrorInvalidFormat,
+		}},
+		{"only time start", func(in *CreateEventInput) { in.TimeEnd = "" }, []string{
+			"conditions.timeEnd:" + FieldErrorTimeRangeRequired,
+		}},
+		{"only time end", func(in *CreateEventInput) { in.TimeStart = "" }, []string{
+			"conditions.timeStart:" + FieldErrorTimeRangeRequired,
+		}},
+		{"time order", func(in *CreateEventInput) { in.TimeStart, in.TimeEnd = "18:00", "09:00" }, []string{
+			"conditions.timeEnd:" + FieldErrorTimeOrder,
+		}},
+		{"duration not positive", func(in *CreateEventInput) { in.DurationMin = 0 }, []string{
+			"conditions.durationMin:" + FieldErrorMustBePositive,
+		}},
+		{"duration exceeds window", func(in *CreateEventInput) { in.DurationMin = 10 * 60 }, []string{
+			"conditions.durationMin:" + FieldErrorDurationTooLong,
+		}},
+		{"duration exceeds a day", func(in *CreateEventInput) { in.TimeStart, in.TimeEnd, in.DurationMin = "", "", 24*60+1 }, []string{
+			"conditions.durationMin:" + FieldErrorDurationTooLong,
+		}},
+	}
+	for _, tt := range tests {
+		in := validCreateEventInput()
+		tt.modify(&in)
+		err := ValidateCreateEventInput(in)
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.name, err)
+			}
+			continue
+		}
+		var derr *domain.Error
+		if !errors.As(err, &derr) || derr.Kind != domain.KindValidation {
+			t.Errorf("%s: got %v, want a validation error", tt.name, err)
+			continue
+		}
+		var got []string
+		for _, f := range derr.Fields {
+			got = append(got, f.Field+":"+f.Code)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got fields %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateCreateEventInputPeriodTooLongParams(t *testing.T) {
+	in := validCreateEventInput()
+	in.PeriodEnd = "2027-03-01"
+	var derr *domain.Error
+	if !errors.As(ValidateCreateEventInput(in), &derr) || len(derr.Fields) != 1 {
+		t.Fatalf("want one field error, got %v", derr)
+	}
+	if params := derr.Fields[0].Params; len(params) != 1 || params[0] != maxEventPeriodDays {
+		t.Errorf("params = %v, want [%d]", params, maxEventPeriodDays)
+	}
+}
+
+func TestValidateEventConditionPrefix(t *testing.T) {
+	err := validateEventCondition("", "2026-11-01", "2026-11-07", "10:00", "", 30)
+	var derr *domain.Error
+	if !errors.As(err, &derr) || len(derr.Fields) != 1 || derr.Fields[0].Field != "timeEnd" {
+		t.Fatalf("got %v, want a timeEnd error without prefix", err)
+	}
+	if err := validateEventCondition("", "2026-11-01", "2026-11-07", "10:00", "11:00", 60); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,441 @@
+package application
+
+import (
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Webhook で通知するイベントの種類
+const (
+	WebhookEventCreated          = "event.created"
+	WebhookAvailabilitySubmitted = "availability.submitted"
+	WebhookEventFinalized        = "event.finalized"
+	WebhookParticipantDeclined   = "participant.declined"
+	WebhookParticipantWithdrawn  = "participant.withdrawn"
+)
+
+const (
+	// FieldErrorUnknownWebhookEvent は購読できないイベントの種類が指定されたことを表す
+	FieldErrorUnknownWebhookEvent = "unknown_event_type"
+	// FieldErrorNotPublicAddress は送信先の URL が内部ネットワークのホストであることを表す
+	FieldErrorNotPublicAddress = "not_public_address"
+)
+
+// WebhookEventTypes は購読できるイベントの種類
+var WebhookEventTypes = []string{WebhookEventCreated, WebhookAvailabilitySubmitted, WebhookEventFinalized, WebhookParticipantDeclined, WebhookParticipantWithdrawn}
+
+// webhookDeliveryListLimit は送信履歴の一覧で返す件数
+const webhookDeliveryListLimit = 50
+
+// webhookDeliveryTimeout は 1 件の通知（再試行を含む）にかける時間の上限
+const webhookDeliveryTimeout = 5 * time.Minute
+
+// webhookDeliveryLease は送信を始めてから結果を保存するまでの期限。過ぎても送信中なら中断されたとみなす
+// webhookDeliveryTimeout に結果の保存にかかる時間の余裕を足したもの
+const webhookDeliveryLease = webhookDeliveryTimeout + time.Minute
+
+// webhookResumeWindow は中断された送信を再開する期限。これより古い通知は今さら届けても役に立たないため失敗にする
+const webhookResumeWindow = 24 * time.Hour
+
+// webhookResumeBatch は中断された送信を一度に読み込む件数
+const webhookResumeBatch = 100
+
+// webhookSender は Webhook の送信に使う。再試行の間隔は servise.NewWebhookSender の既定値
+var webhookSender = servise.NewWebhookSender()
+
+// webhookPayload は Webhook で送る JSON の共通部分を表す
+type webhookPayload struct {
+	Type       string `json:"type"`
+	OccurredAt string `json:"occurredAt"`
+	Data       any    `json:"data"`
+}
+
+type webhookEventData struct {
+	EventID     string `json:"eventId"`
+	Title       string `json:"title"`
+	HostUserID  string `json:"hostUserId"`
+	PeriodStart string `json:"periodStart"`
+	PeriodEnd   string `json:"periodEnd"`
+	DurationMin int    `json:"durationMin"`
+}
+
+type webhookAvailabilityData struct {
+	EventID   string `json:"eventId"`
+	UserID    string `json:"userId"`
+	SlotCount int    `json:"slotCount"`
+}
+
+type webhookFinalizedData struct {
+	EventID      string `json:"eventId"`
+	Title        string `json:"title"`
+	DecidedStart string `json:"decidedStart"`
+	DecidedEnd   string `json:"decidedEnd"`
+}
+
+type webhookDeclinedData struct {
+	EventID string `json:"eventId"`
+	UserID  string `json:"userId"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// CreateWebhookInput は Webhook の登録内容を表す
+type CreateWebhookInput struct {
+	UserID     string
+	URL        string
+	EventTypes []string // 空ならすべての種類を通知する
+}
+
+// CreateWebhookSubscription は主催者の Webhook の送信先を登録する。署名の鍵は登録時の戻り値でのみ返す
+// 送信先は公開されているホストに限る。名前解決の結果は後から変わりうるため、送信時にも接続先を確かめる
+func CreateWebhookSubscription(ctx context.Context, in CreateWebhookInput) (*repository.WebhookSubscription, error) {
+	verr := &validationErrors{}
+	if in.URL == "" {
+		verr.add("url", FieldErrorRequired, "URL を指定してください")
+	} else if u, err := url.Parse(in.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		verr.add("url", FieldErrorInvalidFormat, "URL は http または https で指定してください")
+	} else if err := servise.ValidatePublicURL(ctx, in.URL); err != nil {
+		verr.add("url", FieldErrorNotPublicAddress, "URL には公開されているホストを指定してください")
+	}
+	for _, t := range in.EventTypes {
+		if !containsString(WebhookEventTypes, t) {
+			verr.add("events", FieldErrorUnknownWebhookEvent, fmt.Sprintf("通知するイベントの種類が正しくありません: %s", t), t)
+		}
+	}
+	if err := verr.err(); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+	sub := &repository.WebhookSubscription{
+		HostUserID: in.UserID,
+		URL:        in.URL,
+		Secret:     secret,
+		EventTypes: strings.Join(in.EventTypes, ","),
+		CreatedAt:  time.Now(),
+	}
+	if err := repo.CreateWebhookSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// ListWebhookSubscriptions は主催者の Webhook の送信先を返す
+func ListWebhookSubscriptions(ctx context.Context, userID string) ([]repository.WebhookSubscription, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+	return repo.ListWebhookSubscriptionsByHostUserID(ctx, userID)
+}
+
+// DeleteWebhookSubscription は Webhook の送信先を削除する（登録した主催者のみ）
+func DeleteWebhookSubscription(ctx context.Context, userID string, subscriptionID int64) error {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return fmt.Errorf("failed to init repository: %w", err)
+	}
+	if _, err := getWebhookAsHost(ctx, repo, subscriptionID, userID); err != nil {
+		return err
+	}
+	return repo.DeleteWebhookSubscription(ctx, subscriptionID)
+}
+
+// ListWebhookDeliveries は Webhook の送信履歴を新しい順に返す（登録した主催者のみ）
+func ListWebhookDeliveries(ctx context.Context, userID string, subscriptionID int64) ([]repository.WebhookDelivery, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+	if _, err := getWebhookAsHost(ctx, repo, subscriptionID, userID); err != nil {
+		return nil, err
+	}
+	return repo.ListWebhookDeliveriesBySubscriptionID(ctx, subscriptionID, webhookDeliveryListLimit)
+}
+
+// ReplayWebhookDelivery は送信履歴と同じ内容を新しい送信として送り直す（登録した主催者のみ）
+// 送信は非同期で行い、作成した送信履歴（送信中）を返す
+func ReplayWebhookDelivery(ctx context.Context, userID string, deliveryID int64) (*repository.WebhookDelivery, error) {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+
+	orig, err := repo.GetWebhookDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := getWebhookAsHost(ctx, repo, orig.SubscriptionID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrWebhookNotFound) {
+			return nil, repository.ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	d := newWebhookDelivery(sub.ID, orig.EventType, orig.Payload, time.Now())
+	if err := repo.CreateWebhookDelivery(ctx, d); err != nil {
+		return nil, err
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookDeliveryTimeout)
+		defer cancel()
+		deliverWebhook(ctx, repo, sub, d)
+	}()
+	return d, nil
+}
+
+// notifyEventCreated はイベントの作成を主催者の Webhook に通知する
+func notifyEventCreated(ev *repository.Events, cond *repository.EventCondition) {
+	notifyWebhooks(ev.HostUserID, WebhookEventCreated, webhookEventData{
+		EventID:     fmt.Sprint(ev.ID),
+		Title:       ev.Title,
+		HostUserID:  ev.HostUserID,
+		PeriodStart: cond.PeriodStart.Format(time.RFC3339),
+		PeriodEnd:   cond.PeriodEnd.Format(time.RFC3339),
+		DurationMin: cond.DurationMin,
+	})
+}
+
+// notifyAvailabilitySubmitted は参加者の空き時間の登録を主催者の Webhook に通知する
+func notifyAvailabilitySubmitted(ev *repository.Events, userID string, slotCount int) {
+	notifyWebhooks(ev.HostUserID, WebhookAvailabilitySubmitted, webhookAvailabilityData{
+		EventID:   fmt.Sprint(ev.ID),
+		UserID:    userID,
+		SlotCount: slotCount,
+	})
+}
+
+// notifyEventFinalized は日程の確定を主催者の Webhook に通知する
+func notifyEventFinalized(ev *repository.Events) {
+	notifyWebhooks(ev.HostUserID, WebhookEventFinalized, webhookFinalizedData{
+		EventID:      fmt.Sprint(ev.ID),
+		Title:        ev.Title,
+		DecidedStart: ev.DecidedStart.Time.Format(time.RFC3339),
+		DecidedEnd:   ev.DecidedEnd.Time.Format(time.RFC3339),
+	})
+}
+
+// notifyParticipantDeclined は参加者の辞退（withdraw なら受諾後の参加取り消し）を主催者の Webhook に通知する
+func notifyParticipantDeclined(ev *repository.Events, userID, reason string, withdraw bool) {
+	eventType := WebhookParticipantDeclined
+	if withdraw {
+		eventType = WebhookParticipantWithdrawn
+	}
+	notifyWebhooks(ev.HostUserID, eventType, webhookDeclinedData{
+		EventID: fmt.Sprint(ev.ID),
+		UserID:  userID,
+		Reason:  reason,
+	})
+}
+
+// notifyWebhooks は主催者の Webhook のうち eventType を購読しているものへ非同期で通知する
+// 通知の失敗は送信履歴とログに残すだけで、呼び出し元の処理には影響させない
+func notifyWebhooks(hostUserID, eventType string, data any) {
+	payload, err := json.Marshal(webhookPayload{
+		Type:       eventType,
+		OccurredAt: time.Now().Format(time.RFC3339),
+		Data:       data,
+	})
+	if err != nil {
+		log.Printf("Webhook の本文の作成に失敗しました: %v", err)
+		return
+	}
+
+	go func() {
+		ctx := context.Background()
+		repo, err := repository.NewSupabaseRepository()
+		if err != nil {
+			log.Printf("Webhook の通知に失敗しました: %v", err)
+			return
+		}
+		subs, err := repo.ListWebhookSubscriptionsByHostUserID(ctx, hostUserID)
+		if err != nil {
+			log.Printf("Webhook の通知に失敗しました: %v", err)
+			return
+		}
+
+		// 再試行中の送信先が他の送信先への通知を遅らせないよう、送信先ごとに並行して送る
+		for i := range subs {
+			sub := &subs[i]
+			if !subscribesTo(sub, eventType) {
+				continue
+			}
+			d := newWebhookDelivery(sub.ID, eventType, string(payload), time.Now())
+			if err := repo.CreateWebhookDelivery(ctx, d); err != nil {
+				log.Printf("Webhook の送信履歴の作成に失敗しました: subscriptionID=%d: %v", sub.ID, err)
+				continue
+			}
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), webhookDeliveryTimeout)
+				defer cancel()
+				deliverWebhook(ctx, repo, sub, d)
+			}()
+		}
+	}()
+}
+
+// newWebhookDelivery は送信中の送信履歴を作る。webhookDeliveryLease を過ぎても結果が無ければ起動時に送り直される
+func newWebhookDelivery(subscriptionID int64, eventType, payload string, now time.Time) *repository.WebhookDelivery {
+	return &repository.WebhookDelivery{
+		SubscriptionID: subscriptionID,
+		EventType:      eventType,
+		Payload:        payload,
+		Status:         repository.WebhookDeliveryStatusPending,
+		CreatedAt:      now,
+		NextAttemptAt:  sql.NullTime{Time: now.Add(webhookDeliveryLease), Valid: true},
+	}
+}
+
+// ResumePendingWebhookDeliveries はサーバーの再起動などで送信中のまま中断された Webhook を送り直す。サーバーの起動時に呼び出す
+// 結果の保存の期限を過ぎた送信履歴を引き継いで送信し、作成から webhookResumeWindow を過ぎたものは送らずに失敗にする
+func ResumePendingWebhookDeliveries(ctx context.Context) error {
+	repo, err := repository.NewSupabaseRepository()
+	if err != nil {
+		return fmt.Errorf("failed to init repository: %w", err)
+	}
+	for {
+		now := time.Now()
+		ds, err := repo.ListStalledWebhookDeliveries(ctx, now, webhookResumeBatch)
+		if err != nil {
+			return err
+		}
+		for i := range ds {
+			if err := resumeWebhookDelivery(ctx, repo, &ds[i], now); err != nil {
+				return err
+			}
+		}
+		if len(ds) < webhookResumeBatch {
+			return nil
+		}
+	}
+}
+
+// resumeWebhookDelivery は中断された送信を 1 件引き継ぎ、送り直すか失敗にする
+// 他のサーバーが先に引き継いだ場合は何もしない
+func resumeWebhookDelivery(ctx context.Context, repo *repository.SupabaseRepositoryImpl, d *repository.WebhookDelivery, now time.Time) error {
+	claimed, err := repo.ClaimWebhookDelivery(ctx, d.ID, now, now.Add(webhookDeliveryLease))
+	if err != nil || !claimed {
+		return err
+	}
+
+	if now.Sub(d.CreatedAt) > webhookResumeWindow {
+		d.Status = repository.WebhookDeliveryStatusFailed
+		d.LastError = sql.NullString{String: "送信が中断され、再開の期限を過ぎたため送信を取りやめました", Valid: true}
+		log.Printf("中断された Webhook の送信を取りやめました: deliveryID=%d", d.ID)
+		return repo.UpdateWebhookDelivery(ctx, d)
+	}
+
+	sub, err := repo.GetWebhookSubscriptionByID(ctx, d.SubscriptionID)
+	if err != nil {
+		return err
+	}
+	log.Printf("中断された Webhook の送信を再開します: deliveryID=%d", d.ID)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookDeliveryTimeout)
+		defer cancel()
+		deliverWebhook(ctx, repo, sub, d)
+	}()
+	return nil
+}
+
+// deliverWebhook は 1 件の Webhook を送信し（失敗時は再試行し）、結果を送信履歴に保存する
+func deliverWebhook(ctx context.Context, repo *repository.SupabaseRepositoryImpl, sub *repository.WebhookSubscription, d *repository.WebhookDelivery) {
+	res := webhookSender.Send(ctx, servise.WebhookMessage{
+		URL:        sub.URL,
+		Secret:     sub.Secret,
+		EventType:  d.EventType,
+		DeliveryID: d.ID,
+		Payload:    []byte(d.Payload),
+	})
+
+	d.Attempts = res.Attempts
+	d.ResponseStatus = res.StatusCode
+	if res.Err != nil {
+		d.Status = repository.WebhookDeliveryStatusFailed
+		d.LastError = sql.NullString{String: webhookFailureSummary(res), Valid: true}
+		log.Printf("Webhook の送信に失敗しました: deliveryID=%d, attempts=%d: %v", d.ID, res.Attempts, res.Err)
+	} else {
+		d.Status = repository.WebhookDeliveryStatusSucceeded
+		d.LastError = sql.NullString{}
+		d.DeliveredAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+	// 送信に時間がかかって ctx の期限が切れていても結果は残す
+	if err := repo.UpdateWebhookDelivery(context.Background(), d); err != nil {
+		log.Printf("Webhook の送信履歴の更新に失敗しました: deliveryID=%d: %v", d.ID, err)
+	}
+}
+
+// webhookFailureSummary は送信履歴に残す失敗の概要を返す
+// 接続エラーの詳細は送信先のネットワークの様子を明かしてしまうため、ログにだけ残して履歴には含めない
+func webhookFailureSummary(res servise.WebhookResult) string {
+	if res.StatusCode != 0 {
+		return fmt.Sprintf("送信先が HTTP %d を返しました", res.StatusCode)
+	}
+	return "送信先に接続できませんでした"
+}
+
+// WebhookDeliveryStatusName は送信履歴のステータスを API で使う名前に変換する
+func WebhookDeliveryStatusName(status int8) string {
+	switch status {
+	case repository.WebhookDeliveryStatusSucceeded:
+		return "succeeded"
+	case repository.WebhookDeliveryStatusFailed:
+		return "failed"
+	}
+	return "pending"
+}
+
+// getWebhookAsHost は Webhook の送信先を取得し、userID が登録した主催者であることを確認する
+// 他の主催者の送信先は存在を知られないよう見つからない扱いにする
+func getWebhookAsHost(ctx context.Context, repo *repository.SupabaseRepositoryImpl, subscriptionID int64, userID string) (*repository.WebhookSubscription, error) {
+	sub, err := repo.GetWebhookSubscriptionByID(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if sub.HostUserID != userID {
+		return nil, repository.ErrWebhookNotFound
+	}
+	return sub, nil
+}
+
+// subscribesTo は送信先が eventType を購読しているかを返す
+func subscribesTo(sub *repository.WebhookSubscription, eventType string) bool {
+	if sub.EventTypes == "" {
+		return true
+	}
+	return containsString(strings.Split(sub.EventTypes, ","), eventType)
+}
+
+// WebhookSubscriptionEventTypes は送信先が購読しているイベントの種類を返す
+func WebhookSubscriptionEventTypes(sub repository.WebhookSubscription) []string {
+	if sub.EventTypes == "" {
+		return WebhookEventTypes
+	}
+	return strings.Split(sub.EventTypes, ",")
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package application
+
+import (
+	"adjuSche-back-end/repository/repositorytest"
+	"adjuSche-back-end/servise"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var webhookDeliveryColumns = []string{"id", "subscription_id", "event_type", "payload", "status", "attempts", "response_status", "last_error", "created_at", "delivered_at", "next_attempt_at"}
+
+// stubWebhookSender は webhookSender を url への送信が 1 回で終わるものに差し替える
+func stubWebhookSender(t *testing.T, client *http.Client) {
+	t.Helper()
+	prev := webhookSender
+	webhookSender = &servise.WebhookSender{Client: client, MaxAttempts: 1, Now: time.Now}
+	t.Cleanup(func() { webhookSender = prev })
+}
+
+// waitForExpectations は非同期の送信が期待したクエリをすべて実行するまで待つ
+func waitForExpectations(t *testing.T, mock sqlmock.Sqlmock) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		err := mock.ExpectationsWereMet()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("webhook delivery did not finish: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func expectStalledDeliveries(mock sqlmock.Sqlmock, createdAt time.Time) {
+	mock.ExpectQuery(`SELECT \* FROM "WebhookDeliveries" WHERE status = .+ next_attempt_at <= .+ ORDER BY id`).
+		WillReturnRows(sqlmock.NewRows(webhookDeliveryColumns).
+			AddRow(7, 3, WebhookEventCreated, `{"type":"event.created"}`, 0, 0, 0, nil, createdAt, nil, createdAt.Add(webhookDeliveryLease)))
+}
+
+func expectClaim(mock sqlmock.Sqlmock, claimed bool) {
+	var rows int64
+	if claimed {
+		rows = 1
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "WebhookDeliveries" SET "next_attempt_at"=.+ WHERE id = .+ AND status = .+`).
+		WillReturnResult(sqlmock.NewResult(0, rows))
+	mock.ExpectCommit()
+}
+
+func TestResumePendingWebhookDeliveriesResendsStalledDelivery(t *testing.T) {
+	received := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get(servise.WebhookDeliveryHeader)
+	}))
+	defer srv.Close()
+	stubWebhookSender(t, srv.Client())
+
+	mock := repositorytest.UseMock(t)
+	expectStalledDeliveries(mock, time.Now().Add(-time.Hour))
+	expectClaim(mock, true)
+	mock.ExpectQuery(`SELECT \* FROM "WebhookSubscriptions" WHERE "WebhookSubscriptions"."id" = `).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "host_user_id", "url", "secret", "event_types", "created_at"}).
+			AddRow(3, "host", srv.URL, "secret", "", time.Now()))
+	mock.ExpectBegin()
+	// attempts, delivered_at, last_error, response_status, status, id の順
+	mock.ExpectExec(`UPDATE "WebhookDeliveries" SET .*"status"=`).
+		WithArgs(1, sqlmock.AnyArg(), sqlmock.AnyArg(), 200, 1, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := ResumePendingWebhookDeliveries(context.Background()); err != nil {
+		t.Fatalf("ResumePendingWebhookDeliveries() error = %v", err)
+	}
+	select {
+	case id := <-received:
+		if id != "7" {
+			t.Errorf("resent delivery id = %q, want 7", id)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stalled delivery was not resent")
+	}
+	waitForExpectations(t, mock)
+}
+
+func TestResumePendingWebhookDeliveriesFailsExpiredDelivery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("expired delivery should not be sent")
+	}))
+	defer srv.Close()
+	stubWebhookSender(t, srv.Client())
+
+	mock := repositorytest.UseMock(t)
+	expectStalledDeliveries(mock, time.Now().Add(-webhookResumeWindow-time.Hour))
+	expectClaim(mock, true)
+	mock.ExpectBegin()
+	// attempts, delivered_at, last_error, response_status, status, id の順
+	mock.ExpectExec(`UPDATE "WebhookDeliveries" SET .*"status"=`).
+		WithArgs(0, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, 2, 7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := ResumePendingWebhookDeliveries(context.Background()); err != nil {
+		t.Fatalf("ResumePendingWebhookDeliveries() error = %v", err)
+	}
+}
+
+func TestResumePendingWebhookDeliveriesSkipsDeliveryClaimedElsewhere(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("a delivery claimed by another server should not be sent")
+	}))
+	defer srv.Close()
+	stubWebhookSender(t, srv.Client())
+
+	mock := repositorytest.UseMock(t)
+	expectStalledDeliveries(mock, time.Now().Add(-time.Hour))
+	expectClaim(mock, false)
+
+	if err := ResumePendingWebhookDeliveries(context.Background()); err != nil {
+		t.Fatalf("ResumePendingWebhookDeliveries() error = %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package application
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// テストで使う sqlmock の列とクエリの組み立て
+
+var (
+	eventColumns       = []string{"id", "host_user_id", "title", "note", "participant_count", "status", "decided_start", "decided_end", "created_at", "updated_at"}
+	participantColumns = []string{"id", "event_id", "user_id", "status", "joined_at", "decline_reason", "declined_at"}
+)
+
+const testHostID = "host-user"
+
+// expectEvent は GetEventByID で status のイベントを返す
+func expectEvent(mock sqlmock.Sqlmock, eventID int64, status int) {
+	mock.ExpectQuery(`SELECT \* FROM "Events" WHERE "Events"."id" = \$1`).
+		WithArgs(eventID, 1).
+		WillReturnRows(sqlmock.NewRows(eventColumns).
+			AddRow(eventID, testHostID, "定例会", nil, 3, status, nil, nil, time.Now(), time.Now()))
+}
+
+// expectParticipant は GetOrCreateEventParticipant・GetEventParticipant で status の参加者を返す
+func expectParticipant(mock sqlmock.Sqlmock, eventID int64, userID string, status int8) {
+	mock.ExpectQuery(`SELECT \* FROM "EventParticipants" WHERE event_id = \$1 AND user_id = \$2`).
+		WithArgs(eventID, userID, 1).
+		WillReturnRows(sqlmock.NewRows(participantColumns).
+			AddRow(9, eventID, userID, status, time.Now().Format(time.RFC3339), nil, nil))
+}
+
+// expectNoWebhooks は保存後の Webhook の通知で、主催者の送信先が無いことを返す
+// 通知は非同期のため、呼び出し側は waitForExpectations で待つ
+func expectNoWebhooks(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`SELECT \* FROM "WebhookSubscriptions" WHERE host_user_id = \$1`).
+		WithArgs(testHostID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+}
+
+// availabilityInsertArgs は ReplaceUserAvailabilitiesBySources が挿入する空き時間の引数を、列の順に並べる
+func availabilityInsertArgs(eventID int64, userID string, source int8, intervals ...[2]time.Time) []driver.Value {
+	var args []driver.Value
+	for _, iv := range intervals {
+		args = append(args, eventID, userID, iv[0].Format("2006-01-02"), iv[0].Format(time.RFC3339), iv[1].Format(time.RFC3339), source, anyTime{})
+	}
+	return args
+}
+
+// expectReplaceAvailabilities は ReplaceUserAvailabilitiesBySources で sources の空き時間を削除し、args の空き時間を挿入する
+func expectReplaceAvailabilities(mock sqlmock.Sqlmock, sources []int8, args []driver.Value) {
+	mock.ExpectExec(`SAVEPOINT`).WillReturnResult(sqlmock.NewResult(0, 0))
+	deleteArgs := []driver.Value{sqlmock.AnyArg(), sqlmock.AnyArg()}
+	for _, s := range sources {
+		deleteArgs = append(deleteArgs, s)
+	}
+	mock.ExpectExec(`DELETE FROM "Availabilities" WHERE event_id = \$1 AND user_id = \$2 AND sourse IN`).
+		WithArgs(deleteArgs...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	if len(args) > 0 {
+		rows := sqlmock.NewRows([]string{"id"})
+		for i := 0; i < len(args)/7; i++ {
+			rows.AddRow(100 + i)
+		}
+		mock.ExpectQuery(`INSERT INTO "Availabilities"`).WithArgs(args...).WillReturnRows(rows)
+	}
+}
+
+// anyTime は time.Time の引数に一致する
+type anyTime struct{}
+
+func (anyTime) Match(v driver.Value) bool {
+	_, ok := v.(time.Time)
+	return ok
+}
+
+// recentTime は現在時刻の前後 1 分以内の引数に一致する
+type recentTime struct{}
+
+func (recentTime) Match(v driver.Value) bool {
+	t, ok := v.(time.Time)
+	return ok && time.Since(t).Abs() < time.Minute
+}
@@ -0,0 +1,46 @@
+// richmenu は LINE ボットのリッチメニューを作成・画像アップロードしてデフォルトに設定する管理コマンド
+//
+//	go run ./cmd/richmenu -image ./env/richmenu.png
+package main
+
+import (
+	"adjuSche-back-end/servise"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/line/line-bot-sdk-go/v7/linebot"
+)
+
+func main() {
+	imagePath := flag.String("image", "", "リッチメニュー画像 (2500x843 の PNG/JPEG)")
+	replace := flag.Bool("replace", true, "既存のリッチメニューを削除する")
+	flag.Parse()
+
+	if *imagePath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if os.Getenv("RENDER") == "" {
+		err := godotenv.Load("./env/.env")
+		if err != nil {
+			log.Fatalf("環境変数の読み込みに失敗しました: %v\n", err)
+		}
+	}
+
+	bot, err := linebot.New(
+		os.Getenv("LINE_BOT_CHANNEL_SECRET"),
+		os.Getenv("LINE_BOT_CHANNEL_TOKEN"),
+	)
+	if err != nil {
+		log.Fatalf("LINEボットの初期化に失敗しました: %v\n", err)
+	}
+
+	richMenuID, err := servise.SetupRichMenu(bot, *imagePath, *replace)
+	if err != nil {
+		log.Fatalf("%v\n", err)
+	}
+	log.Printf("リッチメニューの設定が完了しました: %s", richMenuID)
+}
@@ -0,0 +1,124 @@
+package domain
+
+import "fmt"
+
+// Kind はエラーの種類を表す。HTTP ステータスへの対応は middleware.ErrorHandler が行う
+type Kind string
+
+const (
+	KindBadRequest   Kind = "bad_request"
+	KindUnauthorized Kind = "unauthorized"
+	KindValidation   Kind = "validation"
+	KindNotFound     Kind = "not_found"
+	KindForbidden    Kind = "forbidden"
+	KindConflict     Kind = "conflict"
+	KindClosed       Kind = "closed"
+)
+
+// 種類だけを比較したいときに errors.Is で使う
+// 例: errors.Is(err, domain.ErrNotFound)
+var (
+	ErrBadRequest   = &Error{Kind: KindBadRequest}
+	ErrUnauthorized = &Error{Kind: KindUnauthorized}
+	ErrValidation   = &Error{Kind: KindValidation}
+	ErrNotFound     = &Error{Kind: KindNotFound}
+	ErrForbidden    = &Error{Kind: KindForbidden}
+	ErrConflict     = &Error{Kind: KindConflict}
+	ErrClosed       = &Error{Kind: KindClosed}
+)
+
+// FieldError は入力項目ごとの検証エラーを表す
+// Params はメッセージを翻訳するときに埋め込む値
+type FieldError struct {
+	Field   string
+	Code    string
+	Message string
+	Params  []any
+}
+
+// Error はリポジトリ層からプレゼンテーション層まで伝搬する型付きのエラー
+// Code は API のレスポンスにそのまま使う安定した識別子
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	msg := string(e.Kind)
+	if e.Code != "" {
+		msg += ": " + e.Code
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is は Code が空のターゲットとは Kind で、それ以外は Kind と Code で一致を判定する
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	if t.Kind != e.Kind {
+		return false
+	}
+	return t.Code == "" || t.Code == e.Code
+}
+
+// Wrap は原因となったエラーを保持したコピーを返す
+func (e *Error) Wrap(cause error) *Error {
+	c := *e
+	c.Err = cause
+	return &c
+}
+
+// Wrapf は原因となったエラーをメッセージ付きで保持したコピーを返す
+func (e *Error) Wrapf(format string, args ...any) *Error {
+	return e.Wrap(fmt.Errorf(format, args...))
+}
+
+func newError(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func BadRequest(code, message string) *Error {
+	return newError(KindBadRequest, code, message)
+}
+
+func Unauthorized(code, message string) *Error {
+	return newError(KindUnauthorized, code, message)
+}
+
+func NotFound(code, message string) *Error {
+	return newError(KindNotFound, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return newError(KindForbidden, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return newError(KindConflict, code, message)
+}
+
+func Closed(code, message string) *Error {
+	return newError(KindClosed, code, message)
+}
+
+// Validation は入力項目ごとの検証エラーをまとめたエラーを返す
+func Validation(fields []FieldError) *Error {
+	return &Error{
+		Kind:    KindValidation,
+		Code:    "validation_failed",
+		Message: "入力内容に誤りがあります",
+		Fields:  fields,
+	}
+}
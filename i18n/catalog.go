@@ -0,0 +1,245 @@
+package i18n
+
+// catalog は言語ごとのメッセージ
+// エラーは API のエラーコード、入力項目のエラーは "validation.<項目>.<コード>"（項目によらないものは "validation.<コード>"）、
+// LINE の返信は "line." で始まるキーで引く
+var catalog = map[Lang]map[string]string{
+	Ja: {
+		// API エラー
+		"internal_error":              "サーバー内部でエラーが発生しました",
+		"validation_failed":           "入力内容に誤りがあります",
+		"invalid_request_body":        "無効なリクエストボディです",
+		"auth_token_required":         "認証トークンが必要です",
+		"invalid_token":               "提供されたトークンが無効です",
+		"invalid_event_id":            "eventId は数値で指定してください",
+		"login_required":              "ログインが必要です",
+		"invalid_status":              "status は draft, open, closed, canceled のいずれかで指定してください",
+		"invalid_limit":               "limit は正の整数で指定してください",
+		"invalid_offset":              "offset は0以上の整数で指定してください",
+		"invalid_signature":           "署名が不正です",
+		"invalid_calendar_request":    "JSON形式のstart_date, end_dateを指定してください (RFC3339)",
+		"invalid_start_date":          "start_dateはRFC3339形式で指定してください",
+		"invalid_end_date":            "end_dateはRFC3339形式で指定してください",
+		"invalid_date_range":          "end_dateはstart_date以降である必要があります",
+		"invalid_duration":            "durationMinは0以上で指定してください",
+		"event_not_found":             "イベントが見つかりません",
+		"event_condition_not_found":   "イベントの条件が見つかりません",
+		"line_account_link_not_found": "LINEアカウントが連携されていません",
+		"line_link_nonce_not_found":   "アカウント連携の有効期限が切れています",
+		"line_account_not_linked":     "LINEアカウントが連携されていません。LINEで「アカウント連携」と送信してください",
+		"invalid_line_id_token":       "IDトークンの検証に失敗しました",
+		"invalid_access_token":        "アクセストークンの検証に失敗しました",
+		"not_event_host":              "イベントの主催者のみ操作できます",
+		"event_canceled":              "中止されたイベントは変更できません",
+		"event_not_finalized":         "日程が確定していないイベントです",
+		"calendar_feed_not_found":     "カレンダーフィードが見つかりません",
+		"invalid_ics":                 "カレンダーファイル (.ics) を読み取れませんでした",
+		"ics_fetch_failed":            "カレンダーファイル (.ics) の URL から取得できませんでした",
+		"calendar_fetch_failed":       "カレンダーから予定を取得できませんでした。URL・ユーザー名・パスワードを確認してください",
+		"invalid_calendar_provider":   "provider には google または microsoft を指定してください",
+		"calendar_access_failed":      "カレンダーから予定を取得できませんでした。カレンダーへのアクセスを許可し直してください",
+		"invalid_webhook_id":          "Webhook の ID は数値で指定してください",
+		"webhook_not_found":           "Webhook が見つかりません",
+		"webhook_delivery_not_found":  "Webhook の送信履歴が見つかりません",
+		"preview_not_found":           "空き時間のプレビューが見つからないか、有効期限が切れています",
+		"participant_not_found":       "イベントの参加者ではありません",
+		"user_not_found":              "ユーザーが見つかりません",
+		"already_accepted":            "参加を受諾済みです。参加を取り消す場合は withdraw を使ってください",
+		"invalid_guest_token":         "ゲストのトークンが無効です",
+		"guest_not_found":             "ゲストが見つかりません",
+		"guest_token_required":        "ゲストのトークンが必要です",
+
+		// 入力項目のエラー
+		"validation.hostUserID.required":                      "主催者のユーザーIDを指定してください",
+		"validation.title.required":                           "タイトルを入力してください",
+		"validation.participantCount.must_be_positive":        "参加人数は1以上で指定してください",
+		"validation.periodStart.required":                     "期間の開始日を指定してください",
+		"validation.periodStart.invalid_format":               "期間の開始日は YYYY-MM-DD または RFC3339 形式で指定してください",
+		"validation.periodEnd.required":                       "期間の終了日を指定してください",
+		"validation.periodEnd.invalid_format":                 "期間の終了日は YYYY-MM-DD または RFC3339 形式で指定してください",
+		"validation.periodEnd.period_order":                   "期間の終了日は開始日以降を指定してください",
+		"validation.periodEnd.period_too_long":                "期間は%d日以内で指定してください",
+		"validation.timeStart.invalid_format":                 "開始時刻は HH:MM 形式で指定してください",
+		"validation.timeEnd.invalid_format":                   "終了時刻は HH:MM 形式で指定してください",
+		"validation.timeStart.time_range_incomplete":          "開始時刻と終了時刻は両方指定してください",
+		"validation.timeEnd.time_range_incomplete":            "開始時刻と終了時刻は両方指定してください",
+		"validation.timeEnd.time_order":                       "終了時刻は開始時刻より後を指定してください",
+		"validation.durationMin.must_be_positive":             "所要時間は1分以上で指定してください",
+		"validation.durationMin.duration_exceeds_time_window": "所要時間が指定した時間帯に収まりません",
+		"validation.start.required":                           "開始日時を指定してください",
+		"validation.start.invalid_format":                     "開始日時は RFC3339 形式で指定してください",
+		"validation.start.outside_period":                     "日時はイベントの期間内で指定してください",
+		"validation.end.required":                             "終了日時を指定してください",
+		"validation.end.invalid_format":                       "終了日時は RFC3339 形式で指定してください",
+		"validation.end.time_order":                           "終了日時は開始日時より後を指定してください",
+		"validation.userId.required":                          "ユーザーIDを指定してください",
+		"validation.previewToken.required":                    "プレビューのトークンを指定してください",
+		"validation.reason.too_long":                          "理由は%d文字以内で入力してください",
+		"validation.displayName.required":                     "表示名を入力してください",
+		"validation.displayName.too_long":                     "表示名は%d文字以内で入力してください",
+		"validation.email.invalid_format":                     "メールアドレスの形式が正しくありません",
+		"validation.availabilities.too_many":                  "空き時間は%d件以内で指定してください",
+		"validation.avatarUrl.invalid_format":                 "アイコンの URL は http または https で指定してください",
+		"validation.timezone.invalid_format":                  "タイムゾーンは Asia/Tokyo のような IANA の名前で指定してください",
+		"validation.workStart.invalid_format":                 "勤務時間の開始時刻は HH:MM 形式で指定してください",
+		"validation.workEnd.invalid_format":                   "勤務時間の終了時刻は HH:MM 形式で指定してください",
+		"validation.workStart.time_range_incomplete":          "勤務時間の開始時刻と終了時刻は両方指定してください",
+		"validation.workEnd.time_range_incomplete":            "勤務時間の開始時刻と終了時刻は両方指定してください",
+		"validation.workEnd.time_order":                       "勤務時間の終了時刻は開始時刻より後を指定してください",
+		"validation.rules.too_many":                           "ルールは%d件以内で指定してください",
+		"validation.weekday.invalid_format":                   "曜日は0（日曜）〜6（土曜）で指定してください",
+		"validation.kind.invalid_format":                      "kind は available または unavailable で指定してください",
+		"validation.startTime.invalid_format":                 "開始時刻は HH:MM 形式で指定してください",
+		"validation.endTime.invalid_format":                   "終了時刻は HH:MM 形式で指定してください",
+		"validation.endTime.time_order":                       "終了時刻は開始時刻より後を指定してください",
+		"validation.file.required":                            ".ics ファイルまたは URL を指定してください",
+		"validation.url.required":                             "URL を指定してください",
+		"validation.url.invalid_format":                       "URL は http または https で指定してください",
+		"validation.url.not_public_address":                   "URL には公開されているホストを指定してください",
+		"validation.invalid_type":                             "値の型が正しくありません（%s で指定してください）",
+		"validation.events.unknown_event_type":                "通知するイベントの種類が正しくありません: %s",
+
+		// LINE の返信
+		"line.keyword.schedule":         "日程調整",
+		"line.keyword.account_link":     "アカウント連携",
+		"line.keyword.my_events":        "マイイベント",
+		"line.default":                  "日程調整をしたい場合は、「日程調整」と入力してください。",
+		"line.help":                     "メニューの「新規イベント」または「日程調整」と入力すると、日程調整フォームのURLを送ります。\n「マイイベント」と入力すると、主催・参加しているイベントを確認できます。\n「アカウント連携」と入力すると、LINEとアプリのアカウントを連携できます。",
+		"line.account_link.user_only":   "アカウント連携は個別のトークでのみ利用できます。",
+		"line.account_link.start_error": "アカウント連携の開始に失敗しました。時間をおいて再度お試しください。",
+		"line.account_link.prompt":      "以下のURLからログインしてアカウントを連携してください。\n%s",
+		"line.account_link.failed":      "アカウント連携に失敗しました。もう一度「アカウント連携」と入力してください。",
+		"line.account_link.done":        "アカウント連携が完了しました。",
+		"line.my_events.not_linked":     "イベントを確認するには、まず「アカウント連携」と入力してアカウントを連携してください。",
+		"line.my_events.error":          "イベントの取得に失敗しました。時間をおいて再度お試しください。",
+		"line.my_events.empty":          "主催・参加しているイベントはありません。",
+		"line.my_events.header":         "マイイベント（%d件中%d件）",
+		"line.my_events.item":           "・%s［%s］%s\n  投票 %d/%d人",
+		"line.my_events.decided":        "  確定: %s",
+		"line.role.host":                "主催",
+		"line.role.participant":         "参加",
+		"line.status.draft":             "下書き",
+		"line.status.open":              "募集中",
+		"line.status.closed":            "締切",
+		"line.status.canceled":          "中止",
+	},
+	En: {
+		// API エラー
+		"internal_error":              "An internal server error occurred",
+		"validation_failed":           "Some fields are invalid",
+		"invalid_request_body":        "Invalid request body",
+		"auth_token_required":         "An authentication token is required",
+		"invalid_token":               "The provided token is invalid",
+		"invalid_event_id":            "eventId must be a number",
+		"login_required":              "You need to sign in",
+		"invalid_status":              "status must be one of draft, open, closed or canceled",
+		"invalid_limit":               "limit must be a positive integer",
+		"invalid_offset":              "offset must be zero or a positive integer",
+		"invalid_signature":           "Invalid signature",
+		"invalid_calendar_request":    "Specify start_date and end_date as JSON (RFC3339)",
+		"invalid_start_date":          "start_date must be in RFC3339 format",
+		"invalid_end_date":            "end_date must be in RFC3339 format",
+		"invalid_date_range":          "end_date must not be before start_date",
+		"invalid_duration":            "durationMin must be zero or greater",
+		"event_not_found":             "Event not found",
+		"event_condition_not_found":   "Event conditions not found",
+		"line_account_link_not_found": "Your LINE account is not linked",
+		"line_link_nonce_not_found":   "The account link request has expired",
+		"line_account_not_linked":     "Your LINE account is not linked. Send \"link account\" to the bot on LINE",
+		"invalid_line_id_token":       "Failed to verify the ID token",
+		"invalid_access_token":        "Failed to verify the access token",
+		"not_event_host":              "Only the host can manage this event",
+		"event_canceled":              "A canceled event cannot be changed",
+		"event_not_finalized":         "The date of this event has not been decided yet",
+		"calendar_feed_not_found":     "Calendar feed not found",
+		"invalid_ics":                 "The calendar file (.ics) could not be read",
+		"ics_fetch_failed":            "The calendar file (.ics) could not be downloaded from the URL",
+		"calendar_fetch_failed":       "Could not fetch events from the calendar. Check the URL, user name and password",
+		"invalid_calendar_provider":   "provider must be google or microsoft",
+		"calendar_access_failed":      "Could not fetch events from the calendar. Please grant calendar access again",
+		"invalid_webhook_id":          "The webhook ID must be a number",
+		"webhook_not_found":           "Webhook not found",
+		"webhook_delivery_not_found":  "Webhook delivery not found",
+		"preview_not_found":           "The availability preview was not found or has expired",
+		"participant_not_found":       "You are not a participant of this event",
+		"user_not_found":              "User not found",
+		"already_accepted":            "You have already accepted. Use withdraw to cancel your participation",
+		"invalid_guest_token":         "The guest token is invalid",
+		"guest_not_found":             "Guest not found",
+		"guest_token_required":        "A guest token is required",
+
+		// 入力項目のエラー
+		"validation.hostUserID.required":                      "Specify the host user ID",
+		"validation.title.required":                           "Enter a title",
+		"validation.participantCount.must_be_positive":        "The number of participants must be at least 1",
+		"validation.periodStart.required":                     "Specify the start date of the period",
+		"validation.periodStart.invalid_format":               "The start date must be YYYY-MM-DD or RFC3339",
+		"validation.periodEnd.required":                       "Specify the end date of the period",
+		"validation.periodEnd.invalid_format":                 "The end date must be YYYY-MM-DD or RFC3339",
+		"validation.periodEnd.period_order":                   "The end date must not be before the start date",
+		"validation.periodEnd.period_too_long":                "The period must be %d days or shorter",
+		"validation.timeStart.invalid_format":                 "The start time must be HH:MM",
+		"validation.timeEnd.invalid_format":                   "The end time must be HH:MM",
+		"validation.timeStart.time_range_incomplete":          "Specify both the start time and the end time",
+		"validation.timeEnd.time_range_incomplete":            "Specify both the start time and the end time",
+		"validation.timeEnd.time_order":                       "The end time must be after the start time",
+		"validation.durationMin.must_be_positive":             "The duration must be at least 1 minute",
+		"validation.durationMin.duration_exceeds_time_window": "The duration does not fit in the time range",
+		"validation.start.required":                           "Specify the start date and time",
+		"validation.start.invalid_format":                     "The start must be in RFC3339 format",
+		"validation.start.outside_period":                     "The date must be within the event period",
+		"validation.end.required":                             "Specify the end date and time",
+		"validation.end.invalid_format":                       "The end must be in RFC3339 format",
+		"validation.end.time_order":                           "The end must be after the start",
+		"validation.userId.required":                          "Specify the user ID",
+		"validation.previewToken.required":                    "Specify the preview token",
+		"validation.reason.too_long":                          "The reason must be %d characters or fewer",
+		"validation.displayName.required":                     "Enter a display name",
+		"validation.displayName.too_long":                     "The display name must be %d characters or fewer",
+		"validation.email.invalid_format":                     "The email address is not valid",
+		"validation.availabilities.too_many":                  "Specify at most %d availability ranges",
+		"validation.avatarUrl.invalid_format":                 "The avatar URL must use http or https",
+		"validation.timezone.invalid_format":                  "The time zone must be an IANA name such as Asia/Tokyo",
+		"validation.workStart.invalid_format":                 "The start of working hours must be HH:MM",
+		"validation.workEnd.invalid_format":                   "The end of working hours must be HH:MM",
+		"validation.workStart.time_range_incomplete":          "Specify both the start and the end of working hours",
+		"validation.workEnd.time_range_incomplete":            "Specify both the start and the end of working hours",
+		"validation.workEnd.time_order":                       "The end of working hours must be after the start",
+		"validation.rules.too_many":                           "Specify at most %d rules",
+		"validation.weekday.invalid_format":                   "The weekday must be 0 (Sunday) to 6 (Saturday)",
+		"validation.kind.invalid_format":                      "kind must be available or unavailable",
+		"validation.startTime.invalid_format":                 "The start time must be HH:MM",
+		"validation.endTime.invalid_format":                   "The end time must be HH:MM",
+		"validation.endTime.time_order":                       "The end time must be after the start time",
+		"validation.file.required":                            "Specify an .ics file or URL",
+		"validation.url.required":                             "Specify the URL",
+		"validation.url.invalid_format":                       "The URL must use http or https",
+		"validation.url.not_public_address":                   "The URL must point to a publicly reachable host",
+		"validation.invalid_type":                             "Invalid type (expected a %s)",
+		"validation.events.unknown_event_type":                "Unknown event type: %s",
+
+		// LINE の返信
+		"line.keyword.schedule":         "schedule",
+		"line.keyword.account_link":     "link account",
+		"line.keyword.my_events":        "my events",
+		"line.default":                  "To schedule an event, send \"schedule\".",
+		"line.help":                     "Tap \"New event\" in the menu or send \"schedule\" to get the scheduling form URL.\nSend \"my events\" to see the events you host or join.\nSend \"link account\" to link your LINE account with the app.",
+		"line.account_link.user_only":   "Account linking is only available in a one-on-one chat.",
+		"line.account_link.start_error": "Failed to start account linking. Please try again later.",
+		"line.account_link.prompt":      "Sign in from the URL below to link your account.\n%s",
+		"line.account_link.failed":      "Account linking failed. Send \"link account\" to try again.",
+		"line.account_link.done":        "Your account has been linked.",
+		"line.my_events.not_linked":     "To see your events, send \"link account\" and link your account first.",
+		"line.my_events.error":          "Failed to load your events. Please try again later.",
+		"line.my_events.empty":          "You have no events.",
+		"line.my_events.header":         "My events (%[2]d of %[1]d)",
+		"line.my_events.item":           "- %s [%s] %s\n  Votes %d/%d",
+		"line.my_events.decided":        "  Decided: %s",
+		"line.role.host":                "host",
+		"line.role.participant":         "participant",
+		"line.status.draft":             "draft",
+		"line.status.open":              "open",
+		"line.status.closed":            "closed",
+		"line.status.canceled":          "canceled",
+	},
+}
@@ -0,0 +1,110 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lang はメッセージの言語を表す
+type Lang string
+
+const (
+	Ja Lang = "ja"
+	En Lang = "en"
+)
+
+// Default は対応する言語が見つからない場合に使う言語
+const Default = Ja
+
+// T は key に対応するメッセージを lang で返す
+// lang に無ければ既定の言語、それも無ければ key をそのまま返す
+func T(lang Lang, key string, args ...any) string {
+	msg, ok := Lookup(lang, key, args...)
+	if !ok {
+		return key
+	}
+	return msg
+}
+
+// Lookup は key に対応するメッセージを lang で返す。カタログに無い場合は false を返す
+func Lookup(lang Lang, key string, args ...any) (string, bool) {
+	msg, ok := catalog[lang][key]
+	if !ok {
+		msg, ok = catalog[Default][key]
+	}
+	if !ok {
+		return "", false
+	}
+	if len(args) > 0 {
+		msg = fmt.Sprintf(msg, args...)
+	}
+	return msg, true
+}
+
+// Parse は言語タグ（"en-US", "ja" など）を対応する言語に変換する
+func Parse(tag string) (Lang, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	primary, _, _ := strings.Cut(tag, "-")
+	switch Lang(primary) {
+	case Ja:
+		return Ja, true
+	case En:
+		return En, true
+	}
+	return "", false
+}
+
+// FromAcceptLanguage は Accept-Language ヘッダの q 値を考慮して対応する言語を選ぶ
+func FromAcceptLanguage(header string) Lang {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		candidates = append(candidates, candidate{tag: tag, q: q})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		if c.q <= 0 {
+			continue
+		}
+		if lang, ok := Parse(c.tag); ok {
+			return lang
+		}
+	}
+	return Default
+}
+
+// FromLineLanguage は LINE のプロフィールの language（"ja", "en", "zh-Hant" など）から言語を選ぶ
+func FromLineLanguage(language string) Lang {
+	if lang, ok := Parse(language); ok {
+		return lang
+	}
+	return Default
+}
+
+// Matches は text がいずれかの言語の key のメッセージと一致するかを返す
+// LINE のキーワードのように、ユーザーの言語設定にかかわらず受け付けたい入力の判定に使う
+func Matches(key, text string) bool {
+	text = strings.TrimSpace(text)
+	for _, messages := range catalog {
+		if msg, ok := messages[key]; ok && strings.EqualFold(msg, text) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package i18n
+
+import (
+	"regexp"
+	"sort"
+	"testing"
+)
+
+// verbPattern はメッセージの書式指定子（%s, %[2]d など。%% は除く）に一致する
+var verbPattern = regexp.MustCompile(`%(\[\d+\])?[a-zA-Z]`)
+
+func TestCatalogHasEveryKeyInEveryLanguage(t *testing.T) {
+	keys := map[string]bool{}
+	for _, messages := range catalog {
+		for key := range messages {
+			keys[key] = true
+		}
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, lang := range []Lang{Ja, En} {
+		t.Run(string(lang), func(t *testing.T) {
+			for _, key := range sorted {
+				msg, ok := catalog[lang][key]
+				if !ok || msg == "" {
+					t.Errorf("%q has no %s message", key, lang)
+					continue
+				}
+				// 言語によって引数の数が変わると、翻訳側で %!(EXTRA ...) や %!d(MISSING) になる
+				if got, want := len(verbPattern.FindAllString(msg, -1)), len(verbPattern.FindAllString(catalog[Default][key], -1)); got != want {
+					t.Errorf("%q: %s message has %d format verbs, %s has %d", key, lang, got, Default, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFromAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Lang
+	}{
+		{"", Default},
+		{"en", En},
+		{"ja-JP", Ja},
+		{"EN-us", En},
+		{"en-US,en;q=0.9,ja;q=0.8", En},
+		{"ja;q=0.5, en;q=0.8", En},
+		// 対応していない言語は飛ばし、次に優先される対応言語を使う
+		{"fr-FR, en;q=0.8", En},
+		{"zh-Hant, ja;q=0.1", Ja},
+		// 対応する言語が無ければ既定の言語
+		{"fr-FR, de;q=0.9", Default},
+		{"*", Default},
+		// q=0 は「受け付けない」
+		{"en;q=0, fr", Default},
+		{",,; q=abc", Default},
+	}
+	for _, tt := range tests {
+		if got := FromAcceptLanguage(tt.header); got != tt.want {
+			t.Errorf("FromAcceptLanguage(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestTFallsBack(t *testing.T) {
+	tests := []struct {
+		name string
+		lang Lang
+		key  string
+		want string
+	}{
+		{"requested language", En, "login_required", catalog[En]["login_required"]},
+		{"unsupported language uses default", Lang("fr"), "login_required", catalog[Default]["login_required"]},
+		{"unknown key is returned as is", En, "no_such_key", "no_such_key"},
+	}
+	for _, tt := range tests {
+		if got := T(tt.lang, tt.key); got != tt.want {
+			t.Errorf("%s: T(%q, %q) = %q, want %q", tt.name, tt.lang, tt.key, got, tt.want)
+		}
+	}
+}
@@ -1,12 +1,11 @@
 package main
 
 import (
+	"adjuSche-back-end/application"
 	"adjuSche-back-end/middleware"
 	"adjuSche-back-end/presentation"
-	"adjuSche-back-end/servise"
-	"fmt"
+	"context"
 	"log"
-	"net/http"
 	"os"
 
 	"github.com/gin-gonic/gin"
@@ -22,73 +21,89 @@ func main() {
 		}
 	}
 
+	// EVENT_HUB=postgres のとき、他のサーバーで保存された空き時間の更新を受け取る
+	application.StartEventUpdateListener(context.Background())
+
+	// 前回の起動中に送信が中断された Webhook を送り直す
+	go func() {
+		if err := application.ResumePendingWebhookDeliveries(context.Background()); err != nil {
+			log.Printf("中断された Webhook の送信の再開に失敗しました: %v", err)
+		}
+	}()
+
 	r := gin.Default()
 
 	r.Use(middleware.CorsMiddleware())
+	r.Use(middleware.ErrorHandler())
 
 	r.GET("/hello", func(c *gin.Context) {
 		c.String(200, "Hello, World!")
 	})
 
-	r.POST("/calendar", servise.GetGoogleCalendarEvents)
-
-	r.POST("/line/webhook", handleLineWebhook)
+	r.POST("/calendar", presentation.GetCalendarFreeIntervals)
 
-	r.POST("/event", presentation.CreateEvent)
-
-	r.POST("/invite", presentation.InviteUser)
-
-	r.POST("/event/Name", presentation.GetEventNameByID)
-
-	log.Println("サーバーを起動しています... http://localhost:8080")
-	r.Run(":8080")
-}
+	r.POST("/session", presentation.CreateSession)
 
-func handleLineWebhook(c *gin.Context) {
+	// LINE の設定が無い・誤っている環境でも LINE 以外の API は使えるように、/line/* だけを無効にして起動する
 	bot, err := linebot.New(
 		os.Getenv("LINE_BOT_CHANNEL_SECRET"),
 		os.Getenv("LINE_BOT_CHANNEL_TOKEN"),
 	)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status": "error",
-			"error":  "LINEボットの初期化に失敗しました",
-		})
-		return
+		log.Printf("警告: LINEボットの初期化に失敗したため /line/* のエンドポイントを無効にします: %v", err)
+	} else {
+		lineHandler := presentation.NewLineHandler(bot)
+		line := r.Group("/line")
+		line.POST("/webhook", lineHandler.Webhook)
+		line.POST("/link", presentation.CreateLineLink)
+		line.POST("/liff/login", presentation.LiffLogin)
 	}
 
-	// リクエスト処理
-	events, berr := bot.ParseRequest(c.Request)
-	if berr != nil {
-		fmt.Println(berr.Error())
-		return
-	}
+	r.POST("/event", presentation.CreateEvent)
 
-	for _, event := range events {
-		if event.Type == linebot.EventTypeMessage {
-			switch message := event.Message.(type) {
-			case *linebot.TextMessage:
-				_, rerr := bot.ReplyMessage(
-					event.ReplyToken,
-					linebot.NewTextMessage(getResMessage(message.Text)),
-				).Do()
-				if rerr != nil {
-					fmt.Println(rerr.Error())
-				}
-			}
-		}
-	}
-}
+	r.POST("/invite", presentation.InviteUser)
 
-func getResMessage(message string) string {
-	if message == "日程調整" {
-		formURL := getFormURL()
-		return formURL
-	}
-	return "日程調整をしたい場合は、「日程調整」と入力してください。"
-}
+	r.POST("/event/Name", presentation.GetEventNameByID)
 
-func getFormURL() string {
-	// TODO: LINEのメッセージ解析やフォーム生成ロジックの実装
-	return "https://adju-sche-front-end.vercel.app/admin/create"
+	r.GET("/me/events", presentation.GetMyEvents)
+	r.GET("/me/profile", presentation.GetMyProfile)
+	r.PATCH("/me/profile", presentation.UpdateMyProfile)
+	r.GET("/me/availability-rules", presentation.GetMyAvailabilityRules)
+	r.PUT("/me/availability-rules", presentation.ReplaceMyAvailabilityRules)
+	r.GET("/me/calendar-feed", presentation.GetCalendarFeedURL)
+	r.POST("/me/calendar-feed/rotate", presentation.RotateCalendarFeed)
+	r.GET("/feeds/:token/calendar.ics", presentation.GetCalendarFeed)
+	r.GET("/me/webhooks", presentation.GetWebhooks)
+	r.POST("/me/webhooks", presentation.CreateWebhook)
+	r.DELETE("/me/webhooks/:id", presentation.DeleteWebhook)
+	r.GET("/me/webhooks/:id/deliveries", presentation.GetWebhookDeliveries)
+	r.POST("/me/webhook-deliveries/:id/replay", presentation.ReplayWebhookDelivery)
+
+	r.GET("/events/:id", presentation.GetEvent)
+	r.GET("/events/:id/invite", presentation.GetInvite)
+	r.GET("/events/:id/stream", presentation.GetEventStream)
+	r.PATCH("/events/:id", presentation.UpdateEvent)
+	r.DELETE("/events/:id", presentation.DeleteEvent)
+	r.POST("/events/:id/finalize", presentation.FinalizeEvent)
+	r.POST("/events/:id/cancel", presentation.CancelEvent)
+	r.POST("/events/:id/decline", presentation.DeclineEvent)
+	r.POST("/events/:id/withdraw", presentation.WithdrawFromEvent)
+	r.POST("/events/:id/guests", presentation.JoinAsGuest)
+	r.GET("/events/:id/guests/me", presentation.GetGuest)
+	r.PUT("/events/:id/guests/me/availability", presentation.SubmitGuestAvailability)
+	r.GET("/events/:id/conditions", presentation.GetEventConditions)
+	r.PATCH("/events/:id/conditions", presentation.UpdateEventCondition)
+	r.GET("/events/:id/calendar.ics", presentation.GetEventICS)
+	r.GET("/events/:id/candidates.ics", presentation.GetEventCandidatesICS)
+	r.GET("/events/:id/availability.csv", presentation.GetAvailabilityCSV)
+	r.GET("/events/:id/availability.xlsx", presentation.GetAvailabilityXLSX)
+	r.POST("/events/:id/availability/calendar", presentation.SubmitCalendarAvailability)
+	r.POST("/events/:id/availability/calendar/preview", presentation.PreviewCalendarAvailability)
+	r.POST("/events/:id/availability/confirm", presentation.ConfirmAvailability)
+	r.PUT("/events/:id/availability/manual", presentation.SubmitManualAvailability)
+	r.POST("/events/:id/availability/ics", presentation.ImportAvailabilityICS)
+	r.POST("/events/:id/availability/caldav", presentation.ImportAvailabilityCalDAV)
+
+	log.Println("サーバーを起動しています... http://localhost:8080")
+	r.Run(":8080")
 }
@@ -10,9 +10,9 @@ import (
 func CorsMiddleware() gin.HandlerFunc {
 	config := cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "https://adju-sche.vercel.app"}, // フロントのURL
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Token"},
-		ExposeHeaders:    []string{"Content-Length", "Content-Type", "Content-Disposition"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-Token", "X-Session-Token", "X-Guest-Token", "If-None-Match"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Type", "Content-Disposition", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}
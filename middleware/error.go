@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"adjuSche-back-end/domain"
+	"adjuSche-back-end/i18n"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorResponse は API のエラーレスポンスの共通形式
+// error には従来どおり人が読めるメッセージを入れ、機械判定には code を使う
+type ErrorResponse struct {
+	Status string               `json:"status"`
+	Code   string               `json:"code"`
+	Error  string               `json:"error"`
+	Fields []FieldErrorResponse `json:"fields,omitempty"`
+}
+
+type FieldErrorResponse struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+var kindStatus = map[domain.Kind]int{
+	domain.KindBadRequest:   http.StatusBadRequest,
+	domain.KindUnauthorized: http.StatusUnauthorized,
+	domain.KindValidation:   http.StatusUnprocessableEntity,
+	domain.KindNotFound:     http.StatusNotFound,
+	domain.KindForbidden:    http.StatusForbidden,
+	domain.KindConflict:     http.StatusConflict,
+	domain.KindClosed:       http.StatusConflict,
+}
+
+// ErrorHandler はハンドラが c.Error で登録したエラーを HTTP ステータスと共通のエラーレスポンスに変換する
+// メッセージは Accept-Language で選んだ言語に翻訳する
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		lang := i18n.FromAcceptLanguage(c.GetHeader("Accept-Language"))
+		status, res := NewErrorResponse(lang, err)
+		if status >= http.StatusInternalServerError {
+			log.Printf("リクエストの処理に失敗しました: %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+		}
+		c.JSON(status, res)
+	}
+}
+
+// NewErrorResponse はエラーから HTTP ステータスと lang のメッセージのレスポンスを作る
+// カタログに無いコードはエラーが持つメッセージをそのまま使う
+func NewErrorResponse(lang i18n.Lang, err error) (int, ErrorResponse) {
+	var derr *domain.Error
+	if !errors.As(err, &derr) {
+		return http.StatusInternalServerError, ErrorResponse{
+			Status: "error",
+			Code:   "internal_error",
+			Error:  i18n.T(lang, "internal_error"),
+		}
+	}
+
+	status, ok := kindStatus[derr.Kind]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	res := ErrorResponse{
+		Status: "error",
+		Code:   derr.Code,
+		Error:  derr.Message,
+	}
+	if res.Code == "" {
+		res.Code = string(derr.Kind)
+	}
+	if msg, ok := i18n.Lookup(lang, res.Code); ok {
+		res.Error = msg
+	}
+	for _, f := range derr.Fields {
+		msg, ok := i18n.Lookup(lang, fieldMessageKey(f), f.Params...)
+		if !ok {
+			msg, ok = i18n.Lookup(lang, "validation."+f.Code, f.Params...)
+		}
+		if !ok {
+			msg = f.Message
+		}
+		res.Fields = append(res.Fields, FieldErrorResponse{Field: f.Field, Code: f.Code, Message: msg})
+	}
+	return status, res
+}
+
+// fieldMessageKey は入力項目のエラーのメッセージキーを返す
+// "conditions.periodEnd" のような入れ子の項目は末尾の項目名で引く
+func fieldMessageKey(f domain.FieldError) string {
+	field := f.Field
+	if i := strings.LastIndex(field, "."); i >= 0 {
+		field = field[i+1:]
+	}
+	return "validation." + field + "." + f.Code
+}
@@ -0,0 +1,20 @@
+-- LINE アカウント連携（LINE userId とアプリのユーザーの対応、連携中の nonce）
+
+CREATE TABLE IF NOT EXISTS "LineAccountLinks" (
+    id           bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    line_user_id text        NOT NULL UNIQUE,
+    user_id      uuid        NOT NULL,
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    updated_at   timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS "LineAccountLinks_user_id_idx" ON "LineAccountLinks" (user_id);
+
+-- nonce は一度しか使えない。有効期限の切れたものは使われずに残るだけ
+CREATE TABLE IF NOT EXISTS "LineLinkNonces" (
+    id         bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    nonce      text        NOT NULL UNIQUE,
+    user_id    uuid        NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    expired_at timestamptz NOT NULL
+);
@@ -0,0 +1,9 @@
+-- イベントの確定日時（未確定なら NULL）
+
+ALTER TABLE "Events"
+    ADD COLUMN IF NOT EXISTS decided_start timestamptz,
+    ADD COLUMN IF NOT EXISTS decided_end   timestamptz;
+
+-- 自分のイベント一覧（主催・参加）の絞り込み用
+CREATE INDEX IF NOT EXISTS "Events_host_user_id_idx" ON "Events" (host_user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS "EventParticipants_user_id_idx" ON "EventParticipants" (user_id);
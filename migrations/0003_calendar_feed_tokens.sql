@@ -0,0 +1,8 @@
+-- ユーザーごとの .ics 購読フィードの秘密トークン（1 ユーザー 1 件。再発行すると置き換える）
+
+CREATE TABLE IF NOT EXISTS "CalendarFeedTokens" (
+    id         bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id    uuid        NOT NULL UNIQUE,
+    token      text        NOT NULL UNIQUE,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
@@ -0,0 +1,28 @@
+-- 主催者ごとの Webhook の送信先と送信履歴
+
+CREATE TABLE IF NOT EXISTS "WebhookSubscriptions" (
+    id           bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    host_user_id uuid        NOT NULL,
+    url          text        NOT NULL,
+    secret       text        NOT NULL,
+    event_types  text        NOT NULL DEFAULT '', -- カンマ区切り、空ならすべて
+    created_at   timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS "WebhookSubscriptions_host_user_id_idx" ON "WebhookSubscriptions" (host_user_id);
+
+-- status は 0: 送信中, 1: 成功, 2: 失敗
+CREATE TABLE IF NOT EXISTS "WebhookDeliveries" (
+    id              bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    subscription_id bigint      NOT NULL REFERENCES "WebhookSubscriptions" (id) ON DELETE CASCADE,
+    event_type      text        NOT NULL,
+    payload         text        NOT NULL,
+    status          smallint    NOT NULL DEFAULT 0,
+    attempts        integer     NOT NULL DEFAULT 0,
+    response_status integer     NOT NULL DEFAULT 0,
+    last_error      text,
+    created_at      timestamptz NOT NULL DEFAULT now(),
+    delivered_at    timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS "WebhookDeliveries_subscription_id_idx" ON "WebhookDeliveries" (subscription_id, id DESC);
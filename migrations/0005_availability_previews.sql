@@ -0,0 +1,16 @@
+-- 保存前に確認してもらう空き時間のプレビュー（確定前の下書き）
+-- 確定すると削除し、有効期限の切れたものは次のプレビューの作成時にまとめて削除する
+
+CREATE TABLE IF NOT EXISTS "AvailabilityPreviews" (
+    id         bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    token      text        NOT NULL UNIQUE,
+    event_id   bigint      NOT NULL,
+    user_id    uuid        NOT NULL,
+    sourse     smallint    NOT NULL,
+    intervals  text        NOT NULL, -- [{"start": RFC3339, "end": RFC3339}]
+    created_at timestamptz NOT NULL DEFAULT now(),
+    expired_at timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "AvailabilityPreviews_event_id_user_id_idx" ON "AvailabilityPreviews" (event_id, user_id);
+CREATE INDEX IF NOT EXISTS "AvailabilityPreviews_expired_at_idx" ON "AvailabilityPreviews" (expired_at);
@@ -0,0 +1,5 @@
+-- 参加者の辞退・参加取り消しの理由と日時
+
+ALTER TABLE "EventParticipants"
+    ADD COLUMN IF NOT EXISTS decline_reason text,
+    ADD COLUMN IF NOT EXISTS declined_at    timestamptz;
@@ -0,0 +1,12 @@
+-- アカウントを持たずに招待 URL から参加したゲスト
+-- user_id はゲストごとに発行する UUID で、EventParticipants / Availabilities ではログインユーザーと同じように扱う
+
+CREATE TABLE IF NOT EXISTS "EventGuests" (
+    id           bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    event_id     bigint      NOT NULL,
+    user_id      uuid        NOT NULL,
+    display_name text        NOT NULL,
+    email        text,
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (event_id, user_id)
+);
@@ -0,0 +1,13 @@
+-- ユーザーのプロフィール（id は Availabilities などの user_id と同じ UUID）
+
+CREATE TABLE IF NOT EXISTS "Users" (
+    id           uuid PRIMARY KEY,
+    display_name text        NOT NULL DEFAULT '',
+    avatar_url   text        NOT NULL DEFAULT '',
+    timezone     text        NOT NULL DEFAULT 'Asia/Tokyo', -- IANA のタイムゾーン名
+    work_start   text,                                      -- 勤務時間 (HH:MM)。未設定なら NULL
+    work_end     text,
+    google_id    text,
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    updated_at   timestamptz NOT NULL DEFAULT now()
+);
@@ -0,0 +1,14 @@
+-- ユーザーの毎週繰り返す空いている・空いていない時間帯
+-- weekday は 0: 日曜 〜 6: 土曜、kind は 0: 空いていない, 1: 空いている
+
+CREATE TABLE IF NOT EXISTS "AvailabilityRules" (
+    id         bigint GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY,
+    user_id    uuid        NOT NULL,
+    weekday    smallint    NOT NULL CHECK (weekday BETWEEN 0 AND 6),
+    start_time text        NOT NULL, -- HH:MM
+    end_time   text        NOT NULL, -- HH:MM（24:00 は日付の終わり）
+    kind       smallint    NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS "AvailabilityRules_user_id_idx" ON "AvailabilityRules" (user_id, weekday);
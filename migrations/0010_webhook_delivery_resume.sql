@@ -0,0 +1,7 @@
+-- 送信中に中断された Webhook の再開
+-- next_attempt_at は送信中の送信履歴の結果が保存されるはずの期限
+-- これを過ぎても送信中のままなら、サーバーの再起動などで送信が中断されたとみなして起動時に送り直す
+
+ALTER TABLE "WebhookDeliveries" ADD COLUMN IF NOT EXISTS next_attempt_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS "WebhookDeliveries_pending_idx" ON "WebhookDeliveries" (next_attempt_at) WHERE status = 0;
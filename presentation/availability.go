@@ -0,0 +1,31 @@
+package presentation
+
+import (
+	"adjuSche-back-end/servise"
+	"time"
+)
+
+type availabilityInterval struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type ImportAvailabilityResponse struct {
+	Status         string                 `json:"status"`
+	Availabilities []availabilityInterval `json:"availabilities"`
+}
+
+func newImportAvailabilityResponse(free []servise.TimeInterval) ImportAvailabilityResponse {
+	return ImportAvailabilityResponse{Status: "success", Availabilities: newAvailabilityIntervals(free)}
+}
+
+func newAvailabilityIntervals(free []servise.TimeInterval) []availabilityInterval {
+	res := make([]availabilityInterval, 0, len(free))
+	for _, iv := range free {
+		res = append(res, availabilityInterval{
+			Start: iv.Start.Format(time.RFC3339),
+			End:   iv.End.Format(time.RFC3339),
+		})
+	}
+	return res
+}
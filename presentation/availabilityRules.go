@@ -0,0 +1,81 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type availabilityRule struct {
+	Weekday   int    `json:"weekday"`   // 0: 日曜 〜 6: 土曜
+	StartTime string `json:"startTime"` // HH:MM
+	EndTime   string `json:"endTime"`   // HH:MM または 24:00
+	Kind      string `json:"kind"`      // available または unavailable
+}
+
+type AvailabilityRulesRequest struct {
+	Rules []availabilityRule `json:"rules"`
+}
+
+type AvailabilityRulesResponse struct {
+	Rules []availabilityRule `json:"rules"`
+}
+
+// GetMyAvailabilityRules はセッションのユーザーの毎週繰り返すルールを返す
+func GetMyAvailabilityRules(c *gin.Context) {
+	userID, err := servise.ExtractSessionUserID(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return
+	}
+
+	rules, err := application.ListAvailabilityRules(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newAvailabilityRulesResponse(rules))
+}
+
+// ReplaceMyAvailabilityRules はセッションのユーザーの毎週繰り返すルールを、リクエストの内容で置き換える
+// ルールは以後、参加するすべてのイベントのカレンダーからの取り込みと手入力の空き時間に適用される
+func ReplaceMyAvailabilityRules(c *gin.Context) {
+	userID, err := servise.ExtractSessionUserID(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return
+	}
+
+	var req AvailabilityRulesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindJSONError(err))
+		return
+	}
+	in := make([]application.AvailabilityRuleInput, 0, len(req.Rules))
+	for _, r := range req.Rules {
+		in = append(in, application.AvailabilityRuleInput{Weekday: r.Weekday, StartTime: r.StartTime, EndTime: r.EndTime, Kind: r.Kind})
+	}
+
+	rules, err := application.ReplaceAvailabilityRules(c.Request.Context(), userID, in)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newAvailabilityRulesResponse(rules))
+}
+
+func newAvailabilityRulesResponse(rules []repository.AvailabilityRule) AvailabilityRulesResponse {
+	res := AvailabilityRulesResponse{Rules: make([]availabilityRule, 0, len(rules))}
+	for _, r := range rules {
+		res.Rules = append(res.Rules, availabilityRule{
+			Weekday:   int(r.Weekday),
+			StartTime: r.StartTime,
+			EndTime:   r.EndTime,
+			Kind:      application.AvailabilityRuleKindName(r.Kind),
+		})
+	}
+	return res
+}
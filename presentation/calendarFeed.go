@@ -0,0 +1,100 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/servise"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// フィードのトークンの発行と .ics の作成（テストで DB を使わない実装に差し替える）
+var (
+	getCalendarFeedToken    = application.GetCalendarFeedToken
+	rotateCalendarFeedToken = application.RotateCalendarFeedToken
+	buildCalendarFeed       = application.BuildCalendarFeed
+)
+
+type CalendarFeedResponse struct {
+	FeedURL   string `json:"feedUrl"`
+	WebcalURL string `json:"webcalUrl"`
+}
+
+// GetCalendarFeedURL はセッションのユーザーの購読フィードの URL を返す（未発行なら発行する）
+func GetCalendarFeedURL(c *gin.Context) {
+	userID, err := servise.ExtractSessionUserID(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return
+	}
+
+	token, err := getCalendarFeedToken(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newCalendarFeedResponse(c, token))
+}
+
+// RotateCalendarFeed は購読フィードの URL を発行し直す（URL が漏れた場合に古い URL を無効にする）
+func RotateCalendarFeed(c *gin.Context) {
+	userID, err := servise.ExtractSessionUserID(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return
+	}
+
+	token, err := rotateCalendarFeedToken(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newCalendarFeedResponse(c, token))
+}
+
+// GetCalendarFeed は購読フィードの .ics を返す。URL のトークンが認証を兼ねる
+// カレンダーアプリは定期的に取得するため、If-None-Match が ETag と一致すれば 304 を返す
+func GetCalendarFeed(c *gin.Context) {
+	feed, err := buildCalendarFeed(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("ETag", feed.ETag)
+	c.Header("Last-Modified", feed.LastModified.UTC().Format(http.TimeFormat))
+	c.Header("Cache-Control", "private, no-cache")
+	if etagMatches(c.GetHeader("If-None-Match"), feed.ETag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Header("Content-Disposition", `inline; filename="adjusche.ics"`)
+	c.Data(http.StatusOK, icsContentType, feed.ICS)
+}
+
+// etagMatches は If-None-Match のいずれかの値が etag と一致するかを返す（弱い比較）
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, v := range strings.Split(ifNoneMatch, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || strings.TrimPrefix(v, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func newCalendarFeedResponse(c *gin.Context, token string) CalendarFeedResponse {
+	path := c.Request.Host + "/feeds/" + token + "/calendar.ics"
+	scheme := "https://"
+	if c.Request.TLS == nil && c.GetHeader("X-Forwarded-Proto") != "https" {
+		scheme = "http://"
+	}
+	return CalendarFeedResponse{
+		FeedURL:   scheme + path,
+		WebcalURL: "webcal://" + path,
+	}
+}
@@ -0,0 +1,189 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/middleware"
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var testFeedModified = time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+// fakeCalendarFeeds はユーザーごとに 1 つのトークンを持つフィードのトークンの保存先
+type fakeCalendarFeeds struct {
+	tokens map[string]string // userID → token
+	issued int
+}
+
+// useFakeCalendarFeeds はフィードの発行と作成を DB を使わない実装に差し替える
+func useFakeCalendarFeeds(t *testing.T) *fakeCalendarFeeds {
+	t.Helper()
+	f := &fakeCalendarFeeds{tokens: map[string]string{}}
+	origGet, origRotate, origBuild := getCalendarFeedToken, rotateCalendarFeedToken, buildCalendarFeed
+	t.Cleanup(func() {
+		getCalendarFeedToken, rotateCalendarFeedToken, buildCalendarFeed = origGet, origRotate, origBuild
+	})
+
+	getCalendarFeedToken = func(ctx context.Context, userID string) (string, error) {
+		if token, ok := f.tokens[userID]; ok {
+			return token, nil
+		}
+		return rotateCalendarFeedToken(ctx, userID)
+	}
+	rotateCalendarFeedToken = func(_ context.Context, userID string) (string, error) {
+		f.issued++
+		f.tokens[userID] = fmt.Sprintf("token-%d", f.issued)
+		return f.tokens[userID], nil
+	}
+	buildCalendarFeed = func(_ context.Context, token string) (application.CalendarFeed, error) {
+		for userID, tok := range f.tokens {
+			if tok == token {
+				return application.CalendarFeed{
+					ICS:          []byte("BEGIN:VCALENDAR\r\nX-WR-CALNAME:" + userID + "\r\nEND:VCALENDAR\r\n"),
+					ETag:         `"etag-` + userID + `"`,
+					LastModified: testFeedModified,
+				}, nil
+			}
+		}
+		return application.CalendarFeed{}, repository.ErrCalendarFeedNotFound
+	}
+	return f
+}
+
+func newTestCalendarFeedRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.GET("/me/calendar-feed", GetCalendarFeedURL)
+	r.POST("/me/calendar-feed/rotate", RotateCalendarFeed)
+	r.GET("/feeds/:token/calendar.ics", GetCalendarFeed)
+	return r
+}
+
+func serveFeedRequest(r *gin.Engine, method, path string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "http://example.com"+path, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func decodeFeedURL(t *testing.T, w *httptest.ResponseRecorder) CalendarFeedResponse {
+	t.Helper()
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	var res CalendarFeedResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return res
+}
+
+func TestRotateCalendarFeedInvalidatesOldURL(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+	useFakeCalendarFeeds(t)
+	r := newTestCalendarFeedRouter()
+
+	session, _, err := servise.IssueSessionToken("u1", time.Now())
+	if err != nil {
+		t.Fatalf("IssueSessionToken: %v", err)
+	}
+	auth := map[string]string{servise.SessionHeader: session}
+
+	first := decodeFeedURL(t, serveFeedRequest(r, http.MethodGet, "/me/calendar-feed", auth))
+	if first.FeedURL != "http://example.com/feeds/token-1/calendar.ics" || first.WebcalURL != "webcal://example.com/feeds/token-1/calendar.ics" {
+		t.Errorf("feed urls = %+v", first)
+	}
+	if again := decodeFeedURL(t, serveFeedRequest(r, http.MethodGet, "/me/calendar-feed", auth)); again != first {
+		t.Errorf("getting the feed url again should not rotate it: got %+v, want %+v", again, first)
+	}
+
+	rotated := decodeFeedURL(t, serveFeedRequest(r, http.MethodPost, "/me/calendar-feed/rotate", auth))
+	if rotated.FeedURL == first.FeedURL || !strings.Contains(rotated.FeedURL, "/feeds/token-2/") {
+		t.Errorf("rotated feed url = %q, want a new token", rotated.FeedURL)
+	}
+
+	if w := serveFeedRequest(r, http.MethodGet, "/feeds/token-1/calendar.ics", nil); w.Code != http.StatusNotFound {
+		t.Errorf("old feed status = %d, want 404", w.Code)
+	}
+	if w := serveFeedRequest(r, http.MethodGet, "/feeds/token-2/calendar.ics", nil); w.Code != http.StatusOK {
+		t.Errorf("new feed status = %d, want 200", w.Code)
+	}
+}
+
+func TestRotateCalendarFeedRequiresSession(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+	f := useFakeCalendarFeeds(t)
+	r := newTestCalendarFeedRouter()
+
+	for _, header := range []map[string]string{nil, {servise.SessionHeader: "forged.token"}} {
+		if w := serveFeedRequest(r, http.MethodPost, "/me/calendar-feed/rotate", header); w.Code != http.StatusUnauthorized {
+			t.Errorf("header %v: status = %d, want 401", header, w.Code)
+		}
+	}
+	if f.issued != 0 {
+		t.Errorf("issued %d tokens without a session", f.issued)
+	}
+}
+
+func TestGetCalendarFeedETag(t *testing.T) {
+	f := useFakeCalendarFeeds(t)
+	f.tokens["u1"] = "token-u1"
+	r := newTestCalendarFeedRouter()
+	const path = "/feeds/token-u1/calendar.ics"
+
+	w := serveFeedRequest(r, http.MethodGet, path, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if got := w.Header().Get("ETag"); got != `"etag-u1"` {
+		t.Errorf("ETag = %q, want %q", got, `"etag-u1"`)
+	}
+	if got := w.Header().Get("Last-Modified"); got != "Sun, 18 Oct 2026 09:00:00 GMT" {
+		t.Errorf("Last-Modified = %q", got)
+	}
+	if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/calendar") {
+		t.Errorf("Content-Type = %q, want text/calendar", got)
+	}
+	if !strings.Contains(w.Body.String(), "X-WR-CALNAME:u1") {
+		t.Errorf("body = %q", w.Body.String())
+	}
+
+	tests := []struct {
+		ifNoneMatch string
+		want        int
+	}{
+		{`"etag-u1"`, http.StatusNotModified},
+		{`W/"etag-u1"`, http.StatusNotModified},
+		{`"stale", "etag-u1"`, http.StatusNotModified},
+		{`*`, http.StatusNotModified},
+		{`"stale"`, http.StatusOK},
+		{`etag-u1`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := serveFeedRequest(r, http.MethodGet, path, map[string]string{"If-None-Match": tt.ifNoneMatch})
+		if w.Code != tt.want {
+			t.Errorf("If-None-Match %s: status = %d, want %d", tt.ifNoneMatch, w.Code, tt.want)
+			continue
+		}
+		if w.Header().Get("ETag") != `"etag-u1"` {
+			t.Errorf("If-None-Match %s: ETag = %q", tt.ifNoneMatch, w.Header().Get("ETag"))
+		}
+		if tt.want == http.StatusNotModified && w.Body.Len() != 0 {
+			t.Errorf("If-None-Match %s: 304 should have no body, got %q", tt.ifNoneMatch, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/servise"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DeclineEventRequest struct {
+	Reason string `json:"reason"` // 任意
+}
+
+// DeclineEvent は招待を辞退する。辞退した参加者は候補日時の計算から外れる
+// ログインユーザーはセッション、ゲストは X-Guest-Token で本人を確かめる
+func DeclineEvent(c *gin.Context) {
+	in, ok := bindDeclineEventRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := application.DeclineEvent(c.Request.Context(), in); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// WithdrawFromEvent は受諾済みの参加を取り消す。提出済みの空き時間は削除される
+// 本人の確かめ方は DeclineEvent と同じ
+func WithdrawFromEvent(c *gin.Context) {
+	in, ok := bindDeclineEventRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := application.WithdrawFromEvent(c.Request.Context(), in); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func bindDeclineEventRequest(c *gin.Context) (application.DeclineEventInput, bool) {
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errInvalidEventID.Wrap(err))
+		return application.DeclineEventInput{}, false
+	}
+
+	in := application.DeclineEventInput{EventID: eventID}
+	if token := c.GetHeader(servise.GuestTokenHeader); token != "" {
+		in.GuestToken = token
+	} else {
+		in.UserID, err = servise.ExtractSessionUserID(c)
+		if err != nil {
+			c.Error(errLoginRequired.Wrap(err))
+			return application.DeclineEventInput{}, false
+		}
+	}
+
+	// 理由は任意のため、本文のないリクエストも受け付ける
+	var req DeclineEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.Error(bindJSONError(err))
+		return application.DeclineEventInput{}, false
+	}
+	in.Reason = req.Reason
+	return in, true
+}
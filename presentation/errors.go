@@ -0,0 +1,58 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/domain"
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// ハンドラ共通のエラー。c.Error で登録すると middleware.ErrorHandler がレスポンスに変換する
+var (
+	errInvalidRequestBody = domain.BadRequest("invalid_request_body", "無効なリクエストボディです")
+	errAuthTokenRequired  = domain.BadRequest("auth_token_required", "認証トークンが必要です")
+	errInvalidEventID     = domain.BadRequest("invalid_event_id", "eventId は数値で指定してください")
+	errLoginRequired      = domain.Unauthorized("login_required", "ログインが必要です")
+	errGuestTokenRequired = domain.Unauthorized("guest_token_required", "ゲストのトークンが必要です")
+)
+
+// bindJSONError は ShouldBindJSON のエラーを API のエラーに変換する
+// 値の型が違う項目（数値に文字列を指定したなど）は 422 で項目ごとに返し、それ以外は 400 にする
+func bindJSONError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		want := jsonTypeName(typeErr.Type)
+		return domain.Validation([]domain.FieldError{{
+			Field:   typeErr.Field,
+			Code:    application.FieldErrorInvalidType,
+			Message: "値の型が正しくありません（" + want + " で指定してください）",
+			Params:  []any{want},
+		}}).Wrap(err)
+	}
+	return errInvalidRequestBody.Wrap(err)
+}
+
+// jsonTypeName は Go の型に対応する JSON の型名を返す
+func jsonTypeName(t reflect.Type) string {
+	if t == nil {
+		return "value"
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -0,0 +1,68 @@
+package presentation
+
+import (
+	"adjuSche-back-end/middleware"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func postCreateEvent(t *testing.T, body, lang string) (int, middleware.ErrorResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.POST("/events", CreateEvent)
+
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", lang)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var res middleware.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("failed to decode response %q: %v", w.Body.String(), err)
+	}
+	return w.Code, res
+}
+
+func TestBindJSONTypeMismatchIsFieldError(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		lang    string
+		field   string
+		message string
+	}{
+		{"nested number", `{"title":"t","conditions":{"durationMin":"60"}}`, "ja", "conditions.durationMin", "値の型が正しくありません（number で指定してください）"},
+		{"top-level number", `{"participantCount":"3"}`, "en", "participantCount", "Invalid type (expected a number)"},
+		{"string", `{"title":123}`, "en", "title", "Invalid type (expected a string)"},
+	}
+	for _, tt := range tests {
+		status, res := postCreateEvent(t, tt.body, tt.lang)
+		if status != http.StatusUnprocessableEntity || res.Code != "validation_failed" {
+			t.Errorf("%s: got %d %s, want 422 validation_failed", tt.name, status, res.Code)
+			continue
+		}
+		if len(res.Fields) != 1 {
+			t.Errorf("%s: got fields %+v, want one", tt.name, res.Fields)
+			continue
+		}
+		f := res.Fields[0]
+		if f.Field != tt.field || f.Code != "invalid_type" || f.Message != tt.message {
+			t.Errorf("%s: got %+v, want %s invalid_type %q", tt.name, f, tt.field, tt.message)
+		}
+	}
+}
+
+func TestBindJSONMalformedBodyIsBadRequest(t *testing.T) {
+	status, res := postCreateEvent(t, `{"title":`, "ja")
+	if status != http.StatusBadRequest || res.Code != "invalid_request_body" || len(res.Fields) != 0 {
+		t.Errorf("got %d %+v, want 400 invalid_request_body", status, res)
+	}
+}
@@ -0,0 +1,102 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// eventStreamHeartbeat はプロキシに無通信の接続を切られないよう、コメント行を送る間隔
+const eventStreamHeartbeat = 25 * time.Second
+
+type EventProgressResponse struct {
+	EventID       string         `json:"eventId"`
+	VotedCount    int            `json:"votedCount"`
+	DeclinedCount int            `json:"declinedCount"`
+	PossibleDate  []possibleDate `json:"possibleDate"`
+}
+
+// GetEventStream は投票の途中経過を Server-Sent Events で配信する
+// 接続直後と、参加者の空き時間が保存されるたびに "progress" イベントとして投票済みの人数と候補日時を送る
+// 招待 URL と同じくイベントIDだけで購読できる
+func GetEventStream(c *gin.Context) {
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errInvalidEventID.Wrap(err))
+		return
+	}
+	ctx := c.Request.Context()
+
+	// 初期状態を読む前に購読し、その間に保存された更新を取りこぼさないようにする
+	stream, err := application.OpenEventProgressStream(eventID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer stream.Close()
+
+	progress, err := stream.Progress(ctx)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // nginx などのバッファリングを無効にする
+	c.Status(http.StatusOK)
+
+	last := writeEventProgress(c, progress, "")
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := c.Writer.WriteString(": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-stream.Updates:
+			progress, err := stream.Progress(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				log.Printf("投票の途中経過の取得に失敗しました: eventID=%d: %v", eventID, err)
+				continue
+			}
+			last = writeEventProgress(c, progress, last)
+		}
+	}
+}
+
+// writeEventProgress は途中経過を "progress" イベントとして送り、送った内容を返す
+// 前回送った内容と同じ場合は送らない
+func writeEventProgress(c *gin.Context, progress application.EventProgress, last string) string {
+	body, err := json.Marshal(EventProgressResponse{
+		EventID:       strconv.FormatInt(progress.EventID, 10),
+		VotedCount:    progress.VotedCount,
+		DeclinedCount: progress.DeclinedCount,
+		PossibleDate:  newPossibleDates(progress.Slots),
+	})
+	if err != nil {
+		log.Printf("投票の途中経過の JSON 変換に失敗しました: %v", err)
+		return last
+	}
+	data := string(body)
+	if data == last {
+		return last
+	}
+	c.SSEvent("progress", data)
+	c.Writer.Flush()
+	return data
+}
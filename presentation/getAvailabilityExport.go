@@ -0,0 +1,46 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/servise"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAvailabilityCSV は参加者 × 候補日時の参加可否（○/×）の表を CSV で返す（主催者のみ）
+// Excel でそのまま開けるように UTF-8 BOM を付ける
+func GetAvailabilityCSV(c *gin.Context) {
+	userID, eventID, ok := bindHostRequest(c)
+	if !ok {
+		return
+	}
+
+	data, err := application.ExportAvailabilityCSV(c.Request.Context(), eventID, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	writeAttachment(c, fmt.Sprintf("event-%d-availability.csv", eventID), servise.CSVContentType, data)
+}
+
+// GetAvailabilityXLSX は参加者 × 候補日時の参加可否（○/×）の表を .xlsx で返す（主催者のみ）
+func GetAvailabilityXLSX(c *gin.Context) {
+	userID, eventID, ok := bindHostRequest(c)
+	if !ok {
+		return
+	}
+
+	data, err := application.ExportAvailabilityXLSX(c.Request.Context(), eventID, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	writeAttachment(c, fmt.Sprintf("event-%d-availability.xlsx", eventID), servise.XLSXContentType, data)
+}
+
+func writeAttachment(c *gin.Context, filename, contentType string, data []byte) {
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, contentType, data)
+}
@@ -0,0 +1,48 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const icsContentType = "text/calendar; charset=utf-8"
+
+// GetEventICS は確定したイベントを .ics ファイルとして返す
+// 招待 URL と同じくイベントIDだけで取得でき、Google カレンダーを使っていない参加者も取り込める
+func GetEventICS(c *gin.Context) {
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errInvalidEventID.Wrap(err))
+		return
+	}
+
+	ics, err := application.ExportEventICS(c.Request.Context(), eventID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	writeICS(c, fmt.Sprintf("event-%d.ics", eventID), ics)
+}
+
+// GetEventCandidatesICS は現在の候補日時を仮予定として並べた .ics ファイルを返す
+func GetEventCandidatesICS(c *gin.Context) {
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errInvalidEventID.Wrap(err))
+		return
+	}
+
+	ics, err := application.ExportCandidatesICS(c.Request.Context(), eventID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	writeICS(c, fmt.Sprintf("event-%d-candidates.ics", eventID), ics)
+}
+
+func writeICS(c *gin.Context, filename string, ics []byte) {
+	writeAttachment(c, filename, icsContentType, ics)
+}
@@ -0,0 +1,106 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/domain"
+	"adjuSche-back-end/servise"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listMyEvents はマイイベント一覧の取得（テストで DB を使わない実装に差し替える）
+var listMyEvents = application.ListMyEvents
+
+type myEvent struct {
+	EventID          string `json:"eventId"`
+	Title            string `json:"title"`
+	Role             string `json:"role"`
+	Status           string `json:"status"`
+	VotedCount       int    `json:"votedCount"`
+	DeclinedCount    int    `json:"declinedCount"`
+	ParticipantCount int64  `json:"participantCount"`
+	DecidedStart     string `json:"decidedStart,omitempty"`
+	DecidedEnd       string `json:"decidedEnd,omitempty"`
+	CreatedAt        string `json:"createdAt"`
+}
+
+type GetMyEventsResponse struct {
+	Events []myEvent `json:"events"`
+	Total  int64     `json:"total"`
+	Limit  int       `json:"limit"`
+	Offset int       `json:"offset"`
+}
+
+// GetMyEvents はセッションのユーザーが主催・参加しているイベントの一覧を返す
+// クエリ: status=draft|open|closed|canceled, limit, offset
+func GetMyEvents(c *gin.Context) {
+	userID, err := servise.ExtractSessionUserID(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return
+	}
+
+	in := application.ListMyEventsInput{UserID: userID, Limit: application.DefaultMyEventsLimit}
+	if s := c.Query("status"); s != "" {
+		status, ok := application.ParseEventStatus(s)
+		if !ok {
+			c.Error(domain.BadRequest("invalid_status", "status は draft, open, closed, canceled のいずれかで指定してください"))
+			return
+		}
+		in.Status = &status
+	}
+	if s := c.Query("limit"); s != "" {
+		in.Limit, err = strconv.Atoi(s)
+		if err != nil || in.Limit <= 0 {
+			c.Error(domain.BadRequest("invalid_limit", "limit は正の整数で指定してください"))
+			return
+		}
+		if in.Limit > application.MaxMyEventsLimit {
+			in.Limit = application.MaxMyEventsLimit
+		}
+	}
+	if s := c.Query("offset"); s != "" {
+		in.Offset, err = strconv.Atoi(s)
+		if err != nil || in.Offset < 0 {
+			c.Error(domain.BadRequest("invalid_offset", "offset は0以上の整数で指定してください"))
+			return
+		}
+	}
+
+	items, total, err := listMyEvents(c.Request.Context(), in)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	res := GetMyEventsResponse{
+		Events: make([]myEvent, 0, len(items)),
+		Total:  total,
+		Limit:  in.Limit,
+		Offset: in.Offset,
+	}
+	for _, it := range items {
+		ev := myEvent{
+			EventID:          strconv.FormatInt(it.EventID, 10),
+			Title:            it.Title,
+			Role:             it.Role,
+			Status:           application.EventStatusName(it.Status),
+			VotedCount:       it.VotedCount,
+			DeclinedCount:    it.DeclinedCount,
+			ParticipantCount: it.ParticipantCount,
+			CreatedAt:        it.CreatedAt.Format(time.RFC3339),
+		}
+		if it.DecidedStart != nil {
+			ev.DecidedStart = it.DecidedStart.Format(time.RFC3339)
+		}
+		if it.DecidedEnd != nil {
+			ev.DecidedEnd = it.DecidedEnd.Format(time.RFC3339)
+		}
+		res.Events = append(res.Events, ev)
+	}
+
+	c.JSON(http.StatusOK, res)
+}
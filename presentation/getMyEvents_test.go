@@ -0,0 +1,163 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/middleware"
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// useFakeMyEvents はマイイベント一覧の取得を、入力を記録して items と total を返す実装に差し替える
+func useFakeMyEvents(t *testing.T, items []application.MyEventSummary, total int64) *[]application.ListMyEventsInput {
+	t.Helper()
+	var inputs []application.ListMyEventsInput
+	orig := listMyEvents
+	t.Cleanup(func() { listMyEvents = orig })
+	listMyEvents = func(_ context.Context, in application.ListMyEventsInput) ([]application.MyEventSummary, int64, error) {
+		inputs = append(inputs, in)
+		return items, total, nil
+	}
+	return &inputs
+}
+
+func getMyEvents(t *testing.T, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	session, _, err := servise.IssueSessionToken("session-user", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.GET("/me/events", GetMyEvents)
+
+	req := httptest.NewRequest(http.MethodGet, "/me/events"+query, nil)
+	req.Header.Set(servise.SessionHeader, session)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetMyEventsPassesFilterAndPaging(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus *int64
+		wantLimit  int
+		wantOffset int
+	}{
+		{"defaults", "", nil, application.DefaultMyEventsLimit, 0},
+		{"status filter", "?status=closed", ptrInt64(repository.EventStatusClosed), application.DefaultMyEventsLimit, 0},
+		{"next page", "?limit=10&offset=30", nil, 10, 30},
+		{"limit above max", "?limit=1000", nil, application.MaxMyEventsLimit, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputs := useFakeMyEvents(t, nil, 45)
+
+			w := getMyEvents(t, tt.query)
+			if w.Code != http.StatusOK {
+				t.Fatalf("got %d, want 200: %s", w.Code, w.Body.String())
+			}
+			if len(*inputs) != 1 {
+				t.Fatalf("ListMyEvents called %d times, want 1", len(*inputs))
+			}
+			in := (*inputs)[0]
+			if in.UserID != "session-user" || in.Limit != tt.wantLimit || in.Offset != tt.wantOffset {
+				t.Errorf("input = %+v, want limit %d offset %d for session-user", in, tt.wantLimit, tt.wantOffset)
+			}
+			if (in.Status == nil) != (tt.wantStatus == nil) || (in.Status != nil && *in.Status != *tt.wantStatus) {
+				t.Errorf("status = %v, want %v", in.Status, tt.wantStatus)
+			}
+
+			// 次のページを求められるよう、適用した limit・offset と総件数を返す
+			var res GetMyEventsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+				t.Fatal(err)
+			}
+			if res.Total != 45 || res.Limit != tt.wantLimit || res.Offset != tt.wantOffset || res.Events == nil {
+				t.Errorf("response = %+v", res)
+			}
+		})
+	}
+}
+
+func TestGetMyEventsRejectsInvalidQuery(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+	inputs := useFakeMyEvents(t, nil, 0)
+
+	tests := []struct {
+		query string
+		code  string
+	}{
+		{"?status=done", "invalid_status"},
+		{"?limit=0", "invalid_limit"},
+		{"?limit=ten", "invalid_limit"},
+		{"?offset=-1", "invalid_offset"},
+	}
+	for _, tt := range tests {
+		w := getMyEvents(t, tt.query)
+		var res middleware.ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+			t.Fatalf("%s: failed to decode response %q: %v", tt.query, w.Body.String(), err)
+		}
+		if w.Code != http.StatusBadRequest || res.Code != tt.code {
+			t.Errorf("%s: got %d %s, want 400 %s", tt.query, w.Code, res.Code, tt.code)
+		}
+	}
+	if len(*inputs) != 0 {
+		t.Errorf("ListMyEvents called for rejected requests: %+v", *inputs)
+	}
+}
+
+func TestGetMyEventsResponse(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+	start := time.Date(2026, 11, 2, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	useFakeMyEvents(t, []application.MyEventSummary{{
+		EventID:          42,
+		Title:            "定例会",
+		Role:             application.EventRoleHost,
+		Status:           repository.EventStatusClosed,
+		VotedCount:       3,
+		DeclinedCount:    1,
+		ParticipantCount: 5,
+		DecidedStart:     &start,
+		DecidedEnd:       &end,
+		CreatedAt:        start.Add(-48 * time.Hour),
+	}}, 1)
+
+	w := getMyEvents(t, "")
+	var res GetMyEventsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	want := myEvent{
+		EventID:          "42",
+		Title:            "定例会",
+		Role:             "host",
+		Status:           "closed",
+		VotedCount:       3,
+		DeclinedCount:    1,
+		ParticipantCount: 5,
+		DecidedStart:     "2026-11-02T10:00:00Z",
+		DecidedEnd:       "2026-11-02T11:00:00Z",
+		CreatedAt:        "2026-10-31T10:00:00Z",
+	}
+	if len(res.Events) != 1 || res.Events[0] != want {
+		t.Errorf("events = %+v, want %+v", res.Events, want)
+	}
+}
+
+func ptrInt64(v int64) *int64 { return &v }
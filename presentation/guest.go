@@ -0,0 +1,134 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/servise"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JoinAsGuestRequest struct {
+	DisplayName string `json:"displayName"`
+	Email       string `json:"email"` // 任意
+}
+
+type GuestResponse struct {
+	GuestID        string                 `json:"guestId"`
+	DisplayName    string                 `json:"displayName"`
+	Email          string                 `json:"email,omitempty"`
+	CreatedAt      string                 `json:"createdAt"`
+	Availabilities []availabilityInterval `json:"availabilities"`
+}
+
+// JoinAsGuestResponse は参加したゲストと編集用トークン
+// トークンは再発行できないため、クライアントで保存して X-Guest-Token ヘッダで送る
+type JoinAsGuestResponse struct {
+	GuestResponse
+	EditToken string `json:"editToken"`
+}
+
+// ManualAvailabilityRequest は手入力の空き時間（ゲスト・ログインユーザー共通）
+type ManualAvailabilityRequest struct {
+	Availabilities []availabilityInterval `json:"availabilities"`
+}
+
+// JoinAsGuest は招待 URL から、アカウントを作らずに表示名だけでイベントに参加する
+func JoinAsGuest(c *gin.Context) {
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errInvalidEventID.Wrap(err))
+		return
+	}
+
+	var req JoinAsGuestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindJSONError(err))
+		return
+	}
+
+	session, err := application.JoinEventAsGuest(c.Request.Context(), application.JoinAsGuestInput{
+		EventID:     eventID,
+		DisplayName: req.DisplayName,
+		Email:       req.Email,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusCreated, JoinAsGuestResponse{
+		GuestResponse: newGuestResponse(application.GuestDetail{Guest: session.Guest}),
+		EditToken:     session.Token,
+	})
+}
+
+// GetGuest は X-Guest-Token のゲストと、登録済みの空き時間を返す
+func GetGuest(c *gin.Context) {
+	eventID, token, ok := bindGuestRequest(c)
+	if !ok {
+		return
+	}
+
+	detail, err := application.GetGuest(c.Request.Context(), eventID, token)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newGuestResponse(*detail))
+}
+
+// SubmitGuestAvailability はゲストの空き時間を、リクエストの内容で置き換える
+func SubmitGuestAvailability(c *gin.Context) {
+	eventID, token, ok := bindGuestRequest(c)
+	if !ok {
+		return
+	}
+
+	var req ManualAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindJSONError(err))
+		return
+	}
+
+	free, err := application.SubmitGuestAvailability(c.Request.Context(), eventID, token, req.intervals())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newImportAvailabilityResponse(free))
+}
+
+func (r ManualAvailabilityRequest) intervals() []application.ManualInterval {
+	intervals := make([]application.ManualInterval, 0, len(r.Availabilities))
+	for _, av := range r.Availabilities {
+		intervals = append(intervals, application.ManualInterval{Start: av.Start, End: av.End})
+	}
+	return intervals
+}
+
+func bindGuestRequest(c *gin.Context) (int64, string, bool) {
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errInvalidEventID.Wrap(err))
+		return 0, "", false
+	}
+
+	token := c.GetHeader(servise.GuestTokenHeader)
+	if token == "" {
+		c.Error(errGuestTokenRequired)
+		return 0, "", false
+	}
+	return eventID, token, true
+}
+
+func newGuestResponse(detail application.GuestDetail) GuestResponse {
+	return GuestResponse{
+		GuestID:        detail.Guest.UserID,
+		DisplayName:    detail.Guest.DisplayName,
+		Email:          detail.Guest.Email.String,
+		CreatedAt:      detail.Guest.CreatedAt.Format(time.RFC3339),
+		Availabilities: newAvailabilityIntervals(detail.Availabilities),
+	}
+}
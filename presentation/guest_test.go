@@ -0,0 +1,63 @@
+package presentation
+
+import (
+	"adjuSche-back-end/middleware"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func serveGuestRequest(method, path, body string, header map[string]string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.POST("/events/:id/guests", JoinAsGuest)
+	r.GET("/events/:id/guests/me", GetGuest)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestJoinAsGuestValidationIs422(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		field string
+		code  string
+	}{
+		{"missing display name", `{"displayName":""}`, "displayName", "required"},
+		{"display name too long", `{"displayName":"` + strings.Repeat("a", 51) + `"}`, "displayName", "too_long"},
+		{"invalid email", `{"displayName":"佐藤","email":"sato@"}`, "email", "invalid_format"},
+	}
+	for _, tt := range tests {
+		w := serveGuestRequest(http.MethodPost, "/events/42/guests", tt.body, nil)
+		var res middleware.ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+			t.Fatalf("%s: failed to decode response %q: %v", tt.name, w.Body.String(), err)
+		}
+		if w.Code != http.StatusUnprocessableEntity || res.Code != "validation_failed" {
+			t.Errorf("%s: got %d %s, want 422 validation_failed", tt.name, w.Code, res.Code)
+			continue
+		}
+		if len(res.Fields) != 1 || res.Fields[0].Field != tt.field || res.Fields[0].Code != tt.code {
+			t.Errorf("%s: got fields %+v, want %s %s", tt.name, res.Fields, tt.field, tt.code)
+		}
+	}
+}
+
+func TestGetGuestRequiresGuestToken(t *testing.T) {
+	w := serveGuestRequest(http.MethodGet, "/events/42/guests/me", "", nil)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("got %d, want 401: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,247 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/domain"
+	"adjuSche-back-end/i18n"
+	"adjuSche-back-end/servise"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/line/line-bot-sdk-go/v7/linebot"
+)
+
+// LineHandler は起動時に生成した LINE ボットクライアントを使い回してリクエストを処理する
+type LineHandler struct {
+	bot *linebot.Client
+	// langs は LINE userId ごとのプロフィールの言語のキャッシュ
+	langs sync.Map
+}
+
+var errInvalidLineSignature = domain.BadRequest("invalid_signature", "署名が不正です")
+
+func NewLineHandler(bot *linebot.Client) *LineHandler {
+	return &LineHandler{bot: bot}
+}
+
+// Webhook は署名を検証したうえで即座に 200 を返し、イベントは非同期で処理する
+// LINE の Webhook はタイムアウトが短いため、返信処理を待たずにレスポンスを返す
+func (h *LineHandler) Webhook(c *gin.Context) {
+	events, err := h.bot.ParseRequest(c.Request)
+	if err != nil {
+		if errors.Is(err, linebot.ErrInvalidSignature) {
+			log.Printf("LINE Webhook の署名が不正です: %v", err)
+			c.Error(errInvalidLineSignature.Wrap(err))
+			return
+		}
+		log.Printf("LINE Webhook のリクエスト解析に失敗しました: %v", err)
+		c.Error(errInvalidRequestBody.Wrap(err))
+		return
+	}
+
+	go h.handleEvents(events)
+
+	c.Status(http.StatusOK)
+}
+
+func (h *LineHandler) handleEvents(events []*linebot.Event) {
+	for _, event := range events {
+		lang := h.userLang(event)
+		switch event.Type {
+		case linebot.EventTypeMessage:
+			switch message := event.Message.(type) {
+			case *linebot.TextMessage:
+				switch {
+				case i18n.Matches(accountLinkKeyword, message.Text):
+					h.startAccountLink(event, lang)
+					continue
+				case i18n.Matches(myEventsKeyword, message.Text):
+					h.replyMyEvents(event, lang)
+					continue
+				}
+				h.replyText(event.ReplyToken, getResMessage(lang, message.Text))
+			}
+		case linebot.EventTypePostback:
+			h.handlePostback(event, lang)
+		case linebot.EventTypeAccountLink:
+			h.completeAccountLink(event, lang)
+		}
+	}
+}
+
+// userLang は送信者の LINE のプロフィールの言語を返す。取得できない場合は既定の言語を使う
+func (h *LineHandler) userLang(event *linebot.Event) i18n.Lang {
+	if event.Source == nil || event.Source.UserID == "" {
+		return i18n.Default
+	}
+	lineUserID := event.Source.UserID
+	if lang, ok := h.langs.Load(lineUserID); ok {
+		return lang.(i18n.Lang)
+	}
+
+	profile, err := h.bot.GetProfile(lineUserID).Do()
+	if err != nil {
+		log.Printf("LINE プロフィールの取得に失敗しました: %v", err)
+		return i18n.Default
+	}
+	lang := i18n.FromLineLanguage(profile.Language)
+	h.langs.Store(lineUserID, lang)
+	return lang
+}
+
+// handlePostback はリッチメニューのポストバックを対応する処理に振り分ける
+func (h *LineHandler) handlePostback(event *linebot.Event, lang i18n.Lang) {
+	if event.Postback == nil {
+		return
+	}
+	switch servise.ParseRichMenuPostbackData(event.Postback.Data) {
+	case servise.RichMenuActionNewEvent:
+		h.replyText(event.ReplyToken, getFormURL())
+	case servise.RichMenuActionMyEvents:
+		h.replyMyEvents(event, lang)
+	case servise.RichMenuActionHelp:
+		h.replyText(event.ReplyToken, getHelpMessage(lang))
+	default:
+		log.Printf("不明なポストバックです: %s", event.Postback.Data)
+	}
+}
+
+// startAccountLink は linkToken を発行し、フロントエンドの連携ページの URL を返信する
+func (h *LineHandler) startAccountLink(event *linebot.Event, lang i18n.Lang) {
+	if event.Source == nil || event.Source.UserID == "" {
+		h.replyText(event.ReplyToken, i18n.T(lang, "line.account_link.user_only"))
+		return
+	}
+
+	res, err := h.bot.IssueLinkToken(event.Source.UserID).Do()
+	if err != nil {
+		log.Printf("linkToken の発行に失敗しました: %v", err)
+		h.replyText(event.ReplyToken, i18n.T(lang, "line.account_link.start_error"))
+		return
+	}
+
+	h.replyText(event.ReplyToken, i18n.T(lang, "line.account_link.prompt", getAccountLinkURL(res.LinkToken)))
+}
+
+// completeAccountLink は accountLink イベントを受け取り、LINE userId とアプリのユーザーを紐付ける
+func (h *LineHandler) completeAccountLink(event *linebot.Event, lang i18n.Lang) {
+	if event.AccountLink == nil || event.Source == nil {
+		return
+	}
+	if event.AccountLink.Result != linebot.AccountLinkResultOK {
+		log.Printf("アカウント連携に失敗しました: lineUserID=%s", event.Source.UserID)
+		h.replyText(event.ReplyToken, i18n.T(lang, "line.account_link.failed"))
+		return
+	}
+
+	userID, err := application.CompleteLineAccountLink(context.Background(), event.Source.UserID, event.AccountLink.Nonce)
+	if err != nil {
+		log.Printf("アカウント連携の保存に失敗しました: %v", err)
+		h.replyText(event.ReplyToken, i18n.T(lang, "line.account_link.failed"))
+		return
+	}
+	log.Printf("アカウント連携が完了しました: lineUserID=%s, userID=%s", event.Source.UserID, userID)
+	h.replyText(event.ReplyToken, i18n.T(lang, "line.account_link.done"))
+}
+
+// replyMyEvents は連携済みユーザーが主催・参加しているイベントの一覧を返信する
+func (h *LineHandler) replyMyEvents(event *linebot.Event, lang i18n.Lang) {
+	if event.Source == nil || event.Source.UserID == "" {
+		return
+	}
+	ctx := context.Background()
+
+	userID, err := getUserIDByLineUserID(ctx, event.Source.UserID)
+	if err != nil {
+		if errors.Is(err, application.ErrLineAccountNotLinked) {
+			h.replyText(event.ReplyToken, i18n.T(lang, "line.my_events.not_linked"))
+			return
+		}
+		log.Printf("LINE ユーザーの取得に失敗しました: %v", err)
+		h.replyText(event.ReplyToken, i18n.T(lang, "line.my_events.error"))
+		return
+	}
+
+	items, total, err := listMyEvents(ctx, application.ListMyEventsInput{UserID: userID, Limit: lineMyEventsLimit})
+	if err != nil {
+		log.Printf("マイイベント一覧の取得に失敗しました: %v", err)
+		h.replyText(event.ReplyToken, i18n.T(lang, "line.my_events.error"))
+		return
+	}
+
+	h.replyText(event.ReplyToken, formatMyEvents(lang, items, total))
+}
+
+func (h *LineHandler) replyText(replyToken, text string) {
+	_, err := h.bot.ReplyMessage(replyToken, linebot.NewTextMessage(text)).Do()
+	if err != nil {
+		log.Printf("LINE への返信に失敗しました: %v", err)
+	}
+}
+
+// LINE のキーワードのメッセージキー。どの言語のキーワードでも受け付ける
+const (
+	// scheduleKeyword は日程調整フォームの URL を返すメッセージ
+	scheduleKeyword = "line.keyword.schedule"
+	// accountLinkKeyword はアカウント連携を開始するメッセージ
+	accountLinkKeyword = "line.keyword.account_link"
+	// myEventsKeyword はマイイベント一覧を表示するメッセージ
+	myEventsKeyword = "line.keyword.my_events"
+)
+
+// lineMyEventsLimit は LINE で返信するマイイベントの最大件数
+const lineMyEventsLimit = 10
+
+// getUserIDByLineUserID は LINE userId の連携先のユーザーの取得（テストで DB を使わない実装に差し替える）
+var getUserIDByLineUserID = application.GetUserIDByLineUserID
+
+// formatMyEvents はマイイベント一覧を LINE のテキストメッセージに整形する
+func formatMyEvents(lang i18n.Lang, items []application.MyEventSummary, total int64) string {
+	if len(items) == 0 {
+		return i18n.T(lang, "line.my_events.empty")
+	}
+
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, "line.my_events.header", total, len(items)))
+	for _, it := range items {
+		role := i18n.T(lang, "line.role.participant")
+		if it.Role == application.EventRoleHost {
+			role = i18n.T(lang, "line.role.host")
+		}
+		status := i18n.T(lang, "line.status."+application.EventStatusName(it.Status))
+		b.WriteString("\n\n")
+		b.WriteString(i18n.T(lang, "line.my_events.item", it.Title, role, status, it.VotedCount, it.ParticipantCount))
+		if it.DecidedStart != nil {
+			b.WriteString("\n")
+			b.WriteString(i18n.T(lang, "line.my_events.decided", it.DecidedStart.Format("1/2 15:04")))
+		}
+	}
+	return b.String()
+}
+
+func getResMessage(lang i18n.Lang, message string) string {
+	if i18n.Matches(scheduleKeyword, message) {
+		formURL := getFormURL()
+		return formURL
+	}
+	return i18n.T(lang, "line.default")
+}
+
+func getHelpMessage(lang i18n.Lang) string {
+	return i18n.T(lang, "line.help")
+}
+
+func getFormURL() string {
+	// TODO: LINEのメッセージ解析やフォーム生成ロジックの実装
+	return "https://adju-sche-front-end.vercel.app/admin/create"
+}
+
+func getAccountLinkURL(linkToken string) string {
+	return "https://adju-sche-front-end.vercel.app/line/link?linkToken=" + url.QueryEscape(linkToken)
+}
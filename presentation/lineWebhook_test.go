@@ -0,0 +1,346 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/i18n"
+	"adjuSche-back-end/middleware"
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/line/line-bot-sdk-go/v7/linebot"
+)
+
+const testChannelSecret = "test-channel-secret"
+
+type replyRequest struct {
+	ReplyToken string `json:"replyToken"`
+	Messages   []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"messages"`
+}
+
+// newTestLineServer は LINE Messaging API のスタブと、それを向いた Webhook 用ルータを返す
+func newTestLineServer(t *testing.T) (*gin.Engine, <-chan replyRequest) {
+	return newTestLineServerWithLanguage(t, "ja")
+}
+
+// newTestLineServerWithLanguage はプロフィールの言語を language にした LINE Messaging API のスタブを使う
+func newTestLineServerWithLanguage(t *testing.T, language string) (*gin.Engine, <-chan replyRequest) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	replies := make(chan replyRequest, 10)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/v2/bot/profile/") {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]string{"userId": "U0123456789", "displayName": "test", "language": language})
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/linkToken") {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"linkToken":"test-link-token"}`)
+			return
+		}
+		if r.URL.Path != linebot.APIEndpointReplyMessage {
+			t.Errorf("unexpected LINE API call: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var req replyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode reply request: %v", err)
+		}
+		replies <- req
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, "{}")
+	}))
+	t.Cleanup(api.Close)
+
+	bot, err := linebot.New(testChannelSecret, "test-channel-token", linebot.WithEndpointBase(api.URL))
+	if err != nil {
+		t.Fatalf("failed to init bot: %v", err)
+	}
+
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.POST("/line/webhook", NewLineHandler(bot).Webhook)
+	return r, replies
+}
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func textMessagePayload(replyToken, text string) []byte {
+	payload := map[string]any{
+		"destination": "Uxxxxxxxx",
+		"events": []map[string]any{{
+			"type":       "message",
+			"mode":       "active",
+			"timestamp":  time.Now().UnixMilli(),
+			"replyToken": replyToken,
+			"source":     map[string]any{"type": "user", "userId": "U0123456789"},
+			"message":    map[string]any{"id": "1", "type": "text", "text": text},
+		}},
+	}
+	b, _ := json.Marshal(payload)
+	return b
+}
+
+func postWebhook(r *gin.Engine, body []byte, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/line/webhook", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if signature != "" {
+		req.Header.Set("X-Line-Signature", signature)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func waitReply(t *testing.T, replies <-chan replyRequest) replyRequest {
+	t.Helper()
+	select {
+	case rep := <-replies:
+		return rep
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for reply")
+		return replyRequest{}
+	}
+}
+
+func TestWebhookRejectsInvalidSignature(t *testing.T) {
+	r, replies := newTestLineServer(t)
+	body := textMessagePayload("token-1", "日程調整")
+
+	w := postWebhook(r, body, sign("wrong-secret", body))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	var res middleware.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatalf("failed to decode error response: %v", err)
+	}
+	if res.Code != "invalid_signature" {
+		t.Errorf("code = %q, want %q", res.Code, "invalid_signature")
+	}
+
+	w = postWebhook(r, body, "")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status without signature = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	select {
+	case rep := <-replies:
+		t.Fatalf("unexpected reply: %+v", rep)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWebhookRejectsMalformedBody(t *testing.T) {
+	r, _ := newTestLineServer(t)
+	body := []byte("{not json")
+
+	w := postWebhook(r, body, sign(testChannelSecret, body))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestWebhookRepliesToTextMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		text     string
+		want     string
+	}{
+		{name: "schedule keyword", language: "ja", text: "日程調整", want: getFormURL()},
+		{name: "other text", language: "ja", text: "こんにちは", want: "日程調整をしたい場合は、「日程調整」と入力してください。"},
+		{name: "english schedule keyword", language: "en", text: "Schedule", want: getFormURL()},
+		{name: "english other text", language: "en-US", text: "hello", want: "To schedule an event, send \"schedule\"."},
+		{name: "unsupported language", language: "zh-Hant", text: "hello", want: "日程調整をしたい場合は、「日程調整」と入力してください。"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, replies := newTestLineServerWithLanguage(t, tt.language)
+			body := textMessagePayload("token-"+tt.name, tt.text)
+
+			w := postWebhook(r, body, sign(testChannelSecret, body))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+
+			rep := waitReply(t, replies)
+			if rep.ReplyToken != "token-"+tt.name {
+				t.Errorf("replyToken = %q, want %q", rep.ReplyToken, "token-"+tt.name)
+			}
+			if len(rep.Messages) != 1 || rep.Messages[0].Text != tt.want {
+				t.Errorf("messages = %+v, want text %q", rep.Messages, tt.want)
+			}
+		})
+	}
+}
+
+func postbackPayload(replyToken, data string) []byte {
+	payload := map[string]any{
+		"destination": "Uxxxxxxxx",
+		"events": []map[string]any{{
+			"type":       "postback",
+			"mode":       "active",
+			"timestamp":  time.Now().UnixMilli(),
+			"replyToken": replyToken,
+			"source":     map[string]any{"type": "user", "userId": "U0123456789"},
+			"postback":   map[string]any{"data": data},
+		}},
+	}
+	b, _ := json.Marshal(payload)
+	return b
+}
+
+func TestWebhookRoutesRichMenuPostback(t *testing.T) {
+	tests := []struct {
+		name   string
+		action string
+		want   string
+	}{
+		{name: "new event", action: servise.RichMenuActionNewEvent, want: getFormURL()},
+		{name: "help", action: servise.RichMenuActionHelp, want: getHelpMessage(i18n.Ja)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, replies := newTestLineServer(t)
+			body := postbackPayload("token-postback", servise.RichMenuPostbackData(tt.action))
+
+			w := postWebhook(r, body, sign(testChannelSecret, body))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+
+			rep := waitReply(t, replies)
+			if len(rep.Messages) != 1 || rep.Messages[0].Text != tt.want {
+				t.Errorf("messages = %+v, want text %q", rep.Messages, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookStartsAccountLink(t *testing.T) {
+	r, replies := newTestLineServer(t)
+	body := textMessagePayload("token-link", "アカウント連携")
+
+	w := postWebhook(r, body, sign(testChannelSecret, body))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	rep := waitReply(t, replies)
+	if len(rep.Messages) != 1 || !strings.Contains(rep.Messages[0].Text, getAccountLinkURL("test-link-token")) {
+		t.Errorf("messages = %+v, want link URL", rep.Messages)
+	}
+}
+
+func TestWebhookAcceptsEmptyEvents(t *testing.T) {
+	r, _ := newTestLineServer(t)
+	body := []byte(`{"destination":"Uxxxxxxxx","events":[]}`)
+
+	w := postWebhook(r, body, sign(testChannelSecret, body))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+// useFakeLineUser は LINE userId の連携先の取得を、lineUserID を記録して userID と err を返す実装に差し替える
+func useFakeLineUser(t *testing.T, userID string, err error) *[]string {
+	t.Helper()
+	var lineUserIDs []string
+	orig := getUserIDByLineUserID
+	t.Cleanup(func() { getUserIDByLineUserID = orig })
+	getUserIDByLineUserID = func(_ context.Context, lineUserID string) (string, error) {
+		lineUserIDs = append(lineUserIDs, lineUserID)
+		return userID, err
+	}
+	return &lineUserIDs
+}
+
+func TestWebhookRepliesMyEvents(t *testing.T) {
+	lineUserIDs := useFakeLineUser(t, "linked-user", nil)
+	decided := time.Date(2026, 11, 2, 10, 0, 0, 0, time.UTC)
+	inputs := useFakeMyEvents(t, []application.MyEventSummary{
+		{EventID: 2, Title: "勉強会", Role: application.EventRoleParticipant, Status: repository.EventStatusClosed, VotedCount: 4, ParticipantCount: 5, DecidedStart: &decided},
+		{EventID: 1, Title: "定例会", Role: application.EventRoleHost, Status: repository.EventStatusOpen, VotedCount: 1, ParticipantCount: 3},
+	}, 12)
+	r, replies := newTestLineServer(t)
+	body := textMessagePayload("token-my-events", "マイイベント")
+
+	if w := postWebhook(r, body, sign(testChannelSecret, body)); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	rep := waitReply(t, replies)
+	want := "マイイベント（12件中2件）\n\n" +
+		"・勉強会［参加］締切\n  投票 4/5人\n  確定: 11/2 10:00\n\n" +
+		"・定例会［主催］募集中\n  投票 1/3人"
+	if len(rep.Messages) != 1 || rep.Messages[0].Text != want {
+		t.Errorf("messages = %+v, want text %q", rep.Messages, want)
+	}
+	if len(*lineUserIDs) != 1 || (*lineUserIDs)[0] != "U0123456789" {
+		t.Errorf("looked up LINE users %v, want [U0123456789]", *lineUserIDs)
+	}
+	// LINE では先頭の lineMyEventsLimit 件だけを返す
+	if len(*inputs) != 1 || (*inputs)[0].UserID != "linked-user" || (*inputs)[0].Limit != lineMyEventsLimit || (*inputs)[0].Offset != 0 {
+		t.Errorf("ListMyEvents inputs = %+v, want the first %d events of linked-user", *inputs, lineMyEventsLimit)
+	}
+}
+
+func TestWebhookRepliesMyEventsWithoutEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		userID string
+		err    error
+		want   string
+		listed int
+	}{
+		{name: "not linked", err: application.ErrLineAccountNotLinked, want: i18n.T(i18n.Ja, "line.my_events.not_linked")},
+		{name: "lookup failed", err: errors.New("db down"), want: i18n.T(i18n.Ja, "line.my_events.error")},
+		{name: "no events", userID: "linked-user", want: i18n.T(i18n.Ja, "line.my_events.empty"), listed: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeLineUser(t, tt.userID, tt.err)
+			inputs := useFakeMyEvents(t, nil, 0)
+			r, replies := newTestLineServer(t)
+			body := textMessagePayload("token-my-events", "マイイベント")
+
+			if w := postWebhook(r, body, sign(testChannelSecret, body)); w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+
+			rep := waitReply(t, replies)
+			if len(rep.Messages) != 1 || rep.Messages[0].Text != tt.want {
+				t.Errorf("messages = %+v, want text %q", rep.Messages, tt.want)
+			}
+			if len(*inputs) != tt.listed {
+				t.Errorf("ListMyEvents called %d times, want %d", len(*inputs), tt.listed)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type eventConditionResponse struct {
+	ID          string `json:"id"`
+	PeriodStart string `json:"periodStart"`
+	PeriodEnd   string `json:"periodEnd"`
+	TimeStart   string `json:"timeStart,omitempty"`
+	TimeEnd     string `json:"timeEnd,omitempty"`
+	DurationMin int    `json:"durationMin"`
+	CreatedAt   string `json:"createdAt"`
+}
+
+type EventDetailResponse struct {
+	EventID          string                 `json:"eventId"`
+	HostUserID       string                 `json:"hostUserID"`
+	Title            string                 `json:"title"`
+	Memo             string                 `json:"memo"`
+	ParticipantCount int64                  `json:"participantCount"`
+	Status           string                 `json:"status"`
+	DecidedStart     string                 `json:"decidedStart,omitempty"`
+	DecidedEnd       string                 `json:"decidedEnd,omitempty"`
+	CreatedAt        string                 `json:"createdAt"`
+	UpdatedAt        string                 `json:"updatedAt"`
+	Conditions       eventConditionResponse `json:"conditions"`
+}
+
+type UpdateEventRequest struct {
+	Title            *string `json:"title"`
+	Memo             *string `json:"memo"`
+	ParticipantCount *int    `json:"participantCount"`
+}
+
+type UpdateEventConditionRequest struct {
+	PeriodStart *string `json:"periodStart"`
+	PeriodEnd   *string `json:"periodEnd"`
+	TimeStart   *string `json:"timeStart"`
+	TimeEnd     *string `json:"timeEnd"`
+	DurationMin *int    `json:"durationMin"`
+}
+
+type FinalizeEventRequest struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// GetEvent はイベントの詳細と最新の条件を返す（主催者のみ）
+func GetEvent(c *gin.Context) {
+	userID, eventID, ok := bindHostRequest(c)
+	if !ok {
+		return
+	}
+
+	detail, err := application.GetEventDetail(c.Request.Context(), eventID, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newEventDetailResponse(detail))
+}
+
+// UpdateEvent はイベントのタイトル・メモ・参加人数を更新する（主催者のみ）
+func UpdateEvent(c *gin.Context) {
+	userID, eventID, ok := bindHostRequest(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindJSONError(err))
+		return
+	}
+
+	detail, err := application.UpdateEvent(c.Request.Context(), application.UpdateEventInput{
+		EventID:          eventID,
+		UserID:           userID,
+		Title:            req.Title,
+		Memo:             req.Memo,
+		ParticipantCount: req.ParticipantCount,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newEventDetailResponse(detail))
+}
+
+// FinalizeEvent は日時を確定する（主催者のみ）
+func FinalizeEvent(c *gin.Context) {
+	userID, eventID, ok := bindHostRequest(c)
+	if !ok {
+		return
+	}
+
+	var req FinalizeEventRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindJSONError(err))
+		return
+	}
+
+	detail, err := application.FinalizeEvent(c.Request.Context(), application.FinalizeEventInput{
+		EventID: eventID,
+		UserID:  userID,
+		Start:   req.Start,
+		End:     req.End,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newEventDetailResponse(detail))
+}
+
+// CancelEvent はイベントを中止する（主催者のみ）
+func CancelEvent(c *gin.Context) {
+	userID, eventID, ok := bindHostRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := application.CancelEvent(c.Request.Context(), eventID, userID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success"})
+}
+
+// DeleteEvent はイベントと関連データを削除する（主催者のみ）
+func DeleteEvent(c *gin.Context) {
+	userID, eventID, ok := bindHostRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := application.DeleteEvent(c.Request.Context(), eventID, userID); err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "success"})
+}
+
+// GetEventConditions はイベント条件の全バージョンを新しい順に返す（主催者のみ）
+func GetEventConditions(c *gin.Context) {
+	userID, eventID, ok := bindHostRequest(c)
+	if !ok {
+		return
+	}
+
+	conds, err := application.ListEventConditionHistory(c.Request.Context(), eventID, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	res := make([]eventConditionResponse, 0, len(conds))
+	for _, cond := range conds {
+		res = append(res, newEventConditionResponse(cond))
+	}
+	c.JSON(http.StatusOK, gin.H{"conditions": res})
+}
+
+// UpdateEventCondition はイベント条件の新しいバージョンを作成する（主催者のみ）
+func UpdateEventCondition(c *gin.Context) {
+	userID, eventID, ok := bindHostRequest(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateEventConditionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindJSONError(err))
+		return
+	}
+
+	detail, err := application.UpdateEventCondition(c.Request.Context(), application.UpdateEventConditionInput{
+		EventID:     eventID,
+		UserID:      userID,
+		PeriodStart: req.PeriodStart,
+		PeriodEnd:   req.PeriodEnd,
+		TimeStart:   req.TimeStart,
+		TimeEnd:     req.TimeEnd,
+		DurationMin: req.DurationMin,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newEventDetailResponse(detail))
+}
+
+// bindHostRequest はセッションのユーザーIDとパスのイベントIDを取り出す
+func bindHostRequest(c *gin.Context) (string, int64, bool) {
+	userID, err := servise.ExtractSessionUserID(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return "", 0, false
+	}
+
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errInvalidEventID.Wrap(err))
+		return "", 0, false
+	}
+	return userID, eventID, true
+}
+
+func newEventDetailResponse(d application.EventDetail) EventDetailResponse {
+	res := EventDetailResponse{
+		EventID:          strconv.FormatInt(d.Event.ID, 10),
+		HostUserID:       d.Event.HostUserID,
+		Title:            d.Event.Title,
+		Memo:             d.Event.Note.String,
+		ParticipantCount: d.Event.ParticipantCount,
+		Status:           application.EventStatusName(d.Event.Status),
+		CreatedAt:        d.Event.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        d.Event.UpdatedAt.Format(time.RFC3339),
+		Conditions:       newEventConditionResponse(d.Condition),
+	}
+	if d.Event.DecidedStart.Valid {
+		res.DecidedStart = d.Event.DecidedStart.Time.Format(time.RFC3339)
+	}
+	if d.Event.DecidedEnd.Valid {
+		res.DecidedEnd = d.Event.DecidedEnd.Time.Format(time.RFC3339)
+	}
+	return res
+}
+
+func newEventConditionResponse(cond repository.EventCondition) eventConditionResponse {
+	return eventConditionResponse{
+		ID:          strconv.FormatInt(cond.ID, 10),
+		PeriodStart: cond.PeriodStart.Format(time.RFC3339),
+		PeriodEnd:   cond.PeriodEnd.Format(time.RFC3339),
+		TimeStart:   cond.TimeStart.String,
+		TimeEnd:     cond.TimeEnd.String,
+		DurationMin: cond.DurationMin,
+		CreatedAt:   cond.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,92 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/servise"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportAvailabilityICS はセッションのユーザーの空き時間を .ics ファイル（Outlook や Apple カレンダーの書き出し）から取り込む
+// multipart/form-data: file（.ics ファイル）または url（公開されている .ics の URL）
+func ImportAvailabilityICS(c *gin.Context) {
+	userID, err := servise.ExtractSessionUserID(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return
+	}
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errInvalidEventID.Wrap(err))
+		return
+	}
+
+	in := application.ImportAvailabilityInput{
+		EventID: eventID,
+		UserID:  userID,
+		URL:     c.PostForm("url"),
+	}
+	if fh, err := c.FormFile("file"); err == nil {
+		f, err := fh.Open()
+		if err != nil {
+			c.Error(errInvalidRequestBody.Wrap(err))
+			return
+		}
+		defer f.Close()
+		in.ICS, err = servise.ReadICS(f)
+		if err != nil {
+			c.Error(application.ErrInvalidICS.Wrap(err))
+			return
+		}
+	}
+
+	free, err := application.ImportAvailabilityFromICS(c.Request.Context(), in)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newImportAvailabilityResponse(free))
+}
+
+type ImportAvailabilityCalDAVRequest struct {
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// ImportAvailabilityCalDAV はセッションのユーザーの空き時間を CalDAV のカレンダー (Nextcloud, iCloud, Fastmail など) から取り込む
+// url には公開されているカレンダーコレクションの URL、password にはアプリ用パスワードを指定する
+func ImportAvailabilityCalDAV(c *gin.Context) {
+	userID, err := servise.ExtractSessionUserID(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return
+	}
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errInvalidEventID.Wrap(err))
+		return
+	}
+
+	var req ImportAvailabilityCalDAVRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindJSONError(err))
+		return
+	}
+
+	free, err := application.ImportAvailabilityFromCalDAV(c.Request.Context(), application.ImportCalDAVInput{
+		EventID:     eventID,
+		UserID:      userID,
+		CalendarURL: req.URL,
+		Username:    req.Username,
+		Password:    req.Password,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newImportAvailabilityResponse(free))
+}
@@ -0,0 +1,100 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/servise"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AvailabilityPreviewResponse struct {
+	InviteUserResponse
+	PreviewToken   string                 `json:"previewToken"`
+	ExpiresAt      string                 `json:"expiresAt"`
+	Availabilities []availabilityInterval `json:"availabilities"`
+}
+
+// PreviewCalendarAvailability はセッションのユーザーのカレンダーから求めた空き時間と、それを含めた場合の候補日時を返す
+// 空き時間はまだ保存しないため、確認後に POST /events/:id/availability/confirm で previewToken を送って確定する
+func PreviewCalendarAvailability(c *gin.Context) {
+	userID, err := servise.ExtractSessionUserID(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return
+	}
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errInvalidEventID.Wrap(err))
+		return
+	}
+
+	var req SubmitCalendarAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindJSONError(err))
+		return
+	}
+
+	tokenString, err := servise.ExtractTokenFromHeader(c)
+	if err != nil {
+		c.Error(errAuthTokenRequired.Wrap(err))
+		return
+	}
+
+	preview, err := application.PreviewAvailabilityFromCalendar(c.Request.Context(), application.SubmitCalendarAvailabilityInput{
+		EventID: eventID,
+		UserID:  userID,
+		Access:  application.CalendarAccess{Provider: req.Provider, Token: tokenString, CredFile: credFile},
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newAvailabilityPreviewResponse(preview))
+}
+
+type ConfirmAvailabilityRequest struct {
+	PreviewToken string `json:"previewToken"`
+}
+
+// ConfirmAvailability はセッションのユーザーがプレビューした空き時間を保存し、参加者として登録する
+func ConfirmAvailability(c *gin.Context) {
+	userID, err := servise.ExtractSessionUserID(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return
+	}
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errInvalidEventID.Wrap(err))
+		return
+	}
+
+	var req ConfirmAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindJSONError(err))
+		return
+	}
+
+	free, err := application.ConfirmAvailabilityPreview(c.Request.Context(), application.ConfirmAvailabilityInput{
+		EventID: eventID,
+		UserID:  userID,
+		Token:   req.PreviewToken,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newImportAvailabilityResponse(free))
+}
+
+func newAvailabilityPreviewResponse(preview *application.AvailabilityPreview) AvailabilityPreviewResponse {
+	return AvailabilityPreviewResponse{
+		InviteUserResponse: newInviteUserResponse(preview.Summary, preview.Slots),
+		PreviewToken:       preview.Token,
+		ExpiresAt:          preview.ExpiresAt.Format(time.RFC3339),
+		Availabilities:     newAvailabilityIntervals(preview.Availabilities),
+	}
+}
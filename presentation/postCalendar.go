@@ -0,0 +1,86 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/domain"
+	"adjuSche-back-end/servise"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// credFile は Google の OAuth クライアントシークレットファイル
+const credFile = "client_secret.json"
+
+// GetCalendarFreeIntervals は Google カレンダーの予定から、指定範囲の空き時間を返す
+// ログインしていれば（X-Session-Token）、セッションのユーザーの繰り返しのルールと勤務時間の外の時間帯も除く
+func GetCalendarFreeIntervals(c *gin.Context) {
+	var userID string
+	if c.GetHeader(servise.SessionHeader) != "" {
+		var err error
+		userID, err = servise.ExtractSessionUserID(c)
+		if err != nil {
+			c.Error(errLoginRequired.Wrap(err))
+			return
+		}
+	}
+
+	tokenString, err := servise.ExtractTokenFromHeader(c)
+	if err != nil {
+		log.Printf("トークンの取得に失敗しました: %v", err)
+		c.Error(errAuthTokenRequired.Wrap(err))
+		return
+	}
+
+	calendarService, err := servise.NewCalendarServiceFromTokenString(tokenString, credFile)
+	if err != nil {
+		log.Printf("カレンダーサービスの初期化に失敗しました: %v", err)
+		c.Error(domain.Unauthorized("invalid_token", "提供されたトークンが無効です").Wrap(err))
+		return
+	}
+
+	var req servise.DateRangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Printf("リクエストボディのバインドに失敗しました: %v", err)
+		c.Error(domain.BadRequest("invalid_calendar_request", "JSON形式のstart_date, end_dateを指定してください (RFC3339)").Wrap(err))
+		return
+	}
+
+	startTime, err := time.Parse(time.RFC3339, req.StartDate)
+	if err != nil {
+		log.Printf("開始日時の解析に失敗しました: %v", err)
+		c.Error(domain.BadRequest("invalid_start_date", "start_dateはRFC3339形式で指定してください").Wrap(err))
+		return
+	}
+
+	endTime, err := time.Parse(time.RFC3339, req.EndDate)
+	if err != nil {
+		log.Printf("終了日時の解析に失敗しました: %v", err)
+		c.Error(domain.BadRequest("invalid_end_date", "end_dateはRFC3339形式で指定してください").Wrap(err))
+		return
+	}
+
+	if endTime.Before(startTime) {
+		c.Error(domain.BadRequest("invalid_date_range", "end_dateはstart_date以降である必要があります"))
+		return
+	}
+
+	if req.DurationMin < 0 {
+		c.Error(domain.BadRequest("invalid_duration", "durationMinは0以上で指定してください"))
+		return
+	}
+
+	events, err := application.GetCalendarFreeIntervals(c.Request.Context(), userID, calendarService, startTime, endTime, req.DurationMin)
+	if err != nil {
+		log.Printf("イベントの取得に失敗しました: %v", err)
+		c.Error(fmt.Errorf("Googleカレンダーからのイベント取得に失敗しました: %w", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+	})
+}
@@ -0,0 +1,55 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/servise"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// submitAvailabilityFromCalendar はカレンダーからの空き時間の保存（テストでカレンダーと DB を使わない実装に差し替える）
+var submitAvailabilityFromCalendar = application.SubmitAvailabilityFromCalendar
+
+type SubmitCalendarAvailabilityRequest struct {
+	Provider string `json:"provider"` // "google"（省略時）または "microsoft"
+}
+
+// SubmitCalendarAvailability はセッションのユーザーの空き時間を Google / Microsoft 365 のカレンダーから求めて保存する
+// カレンダーのトークンは Authorization: Bearer で受け取る。保存後の候補日時は GET /events/:id/invite で取得する
+func SubmitCalendarAvailability(c *gin.Context) {
+	userID, err := servise.ExtractSessionUserID(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return
+	}
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errInvalidEventID.Wrap(err))
+		return
+	}
+
+	var req SubmitCalendarAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindJSONError(err))
+		return
+	}
+
+	tokenString, err := servise.ExtractTokenFromHeader(c)
+	if err != nil {
+		c.Error(errAuthTokenRequired.Wrap(err))
+		return
+	}
+
+	free, err := submitAvailabilityFromCalendar(c.Request.Context(), application.SubmitCalendarAvailabilityInput{
+		EventID: eventID,
+		UserID:  userID,
+		Access:  application.CalendarAccess{Provider: req.Provider, Token: tokenString, CredFile: credFile},
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newImportAvailabilityResponse(free))
+}
@@ -0,0 +1,113 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/middleware"
+	"adjuSche-back-end/servise"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// useFakeCalendarSubmission はカレンダーからの空き時間の保存を、入力を記録して固定の空き時間を返す実装に差し替える
+func useFakeCalendarSubmission(t *testing.T) *[]application.SubmitCalendarAvailabilityInput {
+	t.Helper()
+	var inputs []application.SubmitCalendarAvailabilityInput
+	orig := submitAvailabilityFromCalendar
+	t.Cleanup(func() { submitAvailabilityFromCalendar = orig })
+	submitAvailabilityFromCalendar = func(_ context.Context, in application.SubmitCalendarAvailabilityInput) ([]servise.TimeInterval, error) {
+		inputs = append(inputs, in)
+		start := time.Date(2026, 11, 2, 10, 0, 0, 0, time.UTC)
+		return []servise.TimeInterval{{Start: start, End: start.Add(time.Hour)}}, nil
+	}
+	return &inputs
+}
+
+func postCalendarAvailability(body string, header map[string]string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.POST("/events/:id/availability/calendar", SubmitCalendarAvailability)
+
+	req := httptest.NewRequest(http.MethodPost, "/events/42/availability/calendar", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestSubmitCalendarAvailability(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+	inputs := useFakeCalendarSubmission(t)
+	session, _, err := servise.IssueSessionToken("session-user", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := postCalendarAvailability(`{"provider":"microsoft"}`, map[string]string{
+		servise.SessionHeader: session,
+		"Authorization":       "Bearer calendar-token",
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200: %s", w.Code, w.Body.String())
+	}
+	var res ImportAvailabilityResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != "success" || len(res.Availabilities) != 1 || res.Availabilities[0].Start != "2026-11-02T10:00:00Z" {
+		t.Errorf("response = %+v", res)
+	}
+
+	if len(*inputs) != 1 {
+		t.Fatalf("SubmitAvailabilityFromCalendar called %d times, want 1", len(*inputs))
+	}
+	in := (*inputs)[0]
+	if in.EventID != 42 || in.UserID != "session-user" {
+		t.Errorf("input = %+v, want event 42 for session-user", in)
+	}
+	if in.Access.Provider != "microsoft" || in.Access.Token != "calendar-token" || in.Access.CredFile != credFile {
+		t.Errorf("access = %+v", in.Access)
+	}
+}
+
+func TestSubmitCalendarAvailabilityRejects(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+	inputs := useFakeCalendarSubmission(t)
+	session, _, err := servise.IssueSessionToken("session-user", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		status int
+		code   string
+	}{
+		{"no session", map[string]string{"Authorization": "Bearer calendar-token"}, http.StatusUnauthorized, "login_required"},
+		{"no calendar token", map[string]string{servise.SessionHeader: session}, http.StatusBadRequest, "auth_token_required"},
+	}
+	for _, tt := range tests {
+		w := postCalendarAvailability(`{}`, tt.header)
+		var res middleware.ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+			t.Fatalf("%s: failed to decode response %q: %v", tt.name, w.Body.String(), err)
+		}
+		if w.Code != tt.status || res.Code != tt.code {
+			t.Errorf("%s: got %d %s, want %d %s", tt.name, w.Code, res.Code, tt.status, tt.code)
+		}
+	}
+	if len(*inputs) != 0 {
+		t.Errorf("SubmitAvailabilityFromCalendar called for rejected requests: %+v", *inputs)
+	}
+}
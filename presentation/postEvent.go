@@ -8,20 +8,21 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// 必須項目や値の範囲は application.ValidateCreateEventInput で検証し、422 で項目ごとに返す
 type eventConditions struct {
-	PeriodStart string `json:"periodStart" binding:"required"`
-	PeriodEnd   string `json:"periodEnd" binding:"required"`
+	PeriodStart string `json:"periodStart"`
+	PeriodEnd   string `json:"periodEnd"`
 	TimeStart   string `json:"timeStart"`
 	TimeEnd     string `json:"timeEnd"`
-	DurationMin int    `json:"durationMin" binding:"required"`
+	DurationMin int    `json:"durationMin"`
 }
 
 type CreateEventRequest struct {
-	HostUserID       string          `json:"hostUserID" binding:"required"`
-	Title            string          `json:"title" binding:"required"`
+	HostUserID       string          `json:"hostUserID"`
+	Title            string          `json:"title"`
 	Memo             string          `json:"memo"`
-	ParticipantCount int             `json:"participantCount" binding:"required"`
-	Conditions       eventConditions `json:"conditions" binding:"required"`
+	ParticipantCount int             `json:"participantCount"`
+	Conditions       eventConditions `json:"conditions"`
 }
 
 type CreateEventResponse struct {
@@ -32,10 +33,7 @@ type CreateEventResponse struct {
 func CreateEvent(c *gin.Context) {
 	var req CreateEventRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status": "error",
-			"error":  "無効なリクエストボディです",
-		})
+		c.Error(bindJSONError(err))
 		return
 	}
 
@@ -51,7 +49,7 @@ func CreateEvent(c *gin.Context) {
 		DurationMin:      req.Conditions.DurationMin,
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -68,13 +66,13 @@ type GetEventNameByIDRequest  struct {
 func GetEventNameByID(c *gin.Context) {
 	var req GetEventNameByIDRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": err.Error()})
+		c.Error(bindJSONError(err))
 		return
 	}
 
 	eventName, err := application.GetEventNameByID(c.Request.Context(), req.EventID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -0,0 +1,49 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LiffLoginRequest struct {
+	IDToken string `json:"idToken" binding:"required"`
+}
+
+type LiffLoginResponse struct {
+	Status       string `json:"status"`
+	UserID       string `json:"userId"`
+	DisplayName  string `json:"displayName"`
+	PictureURL   string `json:"pictureUrl,omitempty"`
+	SessionToken string `json:"sessionToken"`
+	ExpiresAt    string `json:"expiresAt"`
+}
+
+// LiffLogin は LIFF の ID トークンを検証してアプリのセッションを発行する
+// LINE 内で招待ページを開いた参加者が別途サインインせずに利用できるようにする
+func LiffLogin(c *gin.Context) {
+	var req LiffLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindJSONError(err))
+		return
+	}
+
+	session, err := application.LoginWithLineIDToken(c.Request.Context(), req.IDToken)
+	if err != nil {
+		log.Printf("LIFF ログインに失敗しました: %v", err)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, LiffLoginResponse{
+		Status:       "success",
+		UserID:       session.UserID,
+		DisplayName:  session.DisplayName,
+		PictureURL:   session.PictureURL,
+		SessionToken: session.SessionToken,
+		ExpiresAt:    session.ExpiresAt.Format(time.RFC3339),
+	})
+}
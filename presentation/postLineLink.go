@@ -0,0 +1,73 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/servise"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// issueLineLinkNonce は連携用の nonce の発行（テストで DB を使わない実装に差し替える）
+var issueLineLinkNonce = application.IssueLineLinkNonce
+
+type CreateLineLinkRequest struct {
+	LinkToken string `json:"linkToken" binding:"required"`
+}
+
+type CreateLineLinkResponse struct {
+	Status      string `json:"status"`
+	RedirectURL string `json:"redirectUrl"`
+}
+
+// CreateLineLink はサインインしているユーザーの nonce を発行し、LINE のアカウント連携 URL を返す
+// フロントエンドはこの URL へユーザーをリダイレクトさせる
+func CreateLineLink(c *gin.Context) {
+	userID, ok := lineLinkUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateLineLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindJSONError(err))
+		return
+	}
+
+	redirectURL, err := issueLineLinkNonce(c.Request.Context(), userID, req.LinkToken)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, CreateLineLinkResponse{
+		Status:      "success",
+		RedirectURL: redirectURL,
+	})
+}
+
+// lineLinkUserID は連携するアプリのユーザーを返す
+// 連携前はセッションを持っていないことがあるため、Authorization ヘッダの Supabase のアクセストークンで認証する
+// セッションがあればそちらを使う
+func lineLinkUserID(c *gin.Context) (string, bool) {
+	if c.GetHeader(servise.SessionHeader) != "" {
+		userID, err := servise.ExtractSessionUserID(c)
+		if err != nil {
+			c.Error(errLoginRequired.Wrap(err))
+			return "", false
+		}
+		return userID, true
+	}
+
+	accessToken, err := servise.ExtractTokenFromHeader(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return "", false
+	}
+	userID, err := application.AuthenticateSupabaseUser(c.Request.Context(), accessToken)
+	if err != nil {
+		c.Error(err)
+		return "", false
+	}
+	return userID, true
+}
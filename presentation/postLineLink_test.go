@@ -0,0 +1,106 @@
+package presentation
+
+import (
+	"adjuSche-back-end/middleware"
+	"adjuSche-back-end/servise"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// useFakeLineLinkNonce は nonce の発行を、連携するユーザーを記録するだけの実装に差し替える
+func useFakeLineLinkNonce(t *testing.T) *[]string {
+	t.Helper()
+	var linked []string
+	orig := issueLineLinkNonce
+	t.Cleanup(func() { issueLineLinkNonce = orig })
+	issueLineLinkNonce = func(_ context.Context, userID, linkToken string) (string, error) {
+		linked = append(linked, userID)
+		return "https://access.line.me/dialog/bot/accountLink?linkToken=" + linkToken + "&nonce=n", nil
+	}
+	return &linked
+}
+
+func postLineLink(header map[string]string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.POST("/line/link", CreateLineLink)
+
+	req := httptest.NewRequest(http.MethodPost, "/line/link", strings.NewReader(`{"linkToken":"link-1"}`))
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateLineLinkWithSupabaseToken(t *testing.T) {
+	useSupabaseAuthStub(t)
+	linked := useFakeLineLinkNonce(t)
+
+	w := postLineLink(map[string]string{"Authorization": "Bearer access-1"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	var res CreateLineLinkResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(res.RedirectURL, "linkToken=link-1") {
+		t.Errorf("redirectUrl = %q", res.RedirectURL)
+	}
+	if len(*linked) != 1 || (*linked)[0] != testSupabaseUserID {
+		t.Errorf("linked users = %v, want [%s]", *linked, testSupabaseUserID)
+	}
+}
+
+func TestCreateLineLinkWithSession(t *testing.T) {
+	useSupabaseAuthStub(t)
+	linked := useFakeLineLinkNonce(t)
+	session, _, err := servise.IssueSessionToken("session-user", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w := postLineLink(map[string]string{servise.SessionHeader: session}); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if len(*linked) != 1 || (*linked)[0] != "session-user" {
+		t.Errorf("linked users = %v, want [session-user]", *linked)
+	}
+}
+
+func TestCreateLineLinkRequiresSignIn(t *testing.T) {
+	useSupabaseAuthStub(t)
+	linked := useFakeLineLinkNonce(t)
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		code   string
+	}{
+		{"no credentials", nil, "login_required"},
+		{"invalid access token", map[string]string{"Authorization": "Bearer other-token"}, "invalid_access_token"},
+		{"invalid session", map[string]string{servise.SessionHeader: "forged.token", "Authorization": "Bearer access-1"}, "login_required"},
+	}
+	for _, tt := range tests {
+		w := postLineLink(tt.header)
+		var res middleware.ErrorResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &res)
+		if w.Code != http.StatusUnauthorized || res.Code != tt.code {
+			t.Errorf("%s: got %d %s, want 401 %s", tt.name, w.Code, res.Code, tt.code)
+		}
+	}
+	if len(*linked) != 0 {
+		t.Errorf("linked %v without a valid sign-in", *linked)
+	}
+}
@@ -0,0 +1,38 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/servise"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SubmitManualAvailability はセッションのユーザーの手入力の空き時間を、リクエストの内容で置き換える
+// 繰り返しのルール（GET /me/availability-rules）と勤務時間の外の時間帯は除いて保存する
+func SubmitManualAvailability(c *gin.Context) {
+	userID, err := servise.ExtractSessionUserID(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return
+	}
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errInvalidEventID.Wrap(err))
+		return
+	}
+
+	var req ManualAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindJSONError(err))
+		return
+	}
+
+	free, err := application.SubmitManualAvailability(c.Request.Context(), eventID, userID, req.intervals())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newImportAvailabilityResponse(free))
+}
@@ -0,0 +1,40 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/servise"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionResponse struct {
+	Status       string `json:"status"`
+	UserID       string `json:"userId"`
+	SessionToken string `json:"sessionToken"`
+	ExpiresAt    string `json:"expiresAt"`
+}
+
+// CreateSession は Authorization ヘッダの Supabase のアクセストークンを検証してアプリのセッションを発行する
+// 発行したトークンは X-Session-Token ヘッダで送る。LINE アカウントの連携（POST /line/link）にもこのセッションを使える
+func CreateSession(c *gin.Context) {
+	accessToken, err := servise.ExtractTokenFromHeader(c)
+	if err != nil {
+		c.Error(errAuthTokenRequired.Wrap(err))
+		return
+	}
+
+	session, err := application.LoginWithSupabaseToken(c.Request.Context(), accessToken)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, SessionResponse{
+		Status:       "success",
+		UserID:       session.UserID,
+		SessionToken: session.SessionToken,
+		ExpiresAt:    session.ExpiresAt.Format(time.RFC3339),
+	})
+}
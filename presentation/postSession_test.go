@@ -0,0 +1,89 @@
+package presentation
+
+import (
+	"adjuSche-back-end/middleware"
+	"adjuSche-back-end/servise"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testSupabaseUserID = "0b6f7c1e-3a52-4d7a-9d6e-2f1c8a4b5e60"
+
+// useSupabaseAuthStub は Bearer access-1 だけを有効なトークンとして扱う Supabase Auth のスタブを使う
+func useSupabaseAuthStub(t *testing.T) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		io.WriteString(w, `{"id":"`+testSupabaseUserID+`"}`)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("SUPABASE_URL", srv.URL)
+	t.Setenv("SUPABASE_ANON_KEY", "anon-key")
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+}
+
+func postSession(authorization string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.POST("/session", CreateSession)
+
+	req := httptest.NewRequest(http.MethodPost, "/session", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateSessionFromSupabaseToken(t *testing.T) {
+	useSupabaseAuthStub(t)
+
+	w := postSession("Bearer access-1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	var res SessionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.UserID != testSupabaseUserID {
+		t.Errorf("userId = %q, want %q", res.UserID, testSupabaseUserID)
+	}
+	userID, err := servise.ParseSessionToken(res.SessionToken, time.Now())
+	if err != nil || userID != testSupabaseUserID {
+		t.Errorf("issued session token resolves to %q, %v", userID, err)
+	}
+}
+
+func TestCreateSessionRejectsInvalidTokens(t *testing.T) {
+	useSupabaseAuthStub(t)
+
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+		code          string
+	}{
+		{"no token", "", http.StatusBadRequest, "auth_token_required"},
+		{"rejected by Supabase", "Bearer other-token", http.StatusUnauthorized, "invalid_access_token"},
+	}
+	for _, tt := range tests {
+		w := postSession(tt.authorization)
+		var res middleware.ErrorResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &res)
+		if w.Code != tt.status || res.Code != tt.code {
+			t.Errorf("%s: got %d %s, want %d %s", tt.name, w.Code, res.Code, tt.status, tt.code)
+		}
+	}
+}
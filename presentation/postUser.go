@@ -3,7 +3,6 @@ package presentation
 import (
 	"adjuSche-back-end/application"
 	"adjuSche-back-end/servise"
-	"fmt"
 	"log"
 	"net/http"
 	"strconv"
@@ -12,117 +11,176 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// getInviteSummary は候補日時の集計（テストで DB を使わない実装に差し替える）
+var getInviteSummary = application.GetInviteSummary
+
 type InviteUserRequest struct {
-	UserID  string `json:"userId"`
-	EventID string `json:"eventId"`
+	UserID   string `json:"userId"` // 省略可。セッションがある場合はセッションのユーザーを使う
+	EventID  string `json:"eventId"`
+	Provider string `json:"provider"` // "google"（省略時）または "microsoft"
+	DryRun   bool   `json:"dryRun"`   // true なら空き時間を確定せず、プレビューを返す
 }
 
 type possibleDate struct {
-	ID                   int    `json:"id"`
-	Date                 string `json:"date,omitempty"`
-	PeriodStart          string `json:"periodStart"`
-	PeriodEnd            string `json:"periodEnd"`
-	ParticipateMemberNum int    `json:"participate_member_num"`
+	ID                   int      `json:"id"`
+	Date                 string   `json:"date,omitempty"`
+	PeriodStart          string   `json:"periodStart"`
+	PeriodEnd            string   `json:"periodEnd"`
+	ParticipateMemberNum int      `json:"participate_member_num"`
+	AvailableMembers     []string `json:"availableMembers"` // 参加可能な回答者の表示名
 }
 
 type InviteUserResponse struct {
-	EventName    string         `json:"eventName"`
-	VotedCount   int            `json:"votedCount"`
-	Memo         string         `json:"memo"`
-	PeriodStart  string         `json:"periodStart"`
-	PeriodEnd    string         `json:"periodEnd"`
-	DurationMin  int            `json:"durationMin"`
-	PossibleDate []possibleDate `json:"possibleDate"`
+	EventName     string         `json:"eventName"`
+	VotedCount    int            `json:"votedCount"`
+	DeclinedCount int            `json:"declinedCount"`
+	Memo          string         `json:"memo"`
+	PeriodStart   string         `json:"periodStart"`
+	PeriodEnd     string         `json:"periodEnd"`
+	DurationMin   int            `json:"durationMin"`
+	PossibleDate  []possibleDate `json:"possibleDate"`
 }
 
+// InviteUser は呼び出したユーザーのカレンダーを含めた候補日時を返し、userId があれば空き時間も保存する
+// セッションがあればセッションのユーザー、なければ従来どおり本文の userId の空き時間として保存する
+// dryRun のときは参加者の登録も空き時間の保存もせず、POST /events/:id/availability/confirm で確定するためのプレビューを返す
+// 閲覧だけなら GET /events/:id/invite、空き時間の提出は POST /events/:id/availability/calendar を使う
 func InviteUser(c *gin.Context) {
-	const CredFile = "client_secret.json"
-
 	var req InviteUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status": "error",
-			"error":  "無効なリクエストボディです",
-		})
+		c.Error(bindJSONError(err))
 		return
 	}
 
 	tokenString, err := servise.ExtractTokenFromHeader(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status": "error",
-			"error":  "認証トークンが必要です",
-		})
+		c.Error(errAuthTokenRequired.Wrap(err))
 		return
 	}
 
 	// eventId を int64 へ
 	eventID, err := strconv.ParseInt(req.EventID, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "error": "eventId は数値で指定してください"})
+		c.Error(errInvalidEventID.Wrap(err))
 		return
 	}
 
-	summary, slots, err := application.BuildInviteResponse(c.Request.Context(), eventID, tokenString, CredFile)
+	access := application.CalendarAccess{Provider: req.Provider, Token: tokenString, CredFile: credFile}
+	if req.DryRun {
+		// プレビューの確定はセッションのユーザーに限るため、プレビューもセッションのユーザーで作る
+		userID, err := servise.ExtractSessionUserID(c)
+		if err != nil {
+			c.Error(errLoginRequired.Wrap(err))
+			return
+		}
+		preview, err := application.PreviewAvailabilityFromCalendar(c.Request.Context(), application.SubmitCalendarAvailabilityInput{
+			EventID: eventID,
+			UserID:  userID,
+			Access:  access,
+		})
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		c.JSON(http.StatusOK, newAvailabilityPreviewResponse(preview))
+		return
+	}
+
+	userID, sessionUser := inviteUserID(c, req)
+	summary, slots, free, err := application.BuildInviteResponse(c.Request.Context(), application.InviteInput{
+		EventID:     eventID,
+		UserID:      userID,
+		SessionUser: sessionUser,
+		Access:      access,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "error": err.Error()})
+		c.Error(err)
 		return
 	}
 
-	// userId(UUID) が提供されていれば、参加者登録と空き時間を保存
-	if req.UserID != "" {
-		log.Println("req.UserID", req.UserID)
+	// userId が分かれば、参加者登録と空き時間を保存
+	if userID != "" {
+		log.Println("userID", userID)
 		log.Printf("eventID: %d", eventID)
 
 		// 参加者として登録
-		err := application.RegisterEventParticipant(c.Request.Context(), eventID, req.UserID)
+		err := application.RegisterEventParticipant(c.Request.Context(), eventID, userID)
 		if err != nil {
 			log.Printf("参加者登録に失敗しました: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"status": "error",
-				"error":  fmt.Sprintf("参加者登録に失敗しました: %v", err),
-			})
+			c.Error(err)
 			return
 		}
 		log.Println("参加者登録が完了しました")
 
-		// 空き時間を Availabilities に保存
-		intervals := make([]servise.TimeInterval, 0, len(slots))
-		for _, s := range slots {
-			intervals = append(intervals, servise.TimeInterval{Start: s.PeriodStart, End: s.PeriodEnd})
-		}
-		log.Printf("保存対象の空き時間スロット数: %d", len(intervals))
+		// 候補日時ではなく、ユーザー自身の空き時間を Availabilities に保存
+		log.Printf("保存対象の空き時間スロット数: %d", len(free))
 
-		err = application.SaveUserAvailabilitiesFromCalendar(c.Request.Context(), eventID, req.UserID, intervals)
+		err = application.SaveUserAvailabilitiesFromCalendar(c.Request.Context(), eventID, userID, access.Source(), free)
 		if err != nil {
 			log.Printf("空き時間の保存に失敗しました: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"status": "error",
-				"error":  fmt.Sprintf("空き時間の保存に失敗しました: %v", err),
-			})
+			c.Error(err)
 			return
 		}
 		log.Println("空き時間の保存が完了しました")
 	}
 
-	// 整形
-	res := InviteUserResponse{
-		EventName:   summary.EventName,
-		VotedCount:  summary.VotedCount,
-		Memo:        summary.Memo,
-		PeriodStart: summary.PeriodStart.Format(time.RFC3339),
-		PeriodEnd:   summary.PeriodEnd.Format(time.RFC3339),
-		DurationMin: summary.DurationMin,
+	c.JSON(http.StatusOK, newInviteUserResponse(summary, slots))
+}
+
+// inviteUserID は InviteUser で空き時間を保存するユーザーと、それがセッションのユーザーかどうかを返す
+// 有効なセッションがあればセッションのユーザー、なければ従来のフロントエンドとの互換のため本文の userId を使う（空なら保存しない）
+func inviteUserID(c *gin.Context, req InviteUserRequest) (string, bool) {
+	if c.GetHeader(servise.SessionHeader) != "" {
+		if userID, err := servise.ExtractSessionUserID(c); err == nil {
+			return userID, true
+		} else if req.UserID == "" {
+			log.Printf("セッションを確認できないため、空き時間を保存せずに候補日時を返します: %v", err)
+		}
+	}
+	return req.UserID, false
+}
+
+// GetInvite はイベントの概要と現在の候補日時を返す
+// カレンダーのトークンは不要で、招待 URL を開いただけの人も閲覧できる
+func GetInvite(c *gin.Context) {
+	eventID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errInvalidEventID.Wrap(err))
+		return
+	}
+
+	summary, slots, err := getInviteSummary(c.Request.Context(), eventID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newInviteUserResponse(summary, slots))
+}
+
+func newInviteUserResponse(summary application.InviteSummary, slots []application.PossibleSlot) InviteUserResponse {
+	return InviteUserResponse{
+		EventName:     summary.EventName,
+		VotedCount:    summary.VotedCount,
+		DeclinedCount: summary.DeclinedCount,
+		Memo:          summary.Memo,
+		PeriodStart:   summary.PeriodStart.Format(time.RFC3339),
+		PeriodEnd:     summary.PeriodEnd.Format(time.RFC3339),
+		DurationMin:   summary.DurationMin,
+		PossibleDate:  newPossibleDates(slots),
 	}
+}
+
+func newPossibleDates(slots []application.PossibleSlot) []possibleDate {
+	var dates []possibleDate
 	for _, s := range slots {
-		res.PossibleDate = append(res.PossibleDate, possibleDate{
+		dates = append(dates, possibleDate{
 			ID:                   s.ID,
 			Date:                 s.Date,
 			PeriodStart:          s.PeriodStart.Format(time.RFC3339),
 			PeriodEnd:            s.PeriodEnd.Format(time.RFC3339),
 			ParticipateMemberNum: s.ParticipateMemberNum,
+			AvailableMembers:     s.AvailableMembers,
 		})
 	}
-
-	c.JSON(http.StatusOK, res)
+	return dates
 }
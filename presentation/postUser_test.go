@@ -0,0 +1,109 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/middleware"
+	"adjuSche-back-end/servise"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestInviteUserID(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+	gin.SetMode(gin.TestMode)
+	session, _, err := servise.IssueSessionToken("session-user", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		session     string
+		bodyUserID  string
+		wantUserID  string
+		wantSession bool
+	}{
+		{"legacy body userId", "", "body-user", "body-user", false},
+		{"anonymous", "", "", "", false},
+		{"session", session, "", "session-user", true},
+		{"session wins over body", session, "body-user", "session-user", true},
+		{"invalid session falls back to body", "forged.token", "body-user", "body-user", false},
+	}
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/invite", nil)
+		if tt.session != "" {
+			c.Request.Header.Set(servise.SessionHeader, tt.session)
+		}
+		userID, sessionUser := inviteUserID(c, InviteUserRequest{UserID: tt.bodyUserID})
+		if userID != tt.wantUserID || sessionUser != tt.wantSession {
+			t.Errorf("%s: inviteUserID = %q, %v, want %q, %v", tt.name, userID, sessionUser, tt.wantUserID, tt.wantSession)
+		}
+		if len(c.Errors) != 0 {
+			t.Errorf("%s: unexpected errors %v", tt.name, c.Errors)
+		}
+	}
+}
+
+// useFakeInviteSummary は候補日時の集計を、eventID を記録して固定の結果を返す実装に差し替える
+func useFakeInviteSummary(t *testing.T) *[]int64 {
+	t.Helper()
+	var called []int64
+	orig := getInviteSummary
+	t.Cleanup(func() { getInviteSummary = orig })
+	getInviteSummary = func(_ context.Context, eventID int64) (application.InviteSummary, []application.PossibleSlot, error) {
+		called = append(called, eventID)
+		start := time.Date(2026, 11, 2, 10, 0, 0, 0, time.UTC)
+		return application.InviteSummary{EventName: "定例会", VotedCount: 2, PeriodStart: start, PeriodEnd: start.AddDate(0, 0, 7), DurationMin: 60},
+			[]application.PossibleSlot{{ID: 1, PeriodStart: start, PeriodEnd: start.Add(time.Hour), ParticipateMemberNum: 2, AvailableMembers: []string{"佐藤", "鈴木"}}},
+			nil
+	}
+	return &called
+}
+
+func getInvite(path string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorHandler())
+	r.GET("/events/:id/invite", GetInvite)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestGetInviteWithoutToken(t *testing.T) {
+	called := useFakeInviteSummary(t)
+
+	w := getInvite("/events/42/invite")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200: %s", w.Code, w.Body.String())
+	}
+	var res InviteUserResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.EventName != "定例会" || res.VotedCount != 2 || len(res.PossibleDate) != 1 || res.PossibleDate[0].ParticipateMemberNum != 2 {
+		t.Errorf("response = %+v", res)
+	}
+	if len(*called) != 1 || (*called)[0] != 42 {
+		t.Errorf("GetInviteSummary called with %v, want [42]", *called)
+	}
+}
+
+func TestGetInviteRejectsInvalidEventID(t *testing.T) {
+	called := useFakeInviteSummary(t)
+
+	if w := getInvite("/events/abc/invite"); w.Code != http.StatusBadRequest {
+		t.Errorf("got %d, want 400: %s", w.Code, w.Body.String())
+	}
+	if len(*called) != 0 {
+		t.Errorf("GetInviteSummary called with %v for an invalid event id", *called)
+	}
+}
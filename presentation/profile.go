@@ -0,0 +1,83 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileResponse struct {
+	UserID      string `json:"userId"`
+	DisplayName string `json:"displayName"`
+	AvatarURL   string `json:"avatarUrl,omitempty"`
+	Timezone    string `json:"timezone"`
+	WorkStart   string `json:"workStart,omitempty"` // HH:MM
+	WorkEnd     string `json:"workEnd,omitempty"`
+}
+
+type UpdateProfileRequest struct {
+	DisplayName *string `json:"displayName"`
+	AvatarURL   *string `json:"avatarUrl"`
+	Timezone    *string `json:"timezone"`
+	WorkStart   *string `json:"workStart"`
+	WorkEnd     *string `json:"workEnd"`
+}
+
+// GetMyProfile はセッションのユーザーのプロフィールを返す
+func GetMyProfile(c *gin.Context) {
+	userID, err := servise.ExtractSessionUserID(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return
+	}
+
+	u, err := application.GetUserProfile(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newProfileResponse(u))
+}
+
+// UpdateMyProfile はセッションのユーザーのプロフィールを更新する（指定した項目のみ）
+func UpdateMyProfile(c *gin.Context) {
+	userID, err := servise.ExtractSessionUserID(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindJSONError(err))
+		return
+	}
+
+	u, err := application.UpdateUserProfile(c.Request.Context(), application.UpdateUserProfileInput{
+		UserID:      userID,
+		DisplayName: req.DisplayName,
+		AvatarURL:   req.AvatarURL,
+		Timezone:    req.Timezone,
+		WorkStart:   req.WorkStart,
+		WorkEnd:     req.WorkEnd,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusOK, newProfileResponse(u))
+}
+
+func newProfileResponse(u repository.Users) ProfileResponse {
+	return ProfileResponse{
+		UserID:      u.ID,
+		DisplayName: u.DisplayName,
+		AvatarURL:   u.AvatarURL,
+		Timezone:    u.Timezone,
+		WorkStart:   u.WorkStart.String,
+		WorkEnd:     u.WorkEnd.String,
+	}
+}
@@ -0,0 +1,181 @@
+package presentation
+
+import (
+	"adjuSche-back-end/application"
+	"adjuSche-back-end/domain"
+	"adjuSche-back-end/repository"
+	"adjuSche-back-end/servise"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidWebhookID = domain.BadRequest("invalid_webhook_id", "Webhook の ID は数値で指定してください")
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"` // 省略時はすべての種類を通知する
+}
+
+type WebhookResponse struct {
+	ID        string   `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Secret    string   `json:"secret,omitempty"` // 登録時のみ返す
+	CreatedAt string   `json:"createdAt"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             string `json:"id"`
+	WebhookID      string `json:"webhookId"`
+	Event          string `json:"event"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	ResponseStatus int    `json:"responseStatus,omitempty"`
+	LastError      string `json:"lastError,omitempty"`
+	Payload        string `json:"payload"`
+	CreatedAt      string `json:"createdAt"`
+	DeliveredAt    string `json:"deliveredAt,omitempty"`
+}
+
+// CreateWebhook はセッションのユーザー（主催者）の Webhook を登録する
+// 署名の鍵 (secret) はこのレスポンスでのみ返すため、受信側で控えてもらう
+func CreateWebhook(c *gin.Context) {
+	userID, err := servise.ExtractSessionUserID(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(bindJSONError(err))
+		return
+	}
+
+	sub, err := application.CreateWebhookSubscription(c.Request.Context(), application.CreateWebhookInput{
+		UserID:     userID,
+		URL:        req.URL,
+		EventTypes: req.Events,
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	res := newWebhookResponse(*sub)
+	res.Secret = sub.Secret
+	c.JSON(http.StatusCreated, res)
+}
+
+// GetWebhooks はセッションのユーザーの Webhook の一覧を返す
+func GetWebhooks(c *gin.Context) {
+	userID, err := servise.ExtractSessionUserID(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return
+	}
+
+	subs, err := application.ListWebhookSubscriptions(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	res := make([]WebhookResponse, 0, len(subs))
+	for _, sub := range subs {
+		res = append(res, newWebhookResponse(sub))
+	}
+	c.JSON(http.StatusOK, gin.H{"webhooks": res})
+}
+
+// DeleteWebhook は Webhook を削除する（登録したユーザーのみ）
+func DeleteWebhook(c *gin.Context) {
+	userID, id, ok := bindWebhookRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := application.DeleteWebhookSubscription(c.Request.Context(), userID, id); err != nil {
+		c.Error(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries は Webhook の送信履歴を新しい順に返す（登録したユーザーのみ）
+func GetWebhookDeliveries(c *gin.Context) {
+	userID, id, ok := bindWebhookRequest(c)
+	if !ok {
+		return
+	}
+
+	ds, err := application.ListWebhookDeliveries(c.Request.Context(), userID, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	res := make([]WebhookDeliveryResponse, 0, len(ds))
+	for _, d := range ds {
+		res = append(res, newWebhookDeliveryResponse(d))
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries": res})
+}
+
+// ReplayWebhookDelivery は送信履歴と同じ内容を送り直す。送信は非同期のため 202 を返す
+func ReplayWebhookDelivery(c *gin.Context) {
+	userID, id, ok := bindWebhookRequest(c)
+	if !ok {
+		return
+	}
+
+	d, err := application.ReplayWebhookDelivery(c.Request.Context(), userID, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	c.JSON(http.StatusAccepted, newWebhookDeliveryResponse(*d))
+}
+
+// bindWebhookRequest はセッションのユーザーIDと URL の :id を取り出す
+func bindWebhookRequest(c *gin.Context) (string, int64, bool) {
+	userID, err := servise.ExtractSessionUserID(c)
+	if err != nil {
+		c.Error(errLoginRequired.Wrap(err))
+		return "", 0, false
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errInvalidWebhookID.Wrap(err))
+		return "", 0, false
+	}
+	return userID, id, true
+}
+
+func newWebhookResponse(sub repository.WebhookSubscription) WebhookResponse {
+	return WebhookResponse{
+		ID:        strconv.FormatInt(sub.ID, 10),
+		URL:       sub.URL,
+		Events:    application.WebhookSubscriptionEventTypes(sub),
+		CreatedAt: sub.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func newWebhookDeliveryResponse(d repository.WebhookDelivery) WebhookDeliveryResponse {
+	res := WebhookDeliveryResponse{
+		ID:             strconv.FormatInt(d.ID, 10),
+		WebhookID:      strconv.FormatInt(d.SubscriptionID, 10),
+		Event:          d.EventType,
+		Status:         application.WebhookDeliveryStatusName(d.Status),
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError.String,
+		Payload:        d.Payload,
+		CreatedAt:      d.CreatedAt.Format(time.RFC3339),
+	}
+	if d.DeliveredAt.Valid {
+		res.DeliveredAt = d.DeliveredAt.Time.Format(time.RFC3339)
+	}
+	return res
+}
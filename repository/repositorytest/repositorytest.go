@@ -0,0 +1,37 @@
+// Package repositorytest はリポジトリを使う処理のテストで、データベースの代わりに sqlmock を使わせる
+package repositorytest
+
+import (
+	"adjuSche-back-end/repository"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// UseMock はテストの間 repository.NewSupabaseRepository が sqlmock につながったリポジトリを返すようにする
+// テストの終わりに、期待したクエリがすべて実行されたことを確かめる
+func UseMock(t *testing.T) sqlmock.Sqlmock {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open gorm with sqlmock: %v", err)
+	}
+	restore := repository.UseConnection(db)
+	t.Cleanup(func() {
+		restore()
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet database expectations: %v", err)
+		}
+		sqlDB.Close()
+	})
+	return mock
+}
@@ -1,16 +1,21 @@
 package repository
 
 import (
+	"adjuSche-back-end/domain"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Event は Events テーブルのレコードを表します
@@ -21,6 +26,8 @@ type Events struct {
 	Note             sql.NullString `json:"note"`
 	ParticipantCount int64          `json:"participant_count"`
 	Status           int64          `json:"status"`
+	DecidedStart     sql.NullTime   `json:"decided_start"` // 確定した日時（未確定なら NULL）
+	DecidedEnd       sql.NullTime   `json:"decided_end"`
 	CreatedAt        time.Time      `json:"created_at"`
 	UpdatedAt        time.Time      `json:"updated_at"`
 }
@@ -53,6 +60,9 @@ type EventParticipant struct {
 	UserID   string         `json:"user_id" gorm:"type:uuid"` // uuid型に修正
 	Status   int8           `json:"status"`
 	JoinedAt sql.NullString `json:"joined_at"` // text型に変更
+
+	DeclineReason sql.NullString `json:"decline_reason"` // 辞退・参加取り消しの理由（任意）
+	DeclinedAt    sql.NullTime   `json:"declined_at"`
 }
 
 func (EventParticipant) TableName() string {
@@ -84,10 +94,173 @@ type Link struct {
 	ExpiredAt time.Time `json:"expired_at"`
 }
 
+// LineAccountLink は LineAccountLinks テーブルのレコードを表します（LINE userId とアプリのユーザーの対応）
+type LineAccountLink struct {
+	ID         int64     `json:"id" gorm:"primaryKey"`
+	LineUserID string    `json:"line_user_id"`
+	UserID     string    `json:"user_id" gorm:"type:uuid"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (LineAccountLink) TableName() string {
+	return "LineAccountLinks"
+}
+
+// LineLinkNonce は LineLinkNonces テーブルのレコードを表します（アカウント連携中の nonce）
+type LineLinkNonce struct {
+	ID        int64     `json:"id" gorm:"primaryKey"`
+	Nonce     string    `json:"nonce"`
+	UserID    string    `json:"user_id" gorm:"type:uuid"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+func (LineLinkNonce) TableName() string {
+	return "LineLinkNonces"
+}
+
+// Users は Users テーブルのレコードを表します（ユーザーのプロフィール）
+// ID は Availabilities などの user_id と同じ UUID。表示名・アイコンは Google / LINE のプロフィールから補完します
+type Users struct {
+	ID          string         `json:"id" gorm:"primaryKey;type:uuid"`
+	DisplayName string         `json:"display_name"`
+	AvatarURL   string         `json:"avatar_url"`
+	Timezone    string         `json:"timezone"`   // IANA のタイムゾーン名（例: Asia/Tokyo）
+	WorkStart   sql.NullString `json:"work_start"` // 勤務時間の開始 (HH:MM)。未設定なら NULL
+	WorkEnd     sql.NullString `json:"work_end"`
+	GoogleID    sql.NullString `json:"google_id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+func (Users) TableName() string {
+	return "Users"
+}
+
+// AvailabilityRule は AvailabilityRules テーブルのレコードを表します（ユーザーの毎週繰り返す空いている・空いていない時間帯）
+type AvailabilityRule struct {
+	ID        int64     `json:"id" gorm:"primaryKey"`
+	UserID    string    `json:"user_id" gorm:"type:uuid"`
+	Weekday   int8      `json:"weekday"`    // 0: 日曜 〜 6: 土曜
+	StartTime string    `json:"start_time"` // HH:MM
+	EndTime   string    `json:"end_time"`   // HH:MM（24:00 は日付の終わり）
+	Kind      int8      `json:"kind"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (AvailabilityRule) TableName() string {
+	return "AvailabilityRules"
+}
+
+// EventGuest は EventGuests テーブルのレコードを表します（アカウントを持たずに招待 URL から参加したゲスト）
+// UserID はゲストごとに発行する UUID で、EventParticipants / Availabilities ではログインユーザーと同じように扱います
+type EventGuest struct {
+	ID          int64          `json:"id" gorm:"primaryKey"`
+	EventID     int64          `json:"event_id"`
+	UserID      string         `json:"user_id" gorm:"type:uuid"`
+	DisplayName string         `json:"display_name"`
+	Email       sql.NullString `json:"email"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+func (EventGuest) TableName() string {
+	return "EventGuests"
+}
+
+// AvailabilityPreview は AvailabilityPreviews テーブルのレコードを表します（保存前に確認してもらう空き時間のプレビュー）
+// 確定前の下書きで、確定すると削除します。有効期限の切れたものは次のプレビューの作成時にまとめて削除します
+type AvailabilityPreview struct {
+	ID        int64     `json:"id" gorm:"primaryKey"`
+	Token     string    `json:"token"`
+	EventID   int64     `json:"event_id"`
+	UserID    string    `json:"user_id" gorm:"type:uuid"`
+	Sourse    int8      `json:"sourse"`
+	Intervals string    `json:"intervals"` // 空き時間の JSON（[{"start": RFC3339, "end": RFC3339}]）
+	CreatedAt time.Time `json:"created_at"`
+	ExpiredAt time.Time `json:"expired_at"`
+}
+
+func (AvailabilityPreview) TableName() string {
+	return "AvailabilityPreviews"
+}
+
+// CalendarFeedToken は CalendarFeedTokens テーブルのレコードを表します（ユーザーごとの .ics 購読フィードの秘密トークン）
+type CalendarFeedToken struct {
+	ID        int64     `json:"id" gorm:"primaryKey"`
+	UserID    string    `json:"user_id" gorm:"type:uuid"`
+	Token     string    `json:"token"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (CalendarFeedToken) TableName() string {
+	return "CalendarFeedTokens"
+}
+
+// WebhookSubscription は WebhookSubscriptions テーブルのレコードを表します（主催者ごとの Webhook の送信先）
+type WebhookSubscription struct {
+	ID         int64     `json:"id" gorm:"primaryKey"`
+	HostUserID string    `json:"host_user_id" gorm:"type:uuid"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret"`      // 署名 (HMAC-SHA256) の鍵
+	EventTypes string    `json:"event_types"` // 通知するイベントの種類（カンマ区切り、空ならすべて）
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "WebhookSubscriptions"
+}
+
+// WebhookDelivery は WebhookDeliveries テーブルのレコードを表します（Webhook の送信履歴）
+type WebhookDelivery struct {
+	ID             int64          `json:"id" gorm:"primaryKey"`
+	SubscriptionID int64          `json:"subscription_id"`
+	EventType      string         `json:"event_type"`
+	Payload        string         `json:"payload"` // 送信した JSON（再送時もそのまま使う）
+	Status         int8           `json:"status"`
+	Attempts       int            `json:"attempts"`
+	ResponseStatus int            `json:"response_status"` // 最後の試行の HTTP ステータス（接続できなかった場合は 0）
+	LastError      sql.NullString `json:"last_error"`
+	CreatedAt      time.Time      `json:"created_at"`
+	DeliveredAt    sql.NullTime   `json:"delivered_at"`
+	NextAttemptAt  sql.NullTime   `json:"next_attempt_at"` // 送信中の場合、この時刻を過ぎても結果が無ければ送信が中断されたとみなす
+}
+
+func (WebhookDelivery) TableName() string {
+	return "WebhookDeliveries"
+}
+
 const (
-	EventStatusDraft  = 0
-	EventStatusOpen   = 1
-	EventStatusClosed = 2
+	EventStatusDraft    = 0
+	EventStatusOpen     = 1
+	EventStatusClosed   = 2
+	EventStatusCanceled = 3
+)
+
+// Availability の sourse の値
+const (
+	AvailabilitySourceGoogleCalendar = 0
+	AvailabilitySourceManual         = 1
+	AvailabilitySourceICS            = 2 // アップロードされた .ics から取り込んだもの
+	AvailabilitySourceCalDAV         = 3 // CalDAV のカレンダーから取り込んだもの
+	AvailabilitySourceOutlook        = 4 // Microsoft 365 / Outlook のカレンダーから取り込んだもの
+)
+
+// CalendarAvailabilitySources はカレンダーから求めた空き時間の sourse
+// 空き時間は 1 つのカレンダーから求めるため、取り込み直すとこれらをまとめて置き換える（手入力の分は残す）
+var CalendarAvailabilitySources = []int8{AvailabilitySourceGoogleCalendar, AvailabilitySourceICS, AvailabilitySourceCalDAV, AvailabilitySourceOutlook}
+
+// AvailabilityRule の kind の値
+const (
+	AvailabilityRuleKindUnavailable = 0 // 空いていない時間帯
+	AvailabilityRuleKindAvailable   = 1 // 空いている時間帯（この曜日はこの時間帯だけ空いている）
+)
+
+// WebhookDelivery の status の値
+const (
+	WebhookDeliveryStatusPending   = 0
+	WebhookDeliveryStatusSucceeded = 1
+	WebhookDeliveryStatusFailed    = 2
 )
 
 const (
@@ -96,39 +269,91 @@ const (
 	ParticipantStatusDeclined = 2
 )
 
+// レコードが見つからない場合に返すエラー（原因の gorm.ErrRecordNotFound を保持します）
+var (
+	ErrEventNotFound           = domain.NotFound("event_not_found", "イベントが見つかりません")
+	ErrEventConditionNotFound  = domain.NotFound("event_condition_not_found", "イベントの条件が見つかりません")
+	ErrLineAccountLinkNotFound = domain.NotFound("line_account_link_not_found", "LINEアカウントが連携されていません")
+	ErrLineLinkNonceNotFound   = domain.NotFound("line_link_nonce_not_found", "アカウント連携の有効期限が切れています")
+	ErrUserNotFound            = domain.NotFound("user_not_found", "ユーザーが見つかりません")
+	ErrParticipantNotFound     = domain.NotFound("participant_not_found", "イベントの参加者ではありません")
+	ErrGuestNotFound           = domain.NotFound("guest_not_found", "ゲストが見つかりません")
+	ErrPreviewNotFound         = domain.NotFound("preview_not_found", "空き時間のプレビューが見つからないか、有効期限が切れています")
+	ErrCalendarFeedNotFound    = domain.NotFound("calendar_feed_not_found", "カレンダーフィードが見つかりません")
+	ErrWebhookNotFound         = domain.NotFound("webhook_not_found", "Webhook が見つかりません")
+	ErrWebhookDeliveryNotFound = domain.NotFound("webhook_delivery_not_found", "Webhook の送信履歴が見つかりません")
+)
+
 // SupabaseRepositoryImpl は GORM の DB インスタンスを保持します
 type SupabaseRepositoryImpl struct {
 	db *gorm.DB
 }
 
-// NewSupabaseRepository はリポジトリを初期化します
+// NewSupabaseRepository はリポジトリを返します
+// コネクションプールはプロセスで 1 つだけ作り、すべてのリポジトリで共有します
+// リクエストや通知のたびにプールを作ると、閉じられないまま接続が溜まっていくためです
 func NewSupabaseRepository() (*SupabaseRepositoryImpl, error) {
-	db, err := connectDB()
+	db, err := sharedConnection()
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 	return &SupabaseRepositoryImpl{db: db}, nil
 }
 
-func connectDB() (*gorm.DB, error) {
+var (
+	sharedDBMu sync.Mutex
+	sharedDB   *gorm.DB
+	// openDB は共有するコネクションプールを作ります（テストで差し替える）
+	openDB = connectDB
+)
 
-	host := os.Getenv("SUPABASE_HOST")
-	port := os.Getenv("SUPABASE_PORT")
-	user := os.Getenv("SUPABASE_USER")
-	password := os.Getenv("SUPABASE_PASSWORD")
-	dbName := os.Getenv("SUPABASE_DB_NAME")
+// sharedConnection は共有のコネクションプールを返します。まだなければ作ります
+// 作れなかった場合は覚えておかず、次の呼び出しで作り直します
+func sharedConnection() (*gorm.DB, error) {
+	sharedDBMu.Lock()
+	defer sharedDBMu.Unlock()
+	if sharedDB != nil {
+		return sharedDB, nil
+	}
+	db, err := openDB()
+	if err != nil {
+		return nil, err
+	}
+	sharedDB = db
+	return sharedDB, nil
+}
 
-	log.Printf("SUPABASE_HOST: %s", host)
-	log.Printf("SUPABASE_PORT: %s", port)
-	log.Printf("SUPABASE_USER: %s", user)
-	log.Printf("SUPABASE_DB_NAME: %s", dbName)
+// UseConnection は共有のコネクションプールの代わりに db を使わせ、元に戻す関数を返します（テスト用）
+func UseConnection(db *gorm.DB) (restore func()) {
+	sharedDBMu.Lock()
+	defer sharedDBMu.Unlock()
+	prev := sharedDB
+	sharedDB = db
+	return func() {
+		sharedDBMu.Lock()
+		defer sharedDBMu.Unlock()
+		sharedDB = prev
+	}
+}
 
-	if host == "" || port == "" || user == "" || password == "" || dbName == "" {
-		return nil, fmt.Errorf("not all database connection parameters are set")
+// UnitOfWork は fn 内で行うリポジトリ操作を 1 つのトランザクションとして実行します
+// fn がエラーを返すか panic した場合はすべてロールバックされ、nil を返した場合のみコミットされます
+// fn に渡されるリポジトリはトランザクション専用のため、fn の外に持ち出さないでください
+func (r *SupabaseRepositoryImpl) UnitOfWork(ctx context.Context, fn func(tx *SupabaseRepositoryImpl) error) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&SupabaseRepositoryImpl{db: tx})
+	})
+	if err != nil {
+		return fmt.Errorf("transaction rolled back: %w", err)
 	}
+	return nil
+}
 
-	// GORM 用の DSN (Data Source Name) を作成
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=require TimeZone=Asia/Tokyo", host, user, password, dbName, port)
+func connectDB() (*gorm.DB, error) {
+	dsn, err := databaseDSN()
+	if err != nil {
+		return nil, err
+	}
 
 	// GORM を使って PostgreSQL に接続
 	db, err := gorm.Open(postgres.New(postgres.Config{
@@ -152,17 +377,25 @@ func connectDB() (*gorm.DB, error) {
 	return db, nil
 }
 
-// // CreateUser は新しいユーザーをデータベースに作成します
-// func (r *SupabaseRepositoryImpl) CreateUser(ctx context.Context, users *Users) error {
-// 	// WithContext を使用してコンテキストをGORMの操作に引き継ぎます
-// 	// Create メソッドでレコードを作成します
-// 	result := r.db.WithContext(ctx).Create(users)
-// 	if result.Error != nil {
-// 		return fmt.Errorf("failed to create user: %w", result.Error)
-// 	}
-// 	log.Printf("successfully created user with ID: %d", users.ID)
-// 	return nil
-// }
+// databaseDSN は環境変数から PostgreSQL の DSN (Data Source Name) を作成します
+func databaseDSN() (string, error) {
+	host := os.Getenv("SUPABASE_HOST")
+	port := os.Getenv("SUPABASE_PORT")
+	user := os.Getenv("SUPABASE_USER")
+	password := os.Getenv("SUPABASE_PASSWORD")
+	dbName := os.Getenv("SUPABASE_DB_NAME")
+
+	log.Printf("SUPABASE_HOST: %s", host)
+	log.Printf("SUPABASE_PORT: %s", port)
+	log.Printf("SUPABASE_USER: %s", user)
+	log.Printf("SUPABASE_DB_NAME: %s", dbName)
+
+	if host == "" || port == "" || user == "" || password == "" || dbName == "" {
+		return "", fmt.Errorf("not all database connection parameters are set")
+	}
+
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=require TimeZone=Asia/Tokyo", host, user, password, dbName, port), nil
+}
 
 func (r *SupabaseRepositoryImpl) CreateEvent(ctx context.Context, events *Events) error {
 	result := r.db.WithContext(ctx).Create(events)
@@ -186,6 +419,9 @@ func (r *SupabaseRepositoryImpl) CreateEventCondition(ctx context.Context, cond
 func (r *SupabaseRepositoryImpl) GetEventByID(ctx context.Context, eventID int64) (*Events, error) {
 	var e Events
 	if err := r.db.WithContext(ctx).First(&e, eventID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEventNotFound.Wrapf("failed to get event by id: %w", err)
+		}
 		return nil, fmt.Errorf("failed to get event by id: %w", err)
 	}
 	return &e, nil
@@ -194,11 +430,85 @@ func (r *SupabaseRepositoryImpl) GetEventByID(ctx context.Context, eventID int64
 func (r *SupabaseRepositoryImpl) GetEventConditionByEventID(ctx context.Context, eventID int64) (*EventCondition, error) {
 	var ec EventCondition
 	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Order("id DESC").First(&ec).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEventConditionNotFound.Wrapf("failed to get event condition by event_id: %w", err)
+		}
 		return nil, fmt.Errorf("failed to get event condition by event_id: %w", err)
 	}
 	return &ec, nil
 }
 
+// ListEventConditionsByEventID はイベント条件の全バージョンを新しい順に返します
+func (r *SupabaseRepositoryImpl) ListEventConditionsByEventID(ctx context.Context, eventID int64) ([]EventCondition, error) {
+	var ecs []EventCondition
+	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Order("id DESC").Find(&ecs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list event conditions by event_id: %w", err)
+	}
+	return ecs, nil
+}
+
+// UpdateEvent はイベントを更新します
+func (r *SupabaseRepositoryImpl) UpdateEvent(ctx context.Context, ev *Events) error {
+	if err := r.db.WithContext(ctx).Save(ev).Error; err != nil {
+		return fmt.Errorf("failed to update event: %w", err)
+	}
+	log.Printf("successfully updated event with ID: %d", ev.ID)
+	return nil
+}
+
+// DeleteEvent はイベントと、それに紐付く条件・参加者・ゲスト・空き時間・プレビュー・Webhook の送信履歴をまとめて削除します
+// Webhook の送信先は主催者ごとのものなので残し、このイベントについて送った履歴だけを削除します
+func (r *SupabaseRepositoryImpl) DeleteEvent(ctx context.Context, eventID int64) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("event_id = ?", eventID).Delete(&AvailabilityPreview{}).Error; err != nil {
+			return fmt.Errorf("failed to delete availability previews: %w", err)
+		}
+		if err := tx.Where("event_id = ?", eventID).Delete(&Availability{}).Error; err != nil {
+			return fmt.Errorf("failed to delete availabilities: %w", err)
+		}
+		if err := tx.Where("event_id = ?", eventID).Delete(&EventGuest{}).Error; err != nil {
+			return fmt.Errorf("failed to delete event guests: %w", err)
+		}
+		if err := tx.Where("event_id = ?", eventID).Delete(&EventParticipant{}).Error; err != nil {
+			return fmt.Errorf("failed to delete event participants: %w", err)
+		}
+		if err := tx.Where("event_id = ?", eventID).Delete(&EventCondition{}).Error; err != nil {
+			return fmt.Errorf("failed to delete event conditions: %w", err)
+		}
+		// 送信履歴にはイベントの列が無いため、送った JSON の data.eventId で探す
+		if err := tx.Where("payload::jsonb -> 'data' ->> 'eventId' = ?", strconv.FormatInt(eventID, 10)).Delete(&WebhookDelivery{}).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+		if err := tx.Delete(&Events{}, eventID).Error; err != nil {
+			return fmt.Errorf("failed to delete event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("successfully deleted event with ID: %d", eventID)
+	return nil
+}
+
+// ReplaceAvailabilitiesForEvent はイベントの空き時間を全ユーザー分まとめて置換します
+func (r *SupabaseRepositoryImpl) ReplaceAvailabilitiesForEvent(ctx context.Context, eventID int64, avs []Availability) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deleteResult := tx.Where("event_id = ?", eventID).Delete(&Availability{})
+		if deleteResult.Error != nil {
+			return fmt.Errorf("failed to delete availabilities: %w", deleteResult.Error)
+		}
+		log.Printf("削除されたレコード数: %d", deleteResult.RowsAffected)
+
+		if len(avs) > 0 {
+			if err := tx.Omit("ID").Create(&avs).Error; err != nil {
+				return fmt.Errorf("failed to create availabilities: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
 func (r *SupabaseRepositoryImpl) ListAvailabilitiesByEventID(ctx context.Context, eventID int64) ([]Availability, error) {
 	var avs []Availability
 	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Find(&avs).Error; err != nil {
@@ -216,50 +526,102 @@ func (r *SupabaseRepositoryImpl) CountDistinctAvailabilityUsersByEventID(ctx con
 	return res.Cnt, nil
 }
 
-// // GetUserByGoogleID は google_id から Users レコードを取得する
-// func (r *SupabaseRepositoryImpl) GetUserByGoogleID(ctx context.Context, googleID string) (*Users, error) {
-// 	var u Users
-// 	if err := r.db.WithContext(ctx).Where("google_id = ?", googleID).First(&u).Error; err != nil {
-// 		return nil, fmt.Errorf("failed to get user by google_id: %w", err)
-// 	}
-// 	return &u, nil
-// }
+// EventListFilter は ListEventsByUserID の絞り込み条件を表します
+type EventListFilter struct {
+	UserID string
+	Status *int64 // nil の場合はすべてのステータス
+	Limit  int
+	Offset int
+}
 
-// ReplaceUserAvailabilitiesForEvent は指定 event_id×user_id の既存データを削除し、与えられたレコードで置換する
-func (r *SupabaseRepositoryImpl) ReplaceUserAvailabilitiesForEvent(ctx context.Context, eventID int64, userID string, avs []Availability) error {
-	log.Printf("ReplaceUserAvailabilitiesForEvent: eventID=%d, userID=%s, records=%d", eventID, userID, len(avs))
+// ListEventsByUserID はユーザーが主催または参加しているイベントを作成日時の新しい順に返します
+// 戻り値の int64 はページングを考慮しない総件数です
+func (r *SupabaseRepositoryImpl) ListEventsByUserID(ctx context.Context, f EventListFilter) ([]Events, int64, error) {
+	q := r.db.WithContext(ctx).Model(&Events{}).
+		Where("(host_user_id = ? OR id IN (SELECT event_id FROM \"EventParticipants\" WHERE user_id = ?))", f.UserID, f.UserID)
+	if f.Status != nil {
+		q = q.Where("status = ?", *f.Status)
+	}
+	q = q.Session(&gorm.Session{})
 
-	tx := r.db.WithContext(ctx).Begin()
-	if tx.Error != nil {
-		return fmt.Errorf("failed to begin transaction: %w", tx.Error)
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count events by user_id: %w", err)
 	}
 
-	// 既存のレコードを削除（Google Calendar由来のデータ sourse=0 のみを削除）
-	deleteResult := tx.Where("event_id = ? AND user_id = ? AND sourse = ?", eventID, userID, 0).Delete(&Availability{})
-	if deleteResult.Error != nil {
-		_ = tx.Rollback()
-		return fmt.Errorf("failed to delete existing availabilities: %w", deleteResult.Error)
+	var evs []Events
+	if err := q.Order("created_at DESC").Order("id DESC").Limit(f.Limit).Offset(f.Offset).Find(&evs).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list events by user_id: %w", err)
 	}
-	log.Printf("削除されたレコード数: %d", deleteResult.RowsAffected)
+	return evs, total, nil
+}
 
-	// 新しいレコードを挿入
-	if len(avs) > 0 {
-		createResult := tx.Omit("ID").Create(&avs)
-		if createResult.Error != nil {
-			_ = tx.Rollback()
-			log.Printf("Create エラー: %v", createResult.Error)
-			return fmt.Errorf("failed to create availabilities: %w", createResult.Error)
-		}
-		log.Printf("挿入されたレコード数: %d", createResult.RowsAffected)
+// CountDistinctAvailabilityUsersByEventIDs は複数イベントの投票者数を event_id ごとに返します
+func (r *SupabaseRepositoryImpl) CountDistinctAvailabilityUsersByEventIDs(ctx context.Context, eventIDs []int64) (map[int64]int, error) {
+	counts := make(map[int64]int, len(eventIDs))
+	if len(eventIDs) == 0 {
+		return counts, nil
 	}
 
-	if err := tx.Commit().Error; err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	type Result struct {
+		EventID int64
+		Cnt     int
+	}
+	var rows []Result
+	if err := r.db.WithContext(ctx).Raw("SELECT event_id, COUNT(DISTINCT user_id) AS cnt FROM \"Availabilities\" WHERE event_id IN ? GROUP BY event_id", eventIDs).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count distinct users in availabilities by event_ids: %w", err)
+	}
+	for _, row := range rows {
+		counts[row.EventID] = row.Cnt
+	}
+	return counts, nil
+}
+
+// ReplaceUserAvailabilitiesForEvent は指定 event_id×user_id の既存データを削除し、与えられたレコードで置換する
+func (r *SupabaseRepositoryImpl) ReplaceUserAvailabilitiesForEvent(ctx context.Context, eventID int64, userID string, avs []Availability) error {
+	// カレンダー由来のデータのみを置き換え、手入力の分は残す
+	return r.ReplaceUserAvailabilitiesBySources(ctx, eventID, userID, CalendarAvailabilitySources, avs)
+}
+
+// ReplaceUserAvailabilitiesBySources はユーザーのイベントの空き時間のうち、sources の sourse のものを avs で置き換えます
+func (r *SupabaseRepositoryImpl) ReplaceUserAvailabilitiesBySources(ctx context.Context, eventID int64, userID string, sources []int8, avs []Availability) error {
+	log.Printf("ReplaceUserAvailabilitiesBySources: eventID=%d, userID=%s, sources=%v, records=%d", eventID, userID, sources, len(avs))
+
+	// Transaction を使うことで、UnitOfWork の内側から呼ばれた場合は SAVEPOINT としてネストされる
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 既存のレコードを削除
+		deleteResult := tx.Where("event_id = ? AND user_id = ? AND sourse IN ?", eventID, userID, sources).Delete(&Availability{})
+		if deleteResult.Error != nil {
+			return fmt.Errorf("failed to delete existing availabilities: %w", deleteResult.Error)
+		}
+		log.Printf("削除されたレコード数: %d", deleteResult.RowsAffected)
+
+		// 新しいレコードを挿入
+		if len(avs) > 0 {
+			createResult := tx.Omit("ID").Create(&avs)
+			if createResult.Error != nil {
+				log.Printf("Create エラー: %v", createResult.Error)
+				return fmt.Errorf("failed to create availabilities: %w", createResult.Error)
+			}
+			log.Printf("挿入されたレコード数: %d", createResult.RowsAffected)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 	log.Printf("トランザクション完了")
 	return nil
 }
 
+// DeleteUserAvailabilitiesForEvent はユーザーのイベントの空き時間を、手入力の分も含めてすべて削除します
+func (r *SupabaseRepositoryImpl) DeleteUserAvailabilitiesForEvent(ctx context.Context, eventID int64, userID string) error {
+	if err := r.db.WithContext(ctx).Where("event_id = ? AND user_id = ?", eventID, userID).Delete(&Availability{}).Error; err != nil {
+		return fmt.Errorf("failed to delete user availabilities: %w", err)
+	}
+	return nil
+}
+
 // GetOrCreateEventParticipant は参加者を取得または作成します
 func (r *SupabaseRepositoryImpl) GetOrCreateEventParticipant(ctx context.Context, eventID int64, userID string) (*EventParticipant, error) {
 	// 既存の参加者レコードを検索
@@ -294,3 +656,522 @@ func (r *SupabaseRepositoryImpl) GetOrCreateEventParticipant(ctx context.Context
 	log.Printf("新しい参加者レコードを作成しました: ID=%d", newParticipant.ID)
 	return &newParticipant, nil
 }
+
+// GetUser はユーザーのプロフィールを取得します
+func (r *SupabaseRepositoryImpl) GetUser(ctx context.Context, userID string) (*Users, error) {
+	var u Users
+	if err := r.db.WithContext(ctx).Where("id = ?", userID).First(&u).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound.Wrapf("failed to get user: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return &u, nil
+}
+
+// ListUsersByIDs は userIDs のユーザーのプロフィールを返します（プロフィールのないユーザーは含みません）
+func (r *SupabaseRepositoryImpl) ListUsersByIDs(ctx context.Context, userIDs []string) ([]Users, error) {
+	if len(userIDs) == 0 {
+		return []Users{}, nil
+	}
+	var users []Users
+	if err := r.db.WithContext(ctx).Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	return users, nil
+}
+
+// SaveUser はユーザーのプロフィールを作成、または既存のものを上書きします
+func (r *SupabaseRepositoryImpl) SaveUser(ctx context.Context, u *Users) error {
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"display_name", "avatar_url", "timezone", "work_start", "work_end", "google_id", "updated_at"}),
+	}).Create(u).Error
+	if err != nil {
+		return fmt.Errorf("failed to save user: %w", err)
+	}
+	return nil
+}
+
+// ListAvailabilityRules はユーザーの繰り返しのルールを曜日・開始時刻の順に返します
+func (r *SupabaseRepositoryImpl) ListAvailabilityRules(ctx context.Context, userID string) ([]AvailabilityRule, error) {
+	var rules []AvailabilityRule
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("weekday").Order("start_time").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list availability rules: %w", err)
+	}
+	return rules, nil
+}
+
+// ReplaceAvailabilityRules はユーザーの繰り返しのルールをすべて rules で置き換えます
+func (r *SupabaseRepositoryImpl) ReplaceAvailabilityRules(ctx context.Context, userID string, rules []AvailabilityRule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&AvailabilityRule{}).Error; err != nil {
+			return fmt.Errorf("failed to delete availability rules: %w", err)
+		}
+		if len(rules) == 0 {
+			return nil
+		}
+		if err := tx.Omit("ID").Create(&rules).Error; err != nil {
+			return fmt.Errorf("failed to create availability rules: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListEventGuests はイベントのゲストを参加した順に返します
+func (r *SupabaseRepositoryImpl) ListEventGuests(ctx context.Context, eventID int64) ([]EventGuest, error) {
+	var guests []EventGuest
+	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Order("created_at").Find(&guests).Error; err != nil {
+		return nil, fmt.Errorf("failed to list event guests: %w", err)
+	}
+	return guests, nil
+}
+
+// CreateEventGuest はゲストを保存します
+func (r *SupabaseRepositoryImpl) CreateEventGuest(ctx context.Context, g *EventGuest) error {
+	if err := r.db.WithContext(ctx).Omit("ID").Create(g).Error; err != nil {
+		return fmt.Errorf("failed to create event guest: %w", err)
+	}
+	log.Printf("ゲストを作成しました: eventID=%d, userID=%s", g.EventID, g.UserID)
+	return nil
+}
+
+// GetEventGuest はイベントのゲストを取得します
+func (r *SupabaseRepositoryImpl) GetEventGuest(ctx context.Context, eventID int64, userID string) (*EventGuest, error) {
+	var g EventGuest
+	if err := r.db.WithContext(ctx).Where("event_id = ? AND user_id = ?", eventID, userID).First(&g).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGuestNotFound.Wrapf("failed to get event guest: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get event guest: %w", err)
+	}
+	return &g, nil
+}
+
+// ListUserAvailabilitiesForEvent はユーザーのイベントの空き時間を開始日時順に返します
+func (r *SupabaseRepositoryImpl) ListUserAvailabilitiesForEvent(ctx context.Context, eventID int64, userID string) ([]Availability, error) {
+	var avs []Availability
+	if err := r.db.WithContext(ctx).Where("event_id = ? AND user_id = ?", eventID, userID).Order("available_start").Find(&avs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list user availabilities: %w", err)
+	}
+	return avs, nil
+}
+
+// GetEventParticipant はイベントの参加者を取得します
+func (r *SupabaseRepositoryImpl) GetEventParticipant(ctx context.Context, eventID int64, userID string) (*EventParticipant, error) {
+	var p EventParticipant
+	if err := r.db.WithContext(ctx).Where("event_id = ? AND user_id = ?", eventID, userID).First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrParticipantNotFound.Wrapf("failed to get event participant: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get event participant: %w", err)
+	}
+	return &p, nil
+}
+
+// UpdateEventParticipant は参加者のステータスと辞退の理由を更新します
+func (r *SupabaseRepositoryImpl) UpdateEventParticipant(ctx context.Context, p *EventParticipant) error {
+	if err := r.db.WithContext(ctx).Save(p).Error; err != nil {
+		return fmt.Errorf("failed to update event participant: %w", err)
+	}
+	return nil
+}
+
+// CountEventParticipantsByStatus はイベントごとに、指定したステータスの参加者数を返します
+func (r *SupabaseRepositoryImpl) CountEventParticipantsByStatus(ctx context.Context, eventIDs []int64, status int8) (map[int64]int, error) {
+	counts := make(map[int64]int, len(eventIDs))
+	if len(eventIDs) == 0 {
+		return counts, nil
+	}
+
+	type Result struct {
+		EventID int64
+		Cnt     int
+	}
+	var rows []Result
+	err := r.db.WithContext(ctx).Model(&EventParticipant{}).
+		Select("event_id, COUNT(*) AS cnt").
+		Where("event_id IN ? AND status = ?", eventIDs, status).
+		Group("event_id").Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count event participants by status: %w", err)
+	}
+	for _, row := range rows {
+		counts[row.EventID] = row.Cnt
+	}
+	return counts, nil
+}
+
+// ListEventParticipantsByEventID はイベントの参加者を登録順に返します
+func (r *SupabaseRepositoryImpl) ListEventParticipantsByEventID(ctx context.Context, eventID int64) ([]EventParticipant, error) {
+	var ps []EventParticipant
+	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Order("id").Find(&ps).Error; err != nil {
+		return nil, fmt.Errorf("failed to list event participants by event_id: %w", err)
+	}
+	return ps, nil
+}
+
+// ListEventParticipantsByEventIDs は複数イベントの参加者を event_id ごとに返します
+func (r *SupabaseRepositoryImpl) ListEventParticipantsByEventIDs(ctx context.Context, eventIDs []int64) (map[int64][]EventParticipant, error) {
+	byEvent := make(map[int64][]EventParticipant, len(eventIDs))
+	if len(eventIDs) == 0 {
+		return byEvent, nil
+	}
+
+	var ps []EventParticipant
+	if err := r.db.WithContext(ctx).Where("event_id IN ?", eventIDs).Order("id").Find(&ps).Error; err != nil {
+		return nil, fmt.Errorf("failed to list event participants by event_ids: %w", err)
+	}
+	for _, p := range ps {
+		byEvent[p.EventID] = append(byEvent[p.EventID], p)
+	}
+	return byEvent, nil
+}
+
+// ListDecidedEventsByUserID はユーザーが主催または参加しているイベントのうち、日時が確定したものを確定日時の順に返します
+func (r *SupabaseRepositoryImpl) ListDecidedEventsByUserID(ctx context.Context, userID string) ([]Events, error) {
+	var evs []Events
+	err := r.db.WithContext(ctx).
+		Where("(host_user_id = ? OR id IN (SELECT event_id FROM \"EventParticipants\" WHERE user_id = ?))", userID, userID).
+		Where("decided_start IS NOT NULL AND decided_end IS NOT NULL").
+		Order("decided_start").Order("id").
+		Find(&evs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list decided events by user_id: %w", err)
+	}
+	return evs, nil
+}
+
+// SaveCalendarFeedToken はユーザーのフィードのトークンを保存します（既存のトークンは置き換えて無効にする）
+func (r *SupabaseRepositoryImpl) SaveCalendarFeedToken(ctx context.Context, userID, token string) (*CalendarFeedToken, error) {
+	ft := CalendarFeedToken{
+		UserID:    userID,
+		Token:     token,
+		CreatedAt: time.Now(),
+	}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&CalendarFeedToken{}).Error; err != nil {
+			return fmt.Errorf("failed to delete calendar feed token: %w", err)
+		}
+		if err := tx.Omit("ID").Create(&ft).Error; err != nil {
+			return fmt.Errorf("failed to create calendar feed token: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("カレンダーフィードのトークンを発行しました: userID=%s", userID)
+	return &ft, nil
+}
+
+// GetCalendarFeedTokenByUserID はユーザーのフィードのトークンを取得します
+func (r *SupabaseRepositoryImpl) GetCalendarFeedTokenByUserID(ctx context.Context, userID string) (*CalendarFeedToken, error) {
+	var ft CalendarFeedToken
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&ft).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCalendarFeedNotFound.Wrapf("failed to get calendar feed token by user_id: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get calendar feed token by user_id: %w", err)
+	}
+	return &ft, nil
+}
+
+// GetCalendarFeedTokenByToken はトークンからフィードの持ち主を取得します
+func (r *SupabaseRepositoryImpl) GetCalendarFeedTokenByToken(ctx context.Context, token string) (*CalendarFeedToken, error) {
+	var ft CalendarFeedToken
+	if err := r.db.WithContext(ctx).Where("token = ?", token).First(&ft).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCalendarFeedNotFound.Wrapf("failed to get calendar feed token: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get calendar feed token: %w", err)
+	}
+	return &ft, nil
+}
+
+// CreateWebhookSubscription は Webhook の送信先を登録します
+func (r *SupabaseRepositoryImpl) CreateWebhookSubscription(ctx context.Context, sub *WebhookSubscription) error {
+	if err := r.db.WithContext(ctx).Omit("ID").Create(sub).Error; err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+	log.Printf("Webhook を登録しました: id=%d, hostUserID=%s", sub.ID, sub.HostUserID)
+	return nil
+}
+
+// ListWebhookSubscriptionsByHostUserID は主催者の Webhook の送信先を登録順に取得します
+func (r *SupabaseRepositoryImpl) ListWebhookSubscriptionsByHostUserID(ctx context.Context, hostUserID string) ([]WebhookSubscription, error) {
+	var subs []WebhookSubscription
+	if err := r.db.WithContext(ctx).Where("host_user_id = ?", hostUserID).Order("id").Find(&subs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions by host_user_id: %w", err)
+	}
+	return subs, nil
+}
+
+// GetWebhookSubscriptionByID は Webhook の送信先を取得します
+func (r *SupabaseRepositoryImpl) GetWebhookSubscriptionByID(ctx context.Context, id int64) (*WebhookSubscription, error) {
+	var sub WebhookSubscription
+	if err := r.db.WithContext(ctx).First(&sub, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound.Wrapf("failed to get webhook subscription: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return &sub, nil
+}
+
+// DeleteWebhookSubscription は Webhook の送信先と送信履歴を削除します
+func (r *SupabaseRepositoryImpl) DeleteWebhookSubscription(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", id).Delete(&WebhookDelivery{}).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+		if err := tx.Delete(&WebhookSubscription{}, id).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook subscription: %w", err)
+		}
+		return nil
+	})
+}
+
+// CreateWebhookDelivery は Webhook の送信履歴を作成します
+func (r *SupabaseRepositoryImpl) CreateWebhookDelivery(ctx context.Context, d *WebhookDelivery) error {
+	if err := r.db.WithContext(ctx).Omit("ID").Create(d).Error; err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// UpdateWebhookDelivery は Webhook の送信結果を保存します
+func (r *SupabaseRepositoryImpl) UpdateWebhookDelivery(ctx context.Context, d *WebhookDelivery) error {
+	err := r.db.WithContext(ctx).Model(&WebhookDelivery{}).Where("id = ?", d.ID).Updates(map[string]any{
+		"status":          d.Status,
+		"attempts":        d.Attempts,
+		"response_status": d.ResponseStatus,
+		"last_error":      d.LastError,
+		"delivered_at":    d.DeliveredAt,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookDeliveryByID は Webhook の送信履歴を取得します
+func (r *SupabaseRepositoryImpl) GetWebhookDeliveryByID(ctx context.Context, id int64) (*WebhookDelivery, error) {
+	var d WebhookDelivery
+	if err := r.db.WithContext(ctx).First(&d, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookDeliveryNotFound.Wrapf("failed to get webhook delivery: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	return &d, nil
+}
+
+// ListWebhookDeliveriesBySubscriptionID は送信先の送信履歴を新しい順に最大 limit 件取得します
+func (r *SupabaseRepositoryImpl) ListWebhookDeliveriesBySubscriptionID(ctx context.Context, subscriptionID int64, limit int) ([]WebhookDelivery, error) {
+	var ds []WebhookDelivery
+	err := r.db.WithContext(ctx).Where("subscription_id = ?", subscriptionID).
+		Order("id DESC").Limit(limit).Find(&ds).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries by subscription_id: %w", err)
+	}
+	return ds, nil
+}
+
+// ListStalledWebhookDeliveries は送信中のまま next_attempt_at を過ぎた送信履歴を古い順に最大 limit 件取得します
+func (r *SupabaseRepositoryImpl) ListStalledWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]WebhookDelivery, error) {
+	var ds []WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)", WebhookDeliveryStatusPending, now).
+		Order("id").Limit(limit).Find(&ds).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stalled webhook deliveries: %w", err)
+	}
+	return ds, nil
+}
+
+// ClaimWebhookDelivery は送信中のまま next_attempt_at を過ぎた送信履歴の next_attempt_at を until まで延ばし、送信を引き継ぎます
+// 他のサーバーが先に引き継いだか、結果が保存されていた場合は false を返します
+func (r *SupabaseRepositoryImpl) ClaimWebhookDelivery(ctx context.Context, id int64, now, until time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).Model(&WebhookDelivery{}).
+		Where("id = ? AND status = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)", id, WebhookDeliveryStatusPending, now).
+		Update("next_attempt_at", until)
+	if res.Error != nil {
+		return false, fmt.Errorf("failed to claim webhook delivery: %w", res.Error)
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// CreateLineLinkNonce はアカウント連携用の nonce を保存します
+func (r *SupabaseRepositoryImpl) CreateLineLinkNonce(ctx context.Context, n *LineLinkNonce) error {
+	if err := r.db.WithContext(ctx).Omit("ID").Create(n).Error; err != nil {
+		return fmt.Errorf("failed to create line link nonce: %w", err)
+	}
+	return nil
+}
+
+// ConsumeLineLinkNonce は有効期限内の nonce を取得して削除します（nonce は一度しか使えない）
+func (r *SupabaseRepositoryImpl) ConsumeLineLinkNonce(ctx context.Context, nonce string, now time.Time) (*LineLinkNonce, error) {
+	var n LineLinkNonce
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("nonce = ? AND expired_at > ?", nonce, now).First(&n).Error; err != nil {
+			return err
+		}
+		return tx.Where("nonce = ?", nonce).Delete(&LineLinkNonce{}).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLineLinkNonceNotFound.Wrapf("failed to consume line link nonce: %w", err)
+		}
+		return nil, fmt.Errorf("failed to consume line link nonce: %w", err)
+	}
+	return &n, nil
+}
+
+// CreateAvailabilityPreview は空き時間のプレビューを保存します
+// 同じイベント・ユーザーの以前のプレビューは新しいもので置き換え、有効期限の切れたプレビューは削除します
+func (r *SupabaseRepositoryImpl) CreateAvailabilityPreview(ctx context.Context, p *AvailabilityPreview) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("(event_id = ? AND user_id = ?) OR expired_at <= ?", p.EventID, p.UserID, p.CreatedAt).Delete(&AvailabilityPreview{}).Error; err != nil {
+			return err
+		}
+		return tx.Omit("ID").Create(p).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create availability preview: %w", err)
+	}
+	return nil
+}
+
+// GetAvailabilityPreview はイベント・ユーザーの有効期限内のプレビューを取得します
+func (r *SupabaseRepositoryImpl) GetAvailabilityPreview(ctx context.Context, token string, eventID int64, userID string, now time.Time) (*AvailabilityPreview, error) {
+	var p AvailabilityPreview
+	err := r.db.WithContext(ctx).Where("token = ? AND event_id = ? AND user_id = ? AND expired_at > ?", token, eventID, userID, now).First(&p).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPreviewNotFound.Wrapf("failed to get availability preview: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get availability preview: %w", err)
+	}
+	return &p, nil
+}
+
+// DeleteAvailabilityPreview はプレビューを削除します
+// すでに削除されていた場合（同じプレビューを二重に確定した場合など）は ErrPreviewNotFound を返します
+func (r *SupabaseRepositoryImpl) DeleteAvailabilityPreview(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Delete(&AvailabilityPreview{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete availability preview: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrPreviewNotFound.Wrapf("availability preview %d was already deleted", id)
+	}
+	return nil
+}
+
+// UpsertLineAccountLink は LINE userId とアプリのユーザーを紐付けます（既存の紐付けは上書き）
+func (r *SupabaseRepositoryImpl) UpsertLineAccountLink(ctx context.Context, lineUserID, userID string) (*LineAccountLink, error) {
+	now := time.Now()
+	var link LineAccountLink
+	err := r.db.WithContext(ctx).Where("line_user_id = ?", lineUserID).First(&link).Error
+	if err == nil {
+		link.UserID = userID
+		link.UpdatedAt = now
+		if err := r.db.WithContext(ctx).Save(&link).Error; err != nil {
+			return nil, fmt.Errorf("failed to update line account link: %w", err)
+		}
+		log.Printf("LINE アカウント連携を更新しました: lineUserID=%s, userID=%s", lineUserID, userID)
+		return &link, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to search line account link: %w", err)
+	}
+
+	link = LineAccountLink{
+		LineUserID: lineUserID,
+		UserID:     userID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := r.db.WithContext(ctx).Omit("ID").Create(&link).Error; err != nil {
+		return nil, fmt.Errorf("failed to create line account link: %w", err)
+	}
+	log.Printf("LINE アカウント連携を作成しました: lineUserID=%s, userID=%s", lineUserID, userID)
+	return &link, nil
+}
+
+// GetLineAccountLinkByLineUserID は LINE userId から紐付け済みのユーザーを取得します
+func (r *SupabaseRepositoryImpl) GetLineAccountLinkByLineUserID(ctx context.Context, lineUserID string) (*LineAccountLink, error) {
+	var link LineAccountLink
+	if err := r.db.WithContext(ctx).Where("line_user_id = ?", lineUserID).First(&link).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLineAccountLinkNotFound.Wrapf("failed to get line account link by line_user_id: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get line account link by line_user_id: %w", err)
+	}
+	return &link, nil
+}
+
+// EventUpdatesChannel はイベントの空き時間が更新されたことを複数のサーバー間で伝える NOTIFY のチャネル名です
+const EventUpdatesChannel = "adjusche_event_updates"
+
+// NotifyEventUpdated はイベントの更新を EventUpdatesChannel に NOTIFY します
+// トランザクション内で呼び出した場合、通知はコミット時に届きます
+func (r *SupabaseRepositoryImpl) NotifyEventUpdated(ctx context.Context, eventID int64) error {
+	if err := r.db.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", EventUpdatesChannel, strconv.FormatInt(eventID, 10)).Error; err != nil {
+		return fmt.Errorf("failed to notify event update: %w", err)
+	}
+	return nil
+}
+
+// ListenEventUpdates は EventUpdatesChannel を LISTEN し、通知されたイベントIDで fn を呼び出します
+// LISTEN は接続に結び付くため、GORM のコネクションプールとは別の専用接続を使います
+// 接続が切れた場合は待ってから繋ぎ直し、ctx が終了するまで戻りません
+func ListenEventUpdates(ctx context.Context, fn func(eventID int64)) {
+	const maxBackoff = 30 * time.Second
+	backoff := time.Second
+	for ctx.Err() == nil {
+		err := listenEventUpdates(ctx, fn, func() { backoff = time.Second })
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("イベント更新の LISTEN が切断されました。%s 後に再接続します: %v", backoff, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+func listenEventUpdates(ctx context.Context, fn func(eventID int64), onConnected func()) error {
+	dsn, err := databaseDSN()
+	if err != nil {
+		return err
+	}
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return fmt.Errorf("failed to connect for listen: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+EventUpdatesChannel); err != nil {
+		return fmt.Errorf("failed to listen %s: %w", EventUpdatesChannel, err)
+	}
+	onConnected()
+	log.Printf("イベント更新の LISTEN を開始しました: channel=%s", EventUpdatesChannel)
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to wait for notification: %w", err)
+		}
+		eventID, err := strconv.ParseInt(n.Payload, 10, 64)
+		if err != nil {
+			log.Printf("不正なイベント更新の通知を無視します: payload=%q", n.Payload)
+			continue
+		}
+		fn(eventID)
+	}
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newMockRepository は sqlmock につながったリポジトリを返す
+func newMockRepository(t *testing.T) (*SupabaseRepositoryImpl, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open gorm with sqlmock: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("unmet database expectations: %v", err)
+		}
+		sqlDB.Close()
+	})
+	return &SupabaseRepositoryImpl{db: db}, mock
+}
+
+// stubOpenDB は openDB を差し替え、呼ばれた回数を返す関数を返す
+func stubOpenDB(t *testing.T, open func() (*gorm.DB, error)) func() int {
+	t.Helper()
+	var mu sync.Mutex
+	calls := 0
+	prevOpen, prevDB := openDB, sharedDB
+	openDB = func() (*gorm.DB, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return open()
+	}
+	sharedDB = nil
+	t.Cleanup(func() { openDB, sharedDB = prevOpen, prevDB })
+	return func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls
+	}
+}
+
+func TestSharedConnectionOpensOnce(t *testing.T) {
+	db := &gorm.DB{}
+	calls := stubOpenDB(t, func() (*gorm.DB, error) { return db, nil })
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			repo, err := NewSupabaseRepository()
+			if err != nil {
+				t.Errorf("NewSupabaseRepository() error = %v", err)
+				return
+			}
+			if repo.db != db {
+				t.Errorf("repository does not use the shared pool")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := calls(); got != 1 {
+		t.Errorf("openDB called %d times, want 1", got)
+	}
+}
+
+func TestSharedConnectionRetriesAfterError(t *testing.T) {
+	db := &gorm.DB{}
+	fail := true
+	calls := stubOpenDB(t, func() (*gorm.DB, error) {
+		if fail {
+			return nil, errors.New("connection refused")
+		}
+		return db, nil
+	})
+
+	if _, err := NewSupabaseRepository(); err == nil {
+		t.Fatal("NewSupabaseRepository() error = nil, want the open error")
+	}
+	fail = false
+	repo, err := NewSupabaseRepository()
+	if err != nil {
+		t.Fatalf("NewSupabaseRepository() error = %v after the database came back", err)
+	}
+	if repo.db != db {
+		t.Error("repository does not use the newly opened pool")
+	}
+	if got := calls(); got != 2 {
+		t.Errorf("openDB called %d times, want 2", got)
+	}
+}
+
+// deleteEventStatements は DeleteEvent が 1 つのトランザクションで実行する削除の順序
+var deleteEventStatements = []string{
+	`DELETE FROM "AvailabilityPreviews" WHERE event_id = \$1`,
+	`DELETE FROM "Availabilities" WHERE event_id = \$1`,
+	`DELETE FROM "EventGuests" WHERE event_id = \$1`,
+	`DELETE FROM "EventParticipants" WHERE event_id = \$1`,
+	`DELETE FROM "EventConditions" WHERE event_id = \$1`,
+	`DELETE FROM "WebhookDeliveries" WHERE payload::jsonb -> 'data' ->> 'eventId' = \$1`,
+	`DELETE FROM "Events" WHERE "Events"."id" = \$1`,
+}
+
+func TestDeleteEventDeletesRelatedRowsInOneTransaction(t *testing.T) {
+	repo, mock := newMockRepository(t)
+	mock.ExpectBegin()
+	for i, stmt := range deleteEventStatements {
+		arg := any(int64(42))
+		if i == len(deleteEventStatements)-2 {
+			arg = "42" // 送信履歴は JSON の文字列の eventId と比べる
+		}
+		mock.ExpectExec(stmt).WithArgs(arg).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+
+	if err := repo.DeleteEvent(context.Background(), 42); err != nil {
+		t.Fatalf("DeleteEvent() error = %v", err)
+	}
+}
+
+func TestDeleteEventRollsBackWhenADeleteFails(t *testing.T) {
+	repo, mock := newMockRepository(t)
+	mock.ExpectBegin()
+	for _, stmt := range deleteEventStatements[:2] {
+		mock.ExpectExec(stmt).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectExec(deleteEventStatements[2]).WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	if err := repo.DeleteEvent(context.Background(), 42); err == nil {
+		t.Fatal("DeleteEvent() error = nil, want the failed delete")
+	}
+}
+
+func TestUnitOfWorkRollsBackOnErrorAndPanic(t *testing.T) {
+	repo, mock := newMockRepository(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO "Events"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	failed := errors.New("condition failed")
+	err := repo.UnitOfWork(context.Background(), func(tx *SupabaseRepositoryImpl) error {
+		if err := tx.CreateEvent(context.Background(), &Events{HostUserID: "host"}); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Errorf("UnitOfWork() error = %v, want %v", err, failed)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("UnitOfWork() swallowed the panic")
+		}
+	}()
+	repo.UnitOfWork(context.Background(), func(tx *SupabaseRepositoryImpl) error {
+		panic("boom")
+	})
+}
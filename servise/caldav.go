@@ -0,0 +1,161 @@
+package servise
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// calDAVTimeout は CalDAV サーバーへの 1 リクエストのタイムアウト
+const calDAVTimeout = 15 * time.Second
+
+// calDAVQueryTemplate は期間内の VEVENT を取得する calendar-query (RFC 4791 7.8)
+// 繰り返しの展開はサーバーに任せず、受け取った calendar-data を ICSProvider で展開する
+const calDAVQueryTemplate = `<?xml version="1.0" encoding="utf-8" ?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop>
+    <D:getetag/>
+    <C:calendar-data/>
+  </D:prop>
+  <C:filter>
+    <C:comp-filter name="VCALENDAR">
+      <C:comp-filter name="VEVENT">
+        <C:time-range start="%s" end="%s"/>
+      </C:comp-filter>
+    </C:comp-filter>
+  </C:filter>
+</C:calendar-query>`
+
+// CalDAVConfig は CalDAV サーバー (Nextcloud, iCloud, Fastmail など) への接続情報を表す
+// CalendarURL はカレンダーコレクションの URL（例: https://cloud.example.com/remote.php/dav/calendars/<user>/personal/）
+// Password には iCloud や Fastmail で発行するアプリ用パスワードを指定する
+type CalDAVConfig struct {
+	CalendarURL string
+	Username    string
+	Password    string
+}
+
+// CalDAVProvider は CalDAV のカレンダーを CalendarProvider として扱う
+type CalDAVProvider struct {
+	config CalDAVConfig
+	client *http.Client
+	loc    *time.Location
+}
+
+// calDAVMultiStatus は REPORT の 207 Multi-Status のレスポンスを表す
+type calDAVMultiStatus struct {
+	XMLName   xml.Name `xml:"DAV: multistatus"`
+	Responses []struct {
+		Href     string `xml:"DAV: href"`
+		Propstat []struct {
+			Status string `xml:"DAV: status"`
+			Prop   struct {
+				CalendarData string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+// NewCalDAVProvider は CalDAV のカレンダーの Provider を作る。TZID の無い日時は loc の時刻として扱う
+// URL は利用者が指定するため、内部ネットワークのホストには接続しない（ErrNonPublicAddress）
+func NewCalDAVProvider(config CalDAVConfig, loc *time.Location) (*CalDAVProvider, error) {
+	u, err := url.Parse(config.CalendarURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid caldav calendar url: %q", config.CalendarURL)
+	}
+	return &CalDAVProvider{
+		config: config,
+		client: NewPublicHTTPClient(calDAVTimeout),
+		loc:    loc,
+	}, nil
+}
+
+// ListEvents は範囲 [start, end) に掛かる予定を返す
+func (p *CalDAVProvider) ListEvents(ctx context.Context, start, end time.Time) ([]*CalendarEvent, error) {
+	calendars, err := p.query(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*CalendarEvent
+	for _, cal := range calendars {
+		evs, err := cal.ListEvents(ctx, start, end)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, evs...)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].StartTime < events[j].StartTime })
+	return events, nil
+}
+
+// BusyIntervals は範囲 [start, end) に掛かる、予定が入っている区間を返す
+func (p *CalDAVProvider) BusyIntervals(ctx context.Context, start, end time.Time) ([]TimeInterval, error) {
+	calendars, err := p.query(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var busy []TimeInterval
+	for _, cal := range calendars {
+		b, err := cal.BusyIntervals(ctx, start, end)
+		if err != nil {
+			return nil, err
+		}
+		busy = append(busy, b...)
+	}
+	sort.Slice(busy, func(i, j int) bool { return busy[i].Start.Before(busy[j].Start) })
+	return busy, nil
+}
+
+// query は REPORT calendar-query で期間内の予定を含むリソースを取得し、リソースごとに解釈する
+// 解釈できないリソースはログに残して読み飛ばす
+func (p *CalDAVProvider) query(ctx context.Context, start, end time.Time) ([]*ICSProvider, error) {
+	body := fmt.Sprintf(calDAVQueryTemplate, formatICalUTC(start), formatICalUTC(end))
+	req, err := http.NewRequestWithContext(ctx, "REPORT", p.config.CalendarURL, strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create caldav request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "1")
+	if p.config.Username != "" {
+		req.SetBasicAuth(p.config.Username, p.config.Password)
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query caldav calendar: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("failed to query caldav calendar: status %d", res.StatusCode)
+	}
+
+	var ms calDAVMultiStatus
+	if err := xml.NewDecoder(io.LimitReader(res.Body, MaxICSSize)).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("failed to decode caldav response: %w", err)
+	}
+
+	var calendars []*ICSProvider
+	for _, r := range ms.Responses {
+		for _, ps := range r.Propstat {
+			if !strings.Contains(ps.Status, " 200 ") || strings.TrimSpace(ps.Prop.CalendarData) == "" {
+				continue
+			}
+			cal, err := NewICSProvider([]byte(strings.TrimSpace(ps.Prop.CalendarData)), p.loc)
+			if err != nil {
+				log.Printf("CalDAV の予定を解釈できないため読み飛ばします: %s: %v", r.Href, err)
+				continue
+			}
+			calendars = append(calendars, cal)
+		}
+	}
+	return calendars, nil
+}
@@ -0,0 +1,192 @@
+package servise
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const calDAVStubResponse = `<?xml version="1.0" encoding="utf-8"?>
+<d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">
+  <d:response>
+    <d:href>/remote.php/dav/calendars/alice/personal/standup.ics</d:href>
+    <d:propstat>
+      <d:prop>
+        <d:getetag>"1"</d:getetag>
+        <cal:calendar-data>BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Nextcloud//EN
+BEGIN:VEVENT
+UID:standup@example.com
+DTSTART;TZID=Asia/Tokyo:20261005T100000
+DTEND;TZID=Asia/Tokyo:20261005T110000
+RRULE:FREQ=WEEKLY;BYDAY=MO
+SUMMARY:定例
+END:VEVENT
+END:VCALENDAR
+</cal:calendar-data>
+      </d:prop>
+      <d:status>HTTP/1.1 200 OK</d:status>
+    </d:propstat>
+  </d:response>
+  <d:response>
+    <d:href>/remote.php/dav/calendars/alice/personal/review.ics</d:href>
+    <d:propstat>
+      <d:prop>
+        <d:getetag>"2"</d:getetag>
+        <cal:calendar-data>BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Nextcloud//EN
+BEGIN:VEVENT
+UID:review@example.com
+DTSTART:20261020T050000Z
+DTEND:20261020T063000Z
+SUMMARY:レビュー
+LOCATION:会議室A
+END:VEVENT
+END:VCALENDAR
+</cal:calendar-data>
+      </d:prop>
+      <d:status>HTTP/1.1 200 OK</d:status>
+    </d:propstat>
+  </d:response>
+  <d:response>
+    <d:href>/remote.php/dav/calendars/alice/personal/broken.ics</d:href>
+    <d:propstat>
+      <d:prop>
+        <cal:calendar-data>not a calendar</cal:calendar-data>
+      </d:prop>
+      <d:status>HTTP/1.1 200 OK</d:status>
+    </d:propstat>
+  </d:response>
+  <d:response>
+    <d:href>/remote.php/dav/calendars/alice/personal/missing.ics</d:href>
+    <d:propstat>
+      <d:prop>
+        <cal:calendar-data/>
+      </d:prop>
+      <d:status>HTTP/1.1 404 Not Found</d:status>
+    </d:propstat>
+  </d:response>
+</d:multistatus>`
+
+// newCalDAVStub は REPORT calendar-query に固定の multistatus を返す CalDAV サーバーを立てる
+// 受け取った calendar-query の本文は *query に記録する
+func newCalDAVStub(t *testing.T, query *string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "REPORT" {
+			t.Errorf("method = %s, want REPORT", r.Method)
+		}
+		if got := r.Header.Get("Depth"); got != "1" {
+			t.Errorf("Depth = %q, want 1", got)
+		}
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "alice" || pass != "app-password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		*query = string(body)
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		io.WriteString(w, calDAVStubResponse)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCalDAVProviderFreeIntervals(t *testing.T) {
+	var query string
+	srv := newCalDAVStub(t, &query)
+	provider, err := NewCalDAVProvider(CalDAVConfig{
+		CalendarURL: srv.URL + "/remote.php/dav/calendars/alice/personal/",
+		Username:    "alice",
+		Password:    "app-password",
+	}, tokyo)
+	if err != nil {
+		t.Fatalf("NewCalDAVProvider: %v", err)
+	}
+	provider.client = srv.Client() // テストサーバーはループバックで待ち受ける
+
+	start, end := jstTime(10, 19, 9, 0), jstTime(10, 21, 9, 0)
+	busy, err := provider.BusyIntervals(context.Background(), start, end)
+	if err != nil {
+		t.Fatalf("BusyIntervals: %v", err)
+	}
+	if !strings.Contains(query, `<C:time-range start="20261019T000000Z" end="20261021T000000Z"/>`) {
+		t.Errorf("calendar-query has unexpected time-range: %s", query)
+	}
+	assertIntervals(t, busy, []TimeInterval{
+		{Start: jstTime(10, 19, 10, 0), End: jstTime(10, 19, 11, 0)},
+		{Start: jstTime(10, 20, 14, 0), End: jstTime(10, 20, 15, 30)},
+	})
+
+	events, err := provider.ListEvents(context.Background(), start, end)
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	if len(events) != 2 || events[0].Summary != "定例" || events[1].Location != "会議室A" {
+		t.Errorf("unexpected events: %+v", events)
+	}
+
+	free, err := GetFreeIntervals(context.Background(), provider, jstTime(10, 19, 9, 0), jstTime(10, 19, 13, 0), 60)
+	if err != nil {
+		t.Fatalf("GetFreeIntervals: %v", err)
+	}
+	assertIntervals(t, free, []TimeInterval{
+		{Start: jstTime(10, 19, 9, 0), End: jstTime(10, 19, 10, 0)},
+		{Start: jstTime(10, 19, 11, 0), End: jstTime(10, 19, 13, 0)},
+	})
+}
+
+func TestCalDAVProviderUnauthorized(t *testing.T) {
+	var query string
+	srv := newCalDAVStub(t, &query)
+	provider, err := NewCalDAVProvider(CalDAVConfig{
+		CalendarURL: srv.URL + "/remote.php/dav/calendars/alice/personal/",
+		Username:    "alice",
+		Password:    "wrong",
+	}, tokyo)
+	if err != nil {
+		t.Fatalf("NewCalDAVProvider: %v", err)
+	}
+	provider.client = srv.Client() // テストサーバーはループバックで待ち受ける
+
+	if _, err := provider.BusyIntervals(context.Background(), jstTime(10, 19, 9, 0), jstTime(10, 21, 9, 0)); err == nil {
+		t.Fatal("expected error for unauthorized caldav request")
+	}
+}
+
+func TestNewCalDAVProviderRejectsInvalidURL(t *testing.T) {
+	for _, u := range []string{"", "ftp://example.com/cal/", "/relative"} {
+		if _, err := NewCalDAVProvider(CalDAVConfig{CalendarURL: u}, tokyo); err == nil {
+			t.Errorf("NewCalDAVProvider(%q): expected error", u)
+		}
+	}
+}
+
+func TestCalDAVProviderRefusesInternalHosts(t *testing.T) {
+	var query string
+	srv := newCalDAVStub(t, &query)
+	provider, err := NewCalDAVProvider(CalDAVConfig{
+		CalendarURL: srv.URL + "/remote.php/dav/calendars/alice/personal/",
+		Username:    "alice",
+		Password:    "app-password",
+	}, tokyo)
+	if err != nil {
+		t.Fatalf("NewCalDAVProvider: %v", err)
+	}
+
+	_, err = provider.BusyIntervals(context.Background(), jstTime(10, 19, 9, 0), jstTime(10, 21, 9, 0))
+	if !errors.Is(err, ErrNonPublicAddress) {
+		t.Fatalf("BusyIntervals(loopback) = %v, want ErrNonPublicAddress", err)
+	}
+	if query != "" {
+		t.Error("credentials were sent to the loopback server")
+	}
+}
@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"log"
-	"net/http"
 	"sort"
 	"strings"
 	"time"
@@ -105,6 +103,7 @@ type CalendarEvent struct {
 	Location    string `json:"location"`
 }
 
+// DateRangeRequest は POST /calendar（presentation.GetCalendarFreeIntervals）のリクエストを表す
 type DateRangeRequest struct {
 	StartDate   string `json:"start_date" binding:"required"` // RFC3339形式の開始日時
 	EndDate     string `json:"end_date" binding:"required"`   // RFC3339形式の終了日時
@@ -151,90 +150,6 @@ func (cs *CalendarService) GetEventsInDateRange(startDate, endDate time.Time) ([
 	return calendarEvents, nil
 }
 
-func GetGoogleCalendarEvents(c *gin.Context) {
-	const CredFile = "client_secret.json"
-
-	tokenString, err := ExtractTokenFromHeader(c)
-	if err != nil {
-		log.Printf("トークンの取得に失敗しました: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status": "error",
-			"error":  "認証トークンが必要です",
-		})
-		return
-	}
-
-	calendarService, err := NewCalendarServiceFromTokenString(tokenString, CredFile)
-	if err != nil {
-		log.Printf("カレンダーサービスの初期化に失敗しました: %v", err)
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"status": "error",
-			"error":  "提供されたトークンが無効です",
-		})
-		return
-	}
-
-	var req DateRangeRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		log.Printf("リクエストボディのバインドに失敗しました: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status": "error",
-			"error":  "JSON形式のstart_date, end_dateを指定してください (RFC3339)",
-		})
-		return
-	}
-
-	startTime, err := time.Parse(time.RFC3339, req.StartDate)
-	if err != nil {
-		log.Printf("開始日時の解析に失敗しました: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status": "error",
-			"error":  "start_dateはRFC3339形式で指定してください",
-		})
-		return
-	}
-
-	endTime, err := time.Parse(time.RFC3339, req.EndDate)
-	if err != nil {
-		log.Printf("終了日時の解析に失敗しました: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status": "error",
-			"error":  "end_dateはRFC3339形式で指定してください",
-		})
-		return
-	}
-
-	if endTime.Before(startTime) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status": "error",
-			"error":  "end_dateはstart_date以降である必要があります",
-		})
-		return
-	}
-
-	if req.DurationMin < 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status": "error",
-			"error":  "durationMinは0以上で指定してください",
-		})
-		return
-	}
-
-	events, err := calendarService.GetFreeIntervalsInRange(startTime, endTime, req.DurationMin)
-	if err != nil {
-		log.Printf("イベントの取得に失敗しました: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"status": "error",
-			"error":  "Googleカレンダーからのイベント取得に失敗しました",
-		})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"events": events,
-	})
-}
-
 // TimeInterval は開始時刻と終了時刻からなる時間区間を表す
 type TimeInterval struct {
 	Start time.Time
@@ -243,18 +158,23 @@ type TimeInterval struct {
 
 // GetFreeIntervalsInRange は、指定範囲 [startDate, endDate) の中で予定が入っていない全ての時間帯を返す
 func (cs *CalendarService) GetFreeIntervalsInRange(startDate, endDate time.Time, durationMin int) ([]TimeInterval, error) {
-	if endDate.Before(startDate) || endDate.Equal(startDate) {
-		return []TimeInterval{}, nil
-	}
+	return GetFreeIntervals(context.Background(), cs, startDate, endDate, durationMin)
+}
+
+// ListEvents は CalendarProvider としてプライマリカレンダーの予定を返す
+func (cs *CalendarService) ListEvents(ctx context.Context, start, end time.Time) ([]*CalendarEvent, error) {
+	return cs.GetEventsInDateRange(start, end)
+}
 
-	events, err := cs.GetEventsInDateRange(startDate, endDate)
+// BusyIntervals は CalendarProvider としてプライマリカレンダーの予定が入っている区間を返す
+func (cs *CalendarService) BusyIntervals(ctx context.Context, start, end time.Time) ([]TimeInterval, error) {
+	events, err := cs.GetEventsInDateRange(start, end)
 	if err != nil {
 		return nil, err
 	}
 
+	loc := start.Location()
 	busyIntervals := make([]TimeInterval, 0, len(events))
-	loc := startDate.Location()
-
 	for _, e := range events {
 		s, serr := parseRFC3339OrDate(e.StartTime, loc)
 		if serr != nil {
@@ -264,6 +184,21 @@ func (cs *CalendarService) GetFreeIntervalsInRange(startDate, endDate time.Time,
 		if terr != nil {
 			continue
 		}
+		busyIntervals = append(busyIntervals, TimeInterval{Start: s, End: t})
+	}
+	return busyIntervals, nil
+}
+
+// FreeIntervalsFromBusy は予定が入っている区間 busy から、範囲 [startDate, endDate) の中で予定が入っていない全ての時間帯を返す
+// Google カレンダー以外から取り込んだ予定にも同じ計算を使う
+func FreeIntervalsFromBusy(busy []TimeInterval, startDate, endDate time.Time, durationMin int) []TimeInterval {
+	if endDate.Before(startDate) || endDate.Equal(startDate) {
+		return []TimeInterval{}
+	}
+
+	busyIntervals := make([]TimeInterval, 0, len(busy))
+	for _, b := range busy {
+		s, t := b.Start, b.End
 
 		// 範囲外へはみ出した予定はクランプ
 		if t.Before(startDate) || s.After(endDate) {
@@ -285,7 +220,7 @@ func (cs *CalendarService) GetFreeIntervalsInRange(startDate, endDate time.Time,
 	// ビジーの区間がない場合は、範囲全体を空き区間として返す
 	if len(busyIntervals) == 0 {
 		free := []TimeInterval{{Start: startDate, End: endDate}}
-		return filterIntervalsByDuration(free, durationMin), nil
+		return filterIntervalsByDuration(free, durationMin)
 	}
 
 	mergedBusy := mergeIntervals(busyIntervals)
@@ -306,8 +241,7 @@ func (cs *CalendarService) GetFreeIntervalsInRange(startDate, endDate time.Time,
 	}
 
 	// 最小継続時間でフィルタ
-	freeIntervals = filterIntervalsByDuration(freeIntervals, durationMin)
-	return freeIntervals, nil
+	return filterIntervalsByDuration(freeIntervals, durationMin)
 }
 
 // filterIntervalsByDuration は最小継続時間(分)で区間をフィルタする
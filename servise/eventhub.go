@@ -0,0 +1,60 @@
+package servise
+
+import "sync"
+
+// EventHub はイベントごとの「更新があった」という通知を、同じプロセス内の購読者に配る
+// 通知には内容を載せない。受け取った側が最新の状態を読み直すため、続けて届いた通知は 1 つにまとめてよい
+type EventHub struct {
+	mu   sync.Mutex
+	subs map[int64]map[chan struct{}]struct{}
+}
+
+// NewEventHub は購読者のいない EventHub を作る
+func NewEventHub() *EventHub {
+	return &EventHub{subs: make(map[int64]map[chan struct{}]struct{})}
+}
+
+// Subscribe は eventID の更新を受け取るチャネルと、購読をやめる関数を返す
+// 読み出されていない通知がある間に届いた通知は捨てる（購読者が遅くても Publish は待たない）
+func (h *EventHub) Subscribe(eventID int64) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	if h.subs[eventID] == nil {
+		h.subs[eventID] = make(map[chan struct{}]struct{})
+	}
+	h.subs[eventID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs[eventID], ch)
+			if len(h.subs[eventID]) == 0 {
+				delete(h.subs, eventID)
+			}
+			h.mu.Unlock()
+		})
+	}
+	return ch, unsubscribe
+}
+
+// Publish は eventID の購読者全員に更新を通知する
+func (h *EventHub) Publish(eventID int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[eventID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// SubscriberCount は eventID の購読者数を返す
+func (h *EventHub) SubscriberCount(eventID int64) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs[eventID])
+}
@@ -0,0 +1,49 @@
+package servise
+
+import "testing"
+
+func TestEventHubPublishesToSubscribersOfTheEvent(t *testing.T) {
+	hub := NewEventHub()
+	a, unsubA := hub.Subscribe(1)
+	b, unsubB := hub.Subscribe(1)
+	other, unsubOther := hub.Subscribe(2)
+	defer unsubB()
+	defer unsubOther()
+
+	hub.Publish(1)
+	for name, ch := range map[string]<-chan struct{}{"a": a, "b": b} {
+		select {
+		case <-ch:
+		default:
+			t.Errorf("subscriber %s was not notified", name)
+		}
+	}
+	select {
+	case <-other:
+		t.Error("subscriber of another event was notified")
+	default:
+	}
+
+	unsubA()
+	unsubA()
+	if n := hub.SubscriberCount(1); n != 1 {
+		t.Errorf("SubscriberCount = %d, want 1", n)
+	}
+}
+
+func TestEventHubCoalescesPendingNotifications(t *testing.T) {
+	hub := NewEventHub()
+	ch, unsub := hub.Subscribe(1)
+	defer unsub()
+
+	hub.Publish(1)
+	hub.Publish(1)
+	hub.Publish(1)
+
+	<-ch
+	select {
+	case <-ch:
+		t.Error("pending notifications were not coalesced")
+	default:
+	}
+}
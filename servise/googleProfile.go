@@ -0,0 +1,70 @@
+package servise
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// defaultGoogleUserInfoEndpoint は Google の OpenID Connect の userinfo エンドポイント
+const defaultGoogleUserInfoEndpoint = "https://openidconnect.googleapis.com/v1/userinfo"
+
+// GoogleProfile は userinfo エンドポイントが返すプロフィール
+type GoogleProfile struct {
+	Sub     string `json:"sub"` // Google のユーザーID
+	Name    string `json:"name"`
+	Picture string `json:"picture"`
+	Email   string `json:"email"`
+}
+
+// FetchGoogleProfile はカレンダーと同じトークンで Google のプロフィールを取得する
+// トークンに profile スコープが含まれていない場合はエラーになる
+// ローカル環境では GOOGLE_USERINFO_ENDPOINT にスタブサーバーの URL を指定できる
+func FetchGoogleProfile(ctx context.Context, tokenString string) (*GoogleProfile, error) {
+	token, err := parseTokenFromString(tokenString)
+	if err != nil {
+		return nil, fmt.Errorf("トークンの解析に失敗しました: %v", err)
+	}
+	endpoint := os.Getenv("GOOGLE_USERINFO_ENDPOINT")
+	if endpoint == "" {
+		endpoint = defaultGoogleUserInfoEndpoint
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("プロフィール取得リクエストの作成に失敗しました: %v", err)
+	}
+	token.SetAuthHeader(req)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Google のプロフィールの取得に失敗しました: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Google のプロフィールの取得に失敗しました: status=%d", res.StatusCode)
+	}
+
+	var profile GoogleProfile
+	if err := json.NewDecoder(res.Body).Decode(&profile); err != nil {
+		return nil, fmt.Errorf("プロフィールのパースに失敗しました: %v", err)
+	}
+	if profile.Sub == "" {
+		return nil, fmt.Errorf("プロフィールに sub が含まれていません")
+	}
+	return &profile, nil
+}
+
+// TimeZone はプライマリカレンダーのユーザー設定のタイムゾーン（IANA 名）を返す
+func (cs *CalendarService) TimeZone(ctx context.Context) (string, error) {
+	setting, err := cs.service.Settings.Get("timezone").Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("カレンダーのタイムゾーンの取得に失敗しました: %v", err)
+	}
+	return setting.Value, nil
+}
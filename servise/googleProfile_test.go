@@ -0,0 +1,33 @@
+package servise
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchGoogleProfile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		io.WriteString(w, `{"sub":"1234567890","name":"山田 太郎","picture":"https://lh3.googleusercontent.com/a/photo","email":"taro@example.com"}`)
+	}))
+	defer srv.Close()
+	t.Setenv("GOOGLE_USERINFO_ENDPOINT", srv.URL)
+
+	profile, err := FetchGoogleProfile(context.Background(), `{"access_token":"access-1","token_type":"Bearer"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Sub != "1234567890" || profile.Name != "山田 太郎" || profile.Picture != "https://lh3.googleusercontent.com/a/photo" {
+		t.Errorf("profile = %+v", profile)
+	}
+
+	if _, err := FetchGoogleProfile(context.Background(), "other-token"); err == nil {
+		t.Error("expected an error for a rejected token")
+	}
+}
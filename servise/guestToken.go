@@ -0,0 +1,67 @@
+package servise
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GuestTokenHeader はゲストの編集用トークンを受け取るヘッダ
+const GuestTokenHeader = "X-Guest-Token"
+
+// guestTokenPurpose は署名の対象に加える接頭辞
+// セッショントークンと同じ鍵で署名するため、互いに流用できないようにする
+const guestTokenPurpose = "guest:"
+
+// GuestTokenClaims はゲストの編集用トークンの内容
+type GuestTokenClaims struct {
+	EventID int64  `json:"eid"`
+	GuestID string `json:"gid"` // ゲストに発行したユーザーID(UUID)
+	Iat     int64  `json:"iat"`
+}
+
+// IssueGuestToken はイベントのゲストに対する署名付きの編集用トークンを発行する
+// 形式はセッショントークンと同じく base64url(payload).base64url(HMAC-SHA256)。有効期限は設けず、ゲストを削除すると使えなくなる
+func IssueGuestToken(eventID int64, guestID string, now time.Time) (string, error) {
+	secret, err := sessionSecret()
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(GuestTokenClaims{EventID: eventID, GuestID: guestID, Iat: now.Unix()})
+	if err != nil {
+		return "", fmt.Errorf("ゲストのトークンの作成に失敗しました: %v", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + signSession(secret, guestTokenPurpose+encoded), nil
+}
+
+// ParseGuestToken は編集用トークンの署名を検証し、内容を返す
+func ParseGuestToken(token string) (GuestTokenClaims, error) {
+	secret, err := sessionSecret()
+	if err != nil {
+		return GuestTokenClaims{}, err
+	}
+
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return GuestTokenClaims{}, fmt.Errorf("ゲストのトークンの形式が不正です")
+	}
+	if !hmac.Equal([]byte(sig), []byte(signSession(secret, guestTokenPurpose+encoded))) {
+		return GuestTokenClaims{}, fmt.Errorf("ゲストのトークンの署名が不正です")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return GuestTokenClaims{}, fmt.Errorf("ゲストのトークンの形式が不正です")
+	}
+	var claims GuestTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.GuestID == "" {
+		return GuestTokenClaims{}, fmt.Errorf("ゲストのトークンの形式が不正です")
+	}
+	return claims, nil
+}
@@ -0,0 +1,50 @@
+package servise
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGuestTokenRoundTrip(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+
+	token, err := IssueGuestToken(42, "0b6f7c1e-3a52-4d7a-9d6e-2f1c8a4b5e60", time.Unix(1792368000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := ParseGuestToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.EventID != 42 || claims.GuestID != "0b6f7c1e-3a52-4d7a-9d6e-2f1c8a4b5e60" || claims.Iat != 1792368000 {
+		t.Errorf("claims = %+v", claims)
+	}
+
+	if _, err := ParseGuestToken(token + "x"); err == nil {
+		t.Error("tampered token was accepted")
+	}
+	t.Setenv("APP_SESSION_SECRET", "other-secret")
+	if _, err := ParseGuestToken(token); err == nil {
+		t.Error("token signed with another secret was accepted")
+	}
+}
+
+func TestGuestTokenIsNotASessionToken(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+
+	session, _, err := IssueSessionToken("0b6f7c1e-3a52-4d7a-9d6e-2f1c8a4b5e60", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseGuestToken(session); err == nil {
+		t.Error("session token was accepted as a guest token")
+	}
+
+	guest, err := IssueGuestToken(42, "0b6f7c1e-3a52-4d7a-9d6e-2f1c8a4b5e60", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseSessionToken(guest, time.Now()); err == nil {
+		t.Error("guest token was accepted as a session token")
+	}
+}
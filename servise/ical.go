@@ -0,0 +1,187 @@
+package servise
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ICalTimeZone は .ics の日時に付けるタイムゾーン（DB の接続設定と同じ Asia/Tokyo）
+const ICalTimeZone = "Asia/Tokyo"
+
+// icalProdID は生成した .ics の PRODID
+const icalProdID = "-//adjuSche//adjuSche//JA"
+
+// icalLineLimit は RFC 5545 の 1 行の最大オクテット数
+const icalLineLimit = 75
+
+// jst は ICalTimeZone の VTIMEZONE と同じ固定オフセット（夏時間なし）
+var jst = time.FixedZone("JST", 9*60*60)
+
+// VEVENT の STATUS
+const (
+	ICalStatusConfirmed = "CONFIRMED"
+	ICalStatusTentative = "TENTATIVE"
+	ICalStatusCancelled = "CANCELLED"
+)
+
+// ATTENDEE の PARTSTAT
+const (
+	ICalPartStatNeedsAction = "NEEDS-ACTION"
+	ICalPartStatAccepted    = "ACCEPTED"
+	ICalPartStatDeclined    = "DECLINED"
+)
+
+// ICalAttendee は VEVENT の ATTENDEE を表す
+// Address は mailto: や urn:uuid: などの URI
+type ICalAttendee struct {
+	Address  string
+	Name     string
+	PartStat string
+}
+
+// ICalEvent は 1 件の VEVENT を表す
+type ICalEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	Status      string
+	Sequence    int
+	Organizer   string
+	Attendees   []ICalAttendee
+	Updated     time.Time
+}
+
+// ICalendar は VCALENDAR を表す
+type ICalendar struct {
+	Name   string
+	Events []ICalEvent
+}
+
+// BuildICalendar は RFC 5545 形式の .ics を生成する
+// 日時は VTIMEZONE を付けた Asia/Tokyo のローカル時刻で出力する
+func BuildICalendar(cal ICalendar, now time.Time) []byte {
+	w := &icalWriter{}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:" + icalProdID)
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	if cal.Name != "" {
+		w.line("X-WR-CALNAME:" + escapeICalText(cal.Name))
+	}
+	w.line("X-WR-TIMEZONE:" + ICalTimeZone)
+
+	w.line("BEGIN:VTIMEZONE")
+	w.line("TZID:" + ICalTimeZone)
+	w.line("BEGIN:STANDARD")
+	w.line("DTSTART:19700101T000000")
+	w.line("TZOFFSETFROM:+0900")
+	w.line("TZOFFSETTO:+0900")
+	w.line("TZNAME:JST")
+	w.line("END:STANDARD")
+	w.line("END:VTIMEZONE")
+
+	stamp := formatICalUTC(now)
+	for _, ev := range cal.Events {
+		w.line("BEGIN:VEVENT")
+		w.line("UID:" + ev.UID)
+		w.line("DTSTAMP:" + stamp)
+		w.line("DTSTART;TZID=" + ICalTimeZone + ":" + formatICalLocal(ev.Start))
+		w.line("DTEND;TZID=" + ICalTimeZone + ":" + formatICalLocal(ev.End))
+		w.line("SUMMARY:" + escapeICalText(ev.Summary))
+		if ev.Description != "" {
+			w.line("DESCRIPTION:" + escapeICalText(ev.Description))
+		}
+		if ev.URL != "" {
+			w.line("URL:" + ev.URL)
+		}
+		if ev.Status != "" {
+			w.line("STATUS:" + ev.Status)
+		}
+		if ev.Status == ICalStatusTentative {
+			w.line("TRANSP:TRANSPARENT")
+		}
+		w.line(fmt.Sprintf("SEQUENCE:%d", ev.Sequence))
+		if !ev.Updated.IsZero() {
+			w.line("LAST-MODIFIED:" + formatICalUTC(ev.Updated))
+		}
+		if ev.Organizer != "" {
+			w.line("ORGANIZER:" + ev.Organizer)
+		}
+		for _, a := range ev.Attendees {
+			prop := "ATTENDEE;ROLE=REQ-PARTICIPANT"
+			if a.PartStat != "" {
+				prop += ";PARTSTAT=" + a.PartStat
+			}
+			if a.Name != "" {
+				prop += ";CN=" + quoteICalParam(a.Name)
+			}
+			w.line(prop + ":" + a.Address)
+		}
+		w.line("END:VEVENT")
+	}
+
+	w.line("END:VCALENDAR")
+	return []byte(w.b.String())
+}
+
+type icalWriter struct {
+	b strings.Builder
+}
+
+// line は 1 行を CRLF 付きで書き込み、75 オクテットを超える場合は折り返す
+// マルチバイト文字の途中では折り返さない
+func (w *icalWriter) line(s string) {
+	limit := icalLineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isUTF8Start(s[cut]) {
+			cut--
+		}
+		w.b.WriteString(s[:cut])
+		w.b.WriteString("\r\n ")
+		s = s[cut:]
+		// 継続行は先頭の空白の分だけ短くする
+		limit = icalLineLimit - 1
+	}
+	w.b.WriteString(s)
+	w.b.WriteString("\r\n")
+}
+
+func isUTF8Start(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// escapeICalText は TEXT 型の値をエスケープする
+func escapeICalText(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return r.Replace(s)
+}
+
+// quoteICalParam はパラメータ値を必要に応じてダブルクォートで囲む（値にダブルクォートは使えない）
+func quoteICalParam(s string) string {
+	s = strings.ReplaceAll(s, `"`, "'")
+	if strings.ContainsAny(s, ":;,") {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+func formatICalLocal(t time.Time) string {
+	return t.In(jst).Format("20060102T150405")
+}
+
+func formatICalUTC(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
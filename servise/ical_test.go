@@ -0,0 +1,145 @@
+package servise
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// unfoldICal は折り返された行を元に戻して行ごとに返す
+func unfoldICal(t *testing.T, ics string) []string {
+	t.Helper()
+	if !strings.HasSuffix(ics, "\r\n") {
+		t.Fatalf("ics does not end with CRLF: %q", ics)
+	}
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(ics, "\r\n ", ""), "\r\n"), "\r\n")
+}
+
+func TestICalWriterFoldsAt75Octets(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:定例会"},
+		{"exactly 75 octets", "DESCRIPTION:" + strings.Repeat("a", 75-len("DESCRIPTION:"))},
+		{"ascii", "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		{"multibyte", "SUMMARY:" + strings.Repeat("日程調整", 30)},
+		{"mixed", "SUMMARY:a" + strings.Repeat("あ🎉", 40)},
+	}
+	for _, tt := range tests {
+		w := &icalWriter{}
+		w.line(tt.line)
+		out := w.b.String()
+
+		physical := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+		for i, l := range physical {
+			if len(l) > icalLineLimit {
+				t.Errorf("%s: line %d is %d octets, want <= %d: %q", tt.name, i, len(l), icalLineLimit, l)
+			}
+			if i > 0 && !strings.HasPrefix(l, " ") {
+				t.Errorf("%s: continuation line %d does not start with a space: %q", tt.name, i, l)
+			}
+			if !utf8.ValidString(l) {
+				t.Errorf("%s: line %d splits a multibyte character: %q", tt.name, i, l)
+			}
+		}
+		if len(tt.line) <= icalLineLimit && len(physical) != 1 {
+			t.Errorf("%s: a line of %d octets should not be folded: %q", tt.name, len(tt.line), out)
+		}
+		if got := unfoldICal(t, out); len(got) != 1 || got[0] != tt.line {
+			t.Errorf("%s: unfolded = %q, want %q", tt.name, got, tt.line)
+		}
+	}
+}
+
+func TestEscapeICalText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"定例会", "定例会"},
+		{`C:\path`, `C:\\path`},
+		{"a;b,c", `a\;b\,c`},
+		{"1行目\r\n2行目\n3行目\r4行目", `1行目\n2行目\n3行目\n4行目`},
+		{`\;`, `\\\;`},
+		{"会議: 10:00", "会議: 10:00"}, // コロンは TEXT ではエスケープしない
+	}
+	for _, tt := range tests {
+		if got := escapeICalText(tt.in); got != tt.want {
+			t.Errorf("escapeICalText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteICalParam(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"佐藤 花子", "佐藤 花子"},
+		{"Sato, Hanako", `"Sato, Hanako"`},
+		{"a;b", `"a;b"`},
+		{"x:y", `"x:y"`},
+		{`say "hi"`, "say 'hi'"},
+	}
+	for _, tt := range tests {
+		if got := quoteICalParam(tt.in); got != tt.want {
+			t.Errorf("quoteICalParam(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBuildICalendar(t *testing.T) {
+	now := time.Date(2026, 10, 18, 3, 4, 5, 0, time.UTC)
+	cal := ICalendar{
+		Name: "adjuSche; 予定",
+		Events: []ICalEvent{{
+			UID:         "event-1@adjusche",
+			Summary:     "定例会, 第1回",
+			Description: "持ち物:\nPC",
+			Start:       time.Date(2026, 11, 2, 1, 0, 0, 0, time.UTC), // 10:00 JST
+			End:         time.Date(2026, 11, 2, 11, 0, 0, 0, jst),
+			Status:      ICalStatusTentative,
+			Sequence:    2,
+			Attendees: []ICalAttendee{
+				{Address: "urn:uuid:u1", Name: "Sato, Hanako", PartStat: ICalPartStatAccepted},
+			},
+		}},
+	}
+	got := unfoldICal(t, string(BuildICalendar(cal, now)))
+
+	for _, want := range []string{
+		`X-WR-CALNAME:adjuSche\; 予定`,
+		"X-WR-TIMEZONE:Asia/Tokyo",
+		"TZID:Asia/Tokyo",
+		"TZOFFSETTO:+0900",
+		"DTSTAMP:20261018T030405Z",
+		"DTSTART;TZID=Asia/Tokyo:20261102T100000",
+		"DTEND;TZID=Asia/Tokyo:20261102T110000",
+		"SUMMARY:定例会\\, 第1回",
+		"DESCRIPTION:持ち物:\\nPC",
+		"STATUS:TENTATIVE",
+		"TRANSP:TRANSPARENT",
+		"SEQUENCE:2",
+		`ATTENDEE;ROLE=REQ-PARTICIPANT;PARTSTAT=ACCEPTED;CN="Sato, Hanako":urn:uuid:u1`,
+	} {
+		if !slices.Contains(got, want) {
+			t.Errorf("missing line %q in:\n%s", want, strings.Join(got, "\n"))
+		}
+	}
+	if got[0] != "BEGIN:VCALENDAR" || got[len(got)-1] != "END:VCALENDAR" {
+		t.Errorf("calendar is not wrapped in VCALENDAR: first %q, last %q", got[0], got[len(got)-1])
+	}
+	// VTIMEZONE は VEVENT より前に置く
+	if tz, ev := slices.Index(got, "BEGIN:VTIMEZONE"), slices.Index(got, "BEGIN:VEVENT"); tz < 0 || ev < tz {
+		t.Errorf("VTIMEZONE at %d should come before VEVENT at %d", tz, ev)
+	}
+	// 空の項目は出力しない
+	for _, l := range got {
+		for _, prefix := range []string{"URL:", "ORGANIZER:", "LAST-MODIFIED:"} {
+			if strings.HasPrefix(l, prefix) {
+				t.Errorf("unexpected line %q for an empty field", l)
+			}
+		}
+	}
+}
@@ -0,0 +1,506 @@
+package servise
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // TZID のタイムゾーンを実行環境の zoneinfo に依存せず解決する
+)
+
+// MaxICSSize は取り込む .ics の最大サイズ
+const MaxICSSize = 5 << 20
+
+// icsFetchTimeout は URL から .ics を取得するときのタイムアウト
+const icsFetchTimeout = 10 * time.Second
+
+// icsHTTPClient は URL から .ics を取得する。利用者が指定した URL のため公開アドレスにしか接続しない
+var icsHTTPClient = NewPublicHTTPClient(icsFetchTimeout)
+
+// maxRecurrenceIterations は RRULE の展開で試す最大回数（終わりのない繰り返しへの備え）
+const maxRecurrenceIterations = 10000
+
+// icsProperty は .ics の 1 行（プロパティ）を表す
+type icsProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// icsEvent は取り込みに必要な VEVENT の項目を表す
+type icsEvent struct {
+	UID          string
+	Summary      string
+	Description  string
+	Location     string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	ExDates      []time.Time
+	RecurrenceID *time.Time
+	Transparent  bool
+	Cancelled    bool
+
+	rule *rrule
+}
+
+// FetchICS は http(s) または webcal の URL から .ics を取得する
+// 内部ネットワークのホストには接続せず、ErrNonPublicAddress を返す
+func FetchICS(ctx context.Context, rawURL string) ([]byte, error) {
+	if strings.HasPrefix(rawURL, "webcal://") {
+		rawURL = "https://" + strings.TrimPrefix(rawURL, "webcal://")
+	}
+	if !strings.HasPrefix(rawURL, "http://") && !strings.HasPrefix(rawURL, "https://") {
+		return nil, fmt.Errorf("unsupported ics url scheme: %s", rawURL)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, icsFetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ics request: %w", err)
+	}
+	res, err := icsHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ics: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch ics: status %d", res.StatusCode)
+	}
+	return ReadICS(res.Body)
+}
+
+// ReadICS は MaxICSSize までの .ics を読み込む
+func ReadICS(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxICSSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ics: %w", err)
+	}
+	if len(data) > MaxICSSize {
+		return nil, fmt.Errorf("ics is larger than %d bytes", MaxICSSize)
+	}
+	return data, nil
+}
+
+// ICSProvider は .ics の予定を CalendarProvider として扱う
+type ICSProvider struct {
+	events []icsEvent
+}
+
+// icsOccurrence は繰り返しを展開した予定の 1 回分を表す
+type icsOccurrence struct {
+	event *icsEvent
+	Start time.Time
+	End   time.Time
+}
+
+// NewICSProvider は .ics を解釈する。TZID の無い日時と終日の予定は loc の時刻として扱う
+func NewICSProvider(data []byte, loc *time.Location) (*ICSProvider, error) {
+	events, err := parseICSEvents(data, loc)
+	if err != nil {
+		return nil, err
+	}
+	return &ICSProvider{events: events}, nil
+}
+
+// ParseICSBusyIntervals は .ics を解釈し、範囲 [rangeStart, rangeEnd) に掛かる予定の区間を返す
+func ParseICSBusyIntervals(data []byte, rangeStart, rangeEnd time.Time, loc *time.Location) ([]TimeInterval, error) {
+	p, err := NewICSProvider(data, loc)
+	if err != nil {
+		return nil, err
+	}
+	return p.BusyIntervals(context.Background(), rangeStart, rangeEnd)
+}
+
+// ListEvents は範囲 [start, end) に掛かる予定を返す。STATUS:CANCELLED の予定は含めない
+func (p *ICSProvider) ListEvents(ctx context.Context, start, end time.Time) ([]*CalendarEvent, error) {
+	occs := p.occurrences(start, end)
+	events := make([]*CalendarEvent, 0, len(occs))
+	for _, o := range occs {
+		if o.event.Cancelled {
+			continue
+		}
+		layout := time.RFC3339
+		if o.event.AllDay {
+			layout = "2006-01-02"
+		}
+		events = append(events, &CalendarEvent{
+			Summary:     o.event.Summary,
+			Description: o.event.Description,
+			Location:    o.event.Location,
+			StartTime:   o.Start.Format(layout),
+			EndTime:     o.End.Format(layout),
+		})
+	}
+	return events, nil
+}
+
+// BusyIntervals は範囲 [start, end) に掛かる予定の区間を返す
+// TRANSP:TRANSPARENT と STATUS:CANCELLED の予定は空き時間を塞がないため含めない
+func (p *ICSProvider) BusyIntervals(ctx context.Context, start, end time.Time) ([]TimeInterval, error) {
+	occs := p.occurrences(start, end)
+	busy := make([]TimeInterval, 0, len(occs))
+	for _, o := range occs {
+		if o.event.Transparent || o.event.Cancelled {
+			continue
+		}
+		busy = append(busy, TimeInterval{Start: o.Start, End: o.End})
+	}
+	return busy, nil
+}
+
+// occurrences は VEVENT を RRULE / EXDATE / RECURRENCE-ID を考慮して展開し、範囲 [rangeStart, rangeEnd) に掛かるものを開始時刻順に返す
+func (p *ICSProvider) occurrences(rangeStart, rangeEnd time.Time) []icsOccurrence {
+	// RECURRENCE-ID 付きの VEVENT は、同じ UID の繰り返しの 1 回分を置き換える
+	overridden := make(map[string][]time.Time)
+	for _, ev := range p.events {
+		if ev.RecurrenceID != nil {
+			overridden[ev.UID] = append(overridden[ev.UID], *ev.RecurrenceID)
+		}
+	}
+
+	occs := make([]icsOccurrence, 0, len(p.events))
+	for i := range p.events {
+		ev := &p.events[i]
+		if ev.rule == nil || ev.RecurrenceID != nil {
+			if ev.End.After(rangeStart) && ev.Start.Before(rangeEnd) {
+				occs = append(occs, icsOccurrence{event: ev, Start: ev.Start, End: ev.End})
+			}
+			continue
+		}
+
+		excluded := append(append([]time.Time{}, ev.ExDates...), overridden[ev.UID]...)
+		duration := ev.End.Sub(ev.Start)
+		for _, start := range ev.rule.expand(ev.Start, rangeStart, rangeEnd) {
+			if containsTime(excluded, start) {
+				continue
+			}
+			end := start.Add(duration)
+			if ev.AllDay {
+				// 終日の予定は夏時間の切り替えをまたいでも日付単位で扱う
+				end = start.AddDate(0, 0, int(duration.Round(24*time.Hour)/(24*time.Hour)))
+			}
+			if end.After(rangeStart) {
+				occs = append(occs, icsOccurrence{event: ev, Start: start, End: end})
+			}
+		}
+	}
+
+	sort.SliceStable(occs, func(i, j int) bool { return occs[i].Start.Before(occs[j].Start) })
+	return occs
+}
+
+// parseICSEvents は VCALENDAR 直下の VEVENT を読み取る（VALARM などの入れ子のコンポーネントは無視する）
+func parseICSEvents(data []byte, loc *time.Location) ([]icsEvent, error) {
+	lines := unfoldICSLines(string(data))
+	if len(lines) == 0 || !strings.EqualFold(strings.TrimSpace(lines[0]), "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("ics must start with BEGIN:VCALENDAR")
+	}
+
+	var events []icsEvent
+	var props []icsProperty
+	var stack []string
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		prop, err := parseICSLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		switch prop.Name {
+		case "BEGIN":
+			stack = append(stack, strings.ToUpper(prop.Value))
+			if strings.EqualFold(prop.Value, "VEVENT") {
+				props = props[:0]
+			}
+			continue
+		case "END":
+			if len(stack) == 0 || !strings.EqualFold(stack[len(stack)-1], prop.Value) {
+				return nil, fmt.Errorf("line %d: unexpected END:%s", i+1, prop.Value)
+			}
+			stack = stack[:len(stack)-1]
+			if strings.EqualFold(prop.Value, "VEVENT") {
+				ev, err := newICSEvent(props, loc)
+				if err != nil {
+					return nil, err
+				}
+				if ev != nil {
+					events = append(events, *ev)
+				}
+			}
+			continue
+		}
+
+		if len(stack) > 0 && stack[len(stack)-1] == "VEVENT" {
+			props = append(props, prop)
+		}
+	}
+	if len(stack) != 0 {
+		return nil, fmt.Errorf("ics ended inside %s", stack[len(stack)-1])
+	}
+	return events, nil
+}
+
+// newICSEvent は VEVENT のプロパティから icsEvent を作る。DTSTART の無い VEVENT は nil を返す
+func newICSEvent(props []icsProperty, loc *time.Location) (*icsEvent, error) {
+	ev := &icsEvent{}
+	var hasStart, hasEnd bool
+	var duration, rruleValue string
+	for _, p := range props {
+		switch p.Name {
+		case "UID":
+			ev.UID = p.Value
+		case "SUMMARY":
+			ev.Summary = unescapeICalText(p.Value)
+		case "DESCRIPTION":
+			ev.Description = unescapeICalText(p.Value)
+		case "LOCATION":
+			ev.Location = unescapeICalText(p.Value)
+		case "DTSTART":
+			t, allDay, err := parseICSTime(p, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid DTSTART %q: %w", p.Value, err)
+			}
+			ev.Start, ev.AllDay, hasStart = t, allDay, true
+		case "DTEND":
+			t, _, err := parseICSTime(p, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid DTEND %q: %w", p.Value, err)
+			}
+			ev.End, hasEnd = t, true
+		case "DURATION":
+			duration = p.Value
+		case "RRULE":
+			rruleValue = p.Value
+		case "EXDATE":
+			for _, v := range strings.Split(p.Value, ",") {
+				t, _, err := parseICSTime(icsProperty{Name: p.Name, Params: p.Params, Value: v}, loc)
+				if err != nil {
+					return nil, fmt.Errorf("invalid EXDATE %q: %w", v, err)
+				}
+				ev.ExDates = append(ev.ExDates, t)
+			}
+		case "RECURRENCE-ID":
+			t, _, err := parseICSTime(p, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid RECURRENCE-ID %q: %w", p.Value, err)
+			}
+			ev.RecurrenceID = &t
+		case "TRANSP":
+			ev.Transparent = strings.EqualFold(p.Value, "TRANSPARENT")
+		case "STATUS":
+			ev.Cancelled = strings.EqualFold(p.Value, "CANCELLED")
+		}
+	}
+	if !hasStart {
+		return nil, nil
+	}
+
+	switch {
+	case hasEnd:
+	case duration != "":
+		d, err := parseICSDuration(duration)
+		if err != nil {
+			return nil, fmt.Errorf("invalid DURATION %q: %w", duration, err)
+		}
+		ev.End = ev.Start.Add(d)
+	case ev.AllDay:
+		// DTEND の無い終日の予定は 1 日分
+		ev.End = ev.Start.AddDate(0, 0, 1)
+	default:
+		ev.End = ev.Start
+	}
+	if ev.End.Before(ev.Start) {
+		return nil, fmt.Errorf("DTEND is before DTSTART (UID=%s)", ev.UID)
+	}
+	if rruleValue != "" {
+		rule, err := parseRRule(rruleValue, ev.Start.Location())
+		if err != nil {
+			return nil, err
+		}
+		ev.rule = rule
+	}
+	return ev, nil
+}
+
+// unfoldICSLines は折り返された行（先頭が空白またはタブ）を連結して 1 行ずつに分ける
+func unfoldICSLines(s string) []string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\r", "\n")
+	s = strings.TrimPrefix(s, "\ufeff")
+
+	var lines []string
+	for _, raw := range strings.Split(s, "\n") {
+		if (strings.HasPrefix(raw, " ") || strings.HasPrefix(raw, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += raw[1:]
+			continue
+		}
+		lines = append(lines, raw)
+	}
+	return lines
+}
+
+// parseICSLine は "NAME;PARAM=VALUE:value" 形式の行を解釈する（ダブルクォート内の ":" と ";" は区切りとみなさない）
+func parseICSLine(line string) (icsProperty, error) {
+	colon := -1
+	inQuote := false
+	for i, r := range line {
+		if r == '"' {
+			inQuote = !inQuote
+		}
+		if r == ':' && !inQuote {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return icsProperty{}, fmt.Errorf("missing ':' in %q", line)
+	}
+
+	head, value := line[:colon], line[colon+1:]
+	parts := splitOutsideQuotes(head, ';')
+	prop := icsProperty{
+		Name:   strings.ToUpper(parts[0]),
+		Params: make(map[string]string, len(parts)-1),
+		Value:  value,
+	}
+	for _, param := range parts[1:] {
+		k, v, _ := strings.Cut(param, "=")
+		prop.Params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return prop, nil
+}
+
+func splitOutsideQuotes(s string, sep rune) []string {
+	var parts []string
+	inQuote := false
+	last := 0
+	for i, r := range s {
+		if r == '"' {
+			inQuote = !inQuote
+		}
+		if r == sep && !inQuote {
+			parts = append(parts, s[last:i])
+			last = i + 1
+		}
+	}
+	return append(parts, s[last:])
+}
+
+// parseICSTime は DATE / DATE-TIME の値を解釈する。戻り値の bool は日付のみ（終日）かどうか
+func parseICSTime(p icsProperty, loc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(p.Value)
+	if tzid := p.Params["TZID"]; tzid != "" {
+		// Outlook の "Tokyo Standard Time" のように IANA 名でない TZID は既定のタイムゾーンとみなす
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	if strings.EqualFold(p.Params["VALUE"], "DATE") || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// parseICSDuration は "PT1H30M" や "P1D", "P2W" 形式の期間を解釈する
+func parseICSDuration(s string) (time.Duration, error) {
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("duration must start with P")
+	}
+	s = s[1:]
+
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			num += string(r)
+			continue
+		case r == 'T':
+			inTime = true
+			continue
+		}
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number in duration")
+		}
+		num = ""
+		switch {
+		case r == 'W' && !inTime:
+			d += time.Duration(n) * 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			d += time.Duration(n) * 24 * time.Hour
+		case r == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case r == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case r == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("unexpected %q in duration", r)
+		}
+	}
+	if num != "" {
+		return 0, fmt.Errorf("duration has no unit")
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// unescapeICalText は TEXT 型の値のエスケープを戻す
+func unescapeICalText(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		if !escaped {
+			if r == '\\' {
+				escaped = true
+				continue
+			}
+			b.WriteRune(r)
+			continue
+		}
+		escaped = false
+		switch r {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func containsTime(ts []time.Time, t time.Time) bool {
+	for _, x := range ts {
+		if x.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
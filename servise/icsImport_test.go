@@ -0,0 +1,130 @@
+package servise
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var tokyo = time.FixedZone("JST", 9*60*60)
+
+func jstTime(month time.Month, day, hour, min int) time.Time {
+	return time.Date(2026, month, day, hour, min, 0, 0, tokyo)
+}
+
+func assertIntervals(t *testing.T, got, want []TimeInterval) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d intervals %v, want %d %v", len(got), got, len(want), want)
+	}
+	for i := range want {
+		if !got[i].Start.Equal(want[i].Start) || !got[i].End.Equal(want[i].End) {
+			t.Errorf("[%d] = %s - %s, want %s - %s", i, got[i].Start, got[i].End, want[i].Start, want[i].End)
+		}
+	}
+}
+
+func TestParseICSBusyIntervals(t *testing.T) {
+	data, err := os.ReadFile("testdata/outlook.ics")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	busy, err := ParseICSBusyIntervals(data, jstTime(10, 19, 0, 0), jstTime(10, 27, 0, 0), tokyo)
+	if err != nil {
+		t.Fatalf("ParseICSBusyIntervals: %v", err)
+	}
+
+	assertIntervals(t, busy, []TimeInterval{
+		// 毎週月・水の定例（10/21 は EXDATE で除外）
+		{Start: jstTime(10, 19, 10, 0), End: jstTime(10, 19, 11, 0)},
+		// UTC で書かれた DURATION 付きの予定
+		{Start: jstTime(10, 20, 12, 0), End: jstTime(10, 20, 13, 0)},
+		// 終日の予定
+		{Start: jstTime(10, 22, 0, 0), End: jstTime(10, 23, 0, 0)},
+		// RECURRENCE-ID で午後に移動した回
+		{Start: jstTime(10, 26, 15, 0), End: jstTime(10, 26, 16, 0)},
+	})
+}
+
+func TestFetchICSComputesFreeIntervals(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/outlook.ics")
+	}))
+	t.Cleanup(srv.Close)
+	// テストサーバーはループバックで待ち受けるため、公開アドレスの制限のないクライアントに差し替える
+	orig := icsHTTPClient
+	icsHTTPClient = srv.Client()
+	t.Cleanup(func() { icsHTTPClient = orig })
+
+	data, err := FetchICS(context.Background(), srv.URL+"/outlook.ics")
+	if err != nil {
+		t.Fatalf("FetchICS: %v", err)
+	}
+
+	start, end := jstTime(10, 19, 9, 0), jstTime(10, 19, 18, 0)
+	busy, err := ParseICSBusyIntervals(data, start, end, tokyo)
+	if err != nil {
+		t.Fatalf("ParseICSBusyIntervals: %v", err)
+	}
+
+	free := FreeIntervalsFromBusy(busy, start, end, 60)
+	assertIntervals(t, free, []TimeInterval{
+		{Start: jstTime(10, 19, 9, 0), End: jstTime(10, 19, 10, 0)},
+		{Start: jstTime(10, 19, 11, 0), End: jstTime(10, 19, 18, 0)},
+	})
+}
+
+func TestParseICSBusyIntervalsMonthlyRule(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:second-tuesday",
+		"DTSTART;TZID=Asia/Tokyo:20260908T190000",
+		"DTEND;TZID=Asia/Tokyo:20260908T200000",
+		"RRULE:FREQ=MONTHLY;BYDAY=2TU;COUNT=3",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:last-friday",
+		"DTSTART;TZID=Asia/Tokyo:20260130T180000",
+		"DTEND;TZID=Asia/Tokyo:20260130T210000",
+		"RRULE:FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\n")
+
+	busy, err := ParseICSBusyIntervals([]byte(ics), jstTime(10, 1, 0, 0), jstTime(12, 31, 0, 0), tokyo)
+	if err != nil {
+		t.Fatalf("ParseICSBusyIntervals: %v", err)
+	}
+
+	assertIntervals(t, busy, []TimeInterval{
+		{Start: jstTime(10, 13, 19, 0), End: jstTime(10, 13, 20, 0)},
+		{Start: jstTime(11, 10, 19, 0), End: jstTime(11, 10, 20, 0)},
+		// 1 月から 2 か月ごとの最終金曜日: 1, 3, 5, 7, 9, 11 月
+		{Start: jstTime(11, 27, 18, 0), End: jstTime(11, 27, 21, 0)},
+	})
+}
+
+func TestParseICSBusyIntervalsRejectsInvalidICS(t *testing.T) {
+	tests := []struct {
+		name string
+		ics  string
+	}{
+		{name: "not ics", ics: "hello"},
+		{name: "unterminated event", ics: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20261020T100000\n"},
+		{name: "invalid dtstart", ics: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:tomorrow\nEND:VEVENT\nEND:VCALENDAR\n"},
+		{name: "unsupported freq", ics: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20261020T100000\nRRULE:FREQ=HOURLY\nEND:VEVENT\nEND:VCALENDAR\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseICSBusyIntervals([]byte(tt.ics), jstTime(10, 1, 0, 0), jstTime(11, 1, 0, 0), tokyo); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package servise
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// defaultLineVerifyEndpoint は LINE Login の ID トークン検証エンドポイント
+const defaultLineVerifyEndpoint = "https://api.line.me/oauth2/v2.1/verify"
+
+// LineIDTokenClaims は LINE の verify エンドポイントが返す ID トークンの内容
+type LineIDTokenClaims struct {
+	Iss     string `json:"iss"`
+	Sub     string `json:"sub"` // LINE userId
+	Aud     string `json:"aud"`
+	Exp     int64  `json:"exp"`
+	Iat     int64  `json:"iat"`
+	Name    string `json:"name"`
+	Picture string `json:"picture"`
+	Email   string `json:"email"`
+}
+
+// VerifyLineIDToken は LIFF から受け取った ID トークンを LINE の verify エンドポイントで検証する
+// ローカル環境では LINE_VERIFY_ENDPOINT にスタブサーバーの URL を指定できる
+func VerifyLineIDToken(ctx context.Context, idToken string) (*LineIDTokenClaims, error) {
+	channelID := os.Getenv("LINE_LOGIN_CHANNEL_ID")
+	if channelID == "" {
+		return nil, fmt.Errorf("LINE_LOGIN_CHANNEL_ID が設定されていません")
+	}
+	endpoint := os.Getenv("LINE_VERIFY_ENDPOINT")
+	if endpoint == "" {
+		endpoint = defaultLineVerifyEndpoint
+	}
+
+	form := url.Values{}
+	form.Set("id_token", idToken)
+	form.Set("client_id", channelID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("IDトークン検証リクエストの作成に失敗しました: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("IDトークンの検証に失敗しました: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var body struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		_ = json.NewDecoder(res.Body).Decode(&body)
+		return nil, fmt.Errorf("IDトークンが無効です: %s %s", body.Error, body.ErrorDescription)
+	}
+
+	var claims LineIDTokenClaims
+	if err := json.NewDecoder(res.Body).Decode(&claims); err != nil {
+		return nil, fmt.Errorf("IDトークン検証結果のパースに失敗しました: %v", err)
+	}
+	if claims.Sub == "" {
+		return nil, fmt.Errorf("IDトークンに sub が含まれていません")
+	}
+	if claims.Aud != channelID {
+		return nil, fmt.Errorf("IDトークンの aud が一致しません")
+	}
+	return &claims, nil
+}
@@ -0,0 +1,76 @@
+package servise
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newLineVerifyStub は id_token が "valid-token" のときだけ claims を返す LINE の verify エンドポイントのスタブ
+func newLineVerifyStub(t *testing.T, claims LineIDTokenClaims) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.PostForm.Get("client_id") != "1234567890" {
+			t.Errorf("client_id = %q", r.PostForm.Get("client_id"))
+		}
+		if r.PostForm.Get("id_token") != "valid-token" {
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"error":"invalid_request","error_description":"Invalid IdToken."}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(claims)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("LINE_VERIFY_ENDPOINT", srv.URL)
+	t.Setenv("LINE_LOGIN_CHANNEL_ID", "1234567890")
+	return srv
+}
+
+func TestVerifyLineIDToken(t *testing.T) {
+	newLineVerifyStub(t, LineIDTokenClaims{Iss: "https://access.line.me", Sub: "U0123456789", Aud: "1234567890", Name: "山田", Picture: "https://profile.line-scdn.net/a"})
+
+	claims, err := VerifyLineIDToken(context.Background(), "valid-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Sub != "U0123456789" || claims.Name != "山田" || claims.Picture != "https://profile.line-scdn.net/a" {
+		t.Errorf("claims = %+v", claims)
+	}
+
+	if _, err := VerifyLineIDToken(context.Background(), "expired-token"); err == nil {
+		t.Error("a token rejected by LINE was accepted")
+	}
+}
+
+func TestVerifyLineIDTokenRejectsInvalidClaims(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims LineIDTokenClaims
+	}{
+		{"issued for another channel", LineIDTokenClaims{Sub: "U0123456789", Aud: "9999999999"}},
+		{"no subject", LineIDTokenClaims{Aud: "1234567890"}},
+	}
+	for _, tt := range tests {
+		newLineVerifyStub(t, tt.claims)
+		if _, err := VerifyLineIDToken(context.Background(), "valid-token"); err == nil {
+			t.Errorf("%s: token was accepted", tt.name)
+		}
+	}
+}
+
+func TestVerifyLineIDTokenRequiresChannelID(t *testing.T) {
+	t.Setenv("LINE_LOGIN_CHANNEL_ID", "")
+	if _, err := VerifyLineIDToken(context.Background(), "valid-token"); err == nil {
+		t.Error("verified a token without LINE_LOGIN_CHANNEL_ID")
+	}
+}
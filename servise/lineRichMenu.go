@@ -0,0 +1,116 @@
+package servise
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/line/line-bot-sdk-go/v7/linebot"
+)
+
+// リッチメニューのポストバックで送られる action の値
+const (
+	RichMenuActionNewEvent = "new_event"
+	RichMenuActionMyEvents = "my_events"
+	RichMenuActionHelp     = "help"
+)
+
+// リッチメニュー画像のサイズ（横 3 分割のコンパクトサイズ）
+const (
+	richMenuWidth  = 2500
+	richMenuHeight = 843
+)
+
+// NewRichMenu は「新規イベント / マイイベント / ヘルプ」の 3 ボタンからなるリッチメニューを返す
+func NewRichMenu() linebot.RichMenu {
+	actions := []struct {
+		action      string
+		displayText string
+	}{
+		{RichMenuActionNewEvent, "新規イベント"},
+		{RichMenuActionMyEvents, "マイイベント"},
+		{RichMenuActionHelp, "ヘルプ"},
+	}
+
+	areaWidth := richMenuWidth / len(actions)
+	areas := make([]linebot.AreaDetail, 0, len(actions))
+	for i, a := range actions {
+		width := areaWidth
+		// 割り切れない分は最後のボタンに含める
+		if i == len(actions)-1 {
+			width = richMenuWidth - areaWidth*i
+		}
+		areas = append(areas, linebot.AreaDetail{
+			Bounds: linebot.RichMenuBounds{X: areaWidth * i, Y: 0, Width: width, Height: richMenuHeight},
+			Action: linebot.RichMenuAction{
+				Type:        linebot.RichMenuActionTypePostback,
+				Data:        RichMenuPostbackData(a.action),
+				DisplayText: a.displayText,
+			},
+		})
+	}
+
+	return linebot.RichMenu{
+		Size:        linebot.RichMenuSize{Width: richMenuWidth, Height: richMenuHeight},
+		Selected:    true,
+		Name:        "adjuSche default",
+		ChatBarText: "メニュー",
+		Areas:       areas,
+	}
+}
+
+// RichMenuPostbackData はリッチメニューのポストバックデータを組み立てる
+func RichMenuPostbackData(action string) string {
+	q := url.Values{}
+	q.Set("action", action)
+	return q.Encode()
+}
+
+// ParseRichMenuPostbackData はポストバックデータから action を取り出す
+func ParseRichMenuPostbackData(data string) string {
+	q, err := url.ParseQuery(data)
+	if err != nil {
+		return ""
+	}
+	return q.Get("action")
+}
+
+// SetupRichMenu はリッチメニューを作成し、画像をアップロードしてデフォルトに設定する
+// replaceExisting が true の場合は既存のリッチメニューを削除する
+func SetupRichMenu(bot *linebot.Client, imagePath string, replaceExisting bool) (string, error) {
+	var existing []*linebot.RichMenuResponse
+	if replaceExisting {
+		menus, err := bot.GetRichMenuList().Do()
+		if err != nil {
+			return "", fmt.Errorf("リッチメニュー一覧の取得に失敗しました: %v", err)
+		}
+		existing = menus
+	}
+
+	res, err := bot.CreateRichMenu(NewRichMenu()).Do()
+	if err != nil {
+		return "", fmt.Errorf("リッチメニューの作成に失敗しました: %v", err)
+	}
+	richMenuID := res.RichMenuID
+	log.Printf("リッチメニューを作成しました: %s", richMenuID)
+
+	if _, err := bot.UploadRichMenuImage(richMenuID, imagePath).Do(); err != nil {
+		return "", fmt.Errorf("リッチメニュー画像のアップロードに失敗しました: %v", err)
+	}
+	log.Printf("リッチメニュー画像をアップロードしました: %s", imagePath)
+
+	if _, err := bot.SetDefaultRichMenu(richMenuID).Do(); err != nil {
+		return "", fmt.Errorf("デフォルトリッチメニューの設定に失敗しました: %v", err)
+	}
+	log.Printf("デフォルトリッチメニューに設定しました: %s", richMenuID)
+
+	for _, m := range existing {
+		if _, err := bot.DeleteRichMenu(m.RichMenuID).Do(); err != nil {
+			log.Printf("古いリッチメニューの削除に失敗しました: %s: %v", m.RichMenuID, err)
+			continue
+		}
+		log.Printf("古いリッチメニューを削除しました: %s", m.RichMenuID)
+	}
+
+	return richMenuID, nil
+}
@@ -0,0 +1,283 @@
+package servise
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/microsoft"
+)
+
+// defaultGraphEndpoint は Microsoft Graph API のエンドポイント
+const defaultGraphEndpoint = "https://graph.microsoft.com/v1.0"
+
+// graphTimeout は Microsoft Graph への 1 リクエストのタイムアウト
+const graphTimeout = 15 * time.Second
+
+// graphScheduleMaxDays は getSchedule で 1 度に問い合わせられる期間の上限（日）
+const graphScheduleMaxDays = 62
+
+// graphDateTimeLayout は Graph の dateTimeTimeZone.dateTime の形式
+const graphDateTimeLayout = "2006-01-02T15:04:05.9999999"
+
+// MicrosoftCalendarScopes は Outlook カレンダーの読み取りに必要なスコープ
+var MicrosoftCalendarScopes = []string{"offline_access", "User.Read", "Calendars.Read"}
+
+// OutlookCalendarService は Microsoft Graph を使って Microsoft 365 / Outlook のカレンダーを読む CalendarProvider
+type OutlookCalendarService struct {
+	client   *http.Client
+	endpoint string
+}
+
+// MicrosoftOAuthConfig は環境変数から Microsoft ID プラットフォームの OAuth2 設定を作る
+// MS_CLIENT_ID / MS_CLIENT_SECRET はアクセストークンの更新に使う。MS_TENANT_ID を省略すると "common"（職場・個人アカウントの両方）になる
+func MicrosoftOAuthConfig() *oauth2.Config {
+	tenant := os.Getenv("MS_TENANT_ID")
+	if tenant == "" {
+		tenant = "common"
+	}
+	return &oauth2.Config{
+		ClientID:     os.Getenv("MS_CLIENT_ID"),
+		ClientSecret: os.Getenv("MS_CLIENT_SECRET"),
+		Endpoint:     microsoft.AzureADEndpoint(tenant),
+		Scopes:       MicrosoftCalendarScopes,
+	}
+}
+
+// NewOutlookCalendarServiceFromTokenString はフロントから受け取ったトークン文字列から OutlookCalendarService を作る
+// トークンは Google と同じく、生のアクセストークンまたは JSON 形式の oauth2.Token を受け付ける
+// ローカル環境では MS_GRAPH_ENDPOINT にスタブサーバーの URL を指定できる
+func NewOutlookCalendarServiceFromTokenString(tokenString string) (*OutlookCalendarService, error) {
+	token, err := parseTokenFromString(tokenString)
+	if err != nil {
+		return nil, fmt.Errorf("トークンの解析に失敗しました: %v", err)
+	}
+	if token.RefreshToken != "" && os.Getenv("MS_CLIENT_ID") == "" {
+		return nil, fmt.Errorf("MS_CLIENT_ID が設定されていません")
+	}
+
+	endpoint := os.Getenv("MS_GRAPH_ENDPOINT")
+	if endpoint == "" {
+		endpoint = defaultGraphEndpoint
+	}
+	return NewOutlookCalendarService(context.Background(), MicrosoftOAuthConfig(), token, endpoint), nil
+}
+
+// NewOutlookCalendarService は OAuth2 設定とトークンから OutlookCalendarService を作る
+// アクセストークンの期限が切れていればリフレッシュトークンで更新する
+func NewOutlookCalendarService(ctx context.Context, config *oauth2.Config, token *oauth2.Token, endpoint string) *OutlookCalendarService {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Timeout: graphTimeout})
+	return &OutlookCalendarService{
+		client:   config.Client(ctx, token),
+		endpoint: strings.TrimRight(endpoint, "/"),
+	}
+}
+
+// graphDateTime は Graph の dateTimeTimeZone を表す
+type graphDateTime struct {
+	DateTime string `json:"dateTime"`
+	TimeZone string `json:"timeZone"`
+}
+
+type graphEvent struct {
+	Subject     string        `json:"subject"`
+	BodyPreview string        `json:"bodyPreview"`
+	Start       graphDateTime `json:"start"`
+	End         graphDateTime `json:"end"`
+	IsAllDay    bool          `json:"isAllDay"`
+	IsCancelled bool          `json:"isCancelled"`
+	ShowAs      string        `json:"showAs"`
+	Location    struct {
+		DisplayName string `json:"displayName"`
+	} `json:"location"`
+}
+
+type graphScheduleItem struct {
+	Status string        `json:"status"`
+	Start  graphDateTime `json:"start"`
+	End    graphDateTime `json:"end"`
+}
+
+type graphError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ListEvents は範囲 [start, end) に掛かる予定を calendarView で返す。キャンセルされた予定は含めない
+func (s *OutlookCalendarService) ListEvents(ctx context.Context, start, end time.Time) ([]*CalendarEvent, error) {
+	q := url.Values{}
+	q.Set("startDateTime", start.UTC().Format(time.RFC3339))
+	q.Set("endDateTime", end.UTC().Format(time.RFC3339))
+	q.Set("$select", "subject,bodyPreview,start,end,location,isAllDay,isCancelled,showAs")
+	q.Set("$orderby", "start/dateTime")
+	q.Set("$top", "1000")
+	next := s.endpoint + "/me/calendarView?" + q.Encode()
+
+	loc := start.Location()
+	var events []*CalendarEvent
+	for next != "" {
+		var page struct {
+			Value    []graphEvent `json:"value"`
+			NextLink string       `json:"@odata.nextLink"`
+		}
+		if err := s.do(ctx, http.MethodGet, next, nil, &page); err != nil {
+			return nil, fmt.Errorf("指定期間のイベント取得に失敗しました: %v", err)
+		}
+		for _, item := range page.Value {
+			if item.IsCancelled {
+				continue
+			}
+			st, serr := parseGraphDateTime(item.Start)
+			et, eerr := parseGraphDateTime(item.End)
+			if serr != nil || eerr != nil {
+				continue
+			}
+			event := &CalendarEvent{
+				Summary:     item.Subject,
+				Description: item.BodyPreview,
+				Location:    item.Location.DisplayName,
+				StartTime:   st.In(loc).Format(time.RFC3339),
+				EndTime:     et.In(loc).Format(time.RFC3339),
+			}
+			if item.IsAllDay {
+				// 終日の予定はタイムゾーンに関係なく日付で扱う
+				event.StartTime = item.Start.DateTime[:len("2006-01-02")]
+				event.EndTime = item.End.DateTime[:len("2006-01-02")]
+			}
+			events = append(events, event)
+		}
+		next = page.NextLink
+	}
+	return events, nil
+}
+
+// BusyIntervals は範囲 [start, end) に掛かる、予定が入っている区間を getSchedule で返す
+// 状態が free の予定は空き時間を塞がないため含めない。getSchedule の期間の上限を超える場合は分けて問い合わせる
+func (s *OutlookCalendarService) BusyIntervals(ctx context.Context, start, end time.Time) ([]TimeInterval, error) {
+	address, err := s.scheduleAddress(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := start.Location()
+	var busy []TimeInterval
+	for from := start; from.Before(end); from = from.AddDate(0, 0, graphScheduleMaxDays) {
+		to := from.AddDate(0, 0, graphScheduleMaxDays)
+		if to.After(end) {
+			to = end
+		}
+
+		body := map[string]any{
+			"schedules":                []string{address},
+			"startTime":                graphDateTime{DateTime: from.UTC().Format(graphDateTimeLayout), TimeZone: "UTC"},
+			"endTime":                  graphDateTime{DateTime: to.UTC().Format(graphDateTimeLayout), TimeZone: "UTC"},
+			"availabilityViewInterval": 15,
+		}
+		var res struct {
+			Value []struct {
+				ScheduleID    string              `json:"scheduleId"`
+				ScheduleItems []graphScheduleItem `json:"scheduleItems"`
+				Error         *graphError         `json:"error"`
+			} `json:"value"`
+		}
+		if err := s.do(ctx, http.MethodPost, s.endpoint+"/me/calendar/getSchedule", body, &res); err != nil {
+			return nil, fmt.Errorf("空き時間情報の取得に失敗しました: %v", err)
+		}
+
+		for _, sch := range res.Value {
+			if sch.Error != nil {
+				return nil, fmt.Errorf("空き時間情報の取得に失敗しました: %s: %s", sch.ScheduleID, sch.Error.Message)
+			}
+			for _, item := range sch.ScheduleItems {
+				if strings.EqualFold(item.Status, "free") {
+					continue
+				}
+				st, serr := parseGraphDateTime(item.Start)
+				et, eerr := parseGraphDateTime(item.End)
+				if serr != nil || eerr != nil {
+					continue
+				}
+				busy = append(busy, TimeInterval{Start: st.In(loc), End: et.In(loc)})
+			}
+		}
+	}
+	sort.Slice(busy, func(i, j int) bool { return busy[i].Start.Before(busy[j].Start) })
+	return busy, nil
+}
+
+// scheduleAddress は getSchedule に指定するサインイン中のユーザーのメールアドレスを返す
+func (s *OutlookCalendarService) scheduleAddress(ctx context.Context) (string, error) {
+	var me struct {
+		Mail              string `json:"mail"`
+		UserPrincipalName string `json:"userPrincipalName"`
+	}
+	if err := s.do(ctx, http.MethodGet, s.endpoint+"/me?$select=mail,userPrincipalName", nil, &me); err != nil {
+		return "", fmt.Errorf("ユーザー情報の取得に失敗しました: %v", err)
+	}
+	if me.Mail != "" {
+		return me.Mail, nil
+	}
+	if me.UserPrincipalName != "" {
+		return me.UserPrincipalName, nil
+	}
+	return "", fmt.Errorf("ユーザーのメールアドレスが取得できませんでした")
+}
+
+// do は Graph API を呼び出し、レスポンスの JSON を out に読み込む
+// 日時はすべて UTC で受け取る
+func (s *OutlookCalendarService) do(ctx context.Context, method, rawURL string, body any, out any) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Prefer", `outlook.timezone="UTC"`)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		var e struct {
+			Error graphError `json:"error"`
+		}
+		if json.NewDecoder(res.Body).Decode(&e) == nil && e.Error.Code != "" {
+			return fmt.Errorf("status %d: %s: %s", res.StatusCode, e.Error.Code, e.Error.Message)
+		}
+		return fmt.Errorf("status %d", res.StatusCode)
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+// parseGraphDateTime は Graph の dateTimeTimeZone を時刻に変換する
+// IANA 形式でないタイムゾーン名（Windows 形式など）は UTC として扱う（リクエストでは常に UTC を指定している）
+func parseGraphDateTime(dt graphDateTime) (time.Time, error) {
+	loc := time.UTC
+	if dt.TimeZone != "" {
+		if l, err := time.LoadLocation(dt.TimeZone); err == nil {
+			loc = l
+		}
+	}
+	return time.ParseInLocation(graphDateTimeLayout, dt.DateTime, loc)
+}
@@ -0,0 +1,168 @@
+package servise
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// newGraphStub は Microsoft Graph と Microsoft ID プラットフォームのトークンエンドポイントの代わりになるサーバーを立てる
+// accessToken 以外のアクセストークンでの Graph 呼び出しは 401 を返す
+func newGraphStub(t *testing.T, accessToken string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /oauth2/v2.0/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("grant_type") != "refresh_token" || r.PostForm.Get("refresh_token") != "refresh-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			io.WriteString(w, `{"error":"invalid_grant"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"access_token":"`+accessToken+`","token_type":"Bearer","expires_in":3600,"refresh_token":"refresh-2"}`)
+	})
+
+	graph := http.NewServeMux()
+	graph.HandleFunc("GET /v1.0/me", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"mail":null,"userPrincipalName":"alice@contoso.onmicrosoft.com"}`)
+	})
+	graph.HandleFunc("POST /v1.0/me/calendar/getSchedule", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Schedules []string      `json:"schedules"`
+			StartTime graphDateTime `json:"startTime"`
+			EndTime   graphDateTime `json:"endTime"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode getSchedule request: %v", err)
+		}
+		if len(req.Schedules) != 1 || req.Schedules[0] != "alice@contoso.onmicrosoft.com" {
+			t.Errorf("schedules = %v", req.Schedules)
+		}
+		if req.StartTime.DateTime != "2026-10-19T00:00:00" || req.StartTime.TimeZone != "UTC" {
+			t.Errorf("startTime = %+v", req.StartTime)
+		}
+		io.WriteString(w, `{"value":[{"scheduleId":"alice@contoso.onmicrosoft.com","scheduleItems":[
+			{"status":"busy","start":{"dateTime":"2026-10-19T01:00:00.0000000","timeZone":"UTC"},"end":{"dateTime":"2026-10-19T02:00:00.0000000","timeZone":"UTC"}},
+			{"status":"free","start":{"dateTime":"2026-10-19T03:00:00.0000000","timeZone":"UTC"},"end":{"dateTime":"2026-10-19T04:00:00.0000000","timeZone":"UTC"}},
+			{"status":"tentative","start":{"dateTime":"2026-10-19T05:30:00.0000000","timeZone":"UTC"},"end":{"dateTime":"2026-10-19T06:00:00.0000000","timeZone":"UTC"}}
+		]}]}`)
+	})
+	graph.HandleFunc("GET /v1.0/me/calendarView", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			io.WriteString(w, `{"value":[
+				{"subject":"全社会議","start":{"dateTime":"2026-10-19T05:30:00.0000000","timeZone":"UTC"},"end":{"dateTime":"2026-10-19T06:00:00.0000000","timeZone":"UTC"},"location":{"displayName":"Teams"}},
+				{"subject":"中止","isCancelled":true,"start":{"dateTime":"2026-10-19T07:00:00.0000000","timeZone":"UTC"},"end":{"dateTime":"2026-10-19T08:00:00.0000000","timeZone":"UTC"}}
+			]}`)
+			return
+		}
+		if got := r.URL.Query().Get("startDateTime"); got != "2026-10-19T00:00:00Z" {
+			t.Errorf("startDateTime = %q", got)
+		}
+		io.WriteString(w, `{"value":[
+			{"subject":"定例","bodyPreview":"週次の定例","start":{"dateTime":"2026-10-19T01:00:00.0000000","timeZone":"UTC"},"end":{"dateTime":"2026-10-19T02:00:00.0000000","timeZone":"UTC"},"location":{"displayName":"会議室B"}}
+		],"@odata.nextLink":"http://`+r.Host+`/v1.0/me/calendarView?page=2"}`)
+	})
+	mux.Handle("/v1.0/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+accessToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"error":{"code":"InvalidAuthenticationToken","message":"Access token has expired or is not yet valid."}}`)
+			return
+		}
+		if got := r.Header.Get("Prefer"); got != `outlook.timezone="UTC"` {
+			t.Errorf("Prefer = %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		graph.ServeHTTP(w, r)
+	}))
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestOutlookService(t *testing.T, srv *httptest.Server, token *oauth2.Token) *OutlookCalendarService {
+	t.Helper()
+	config := &oauth2.Config{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		Endpoint:     oauth2.Endpoint{TokenURL: srv.URL + "/oauth2/v2.0/token"},
+		Scopes:       MicrosoftCalendarScopes,
+	}
+	return NewOutlookCalendarService(context.Background(), config, token, srv.URL+"/v1.0")
+}
+
+func TestOutlookCalendarServiceFreeIntervals(t *testing.T) {
+	srv := newGraphStub(t, "access-1")
+	cal := newTestOutlookService(t, srv, &oauth2.Token{AccessToken: "access-1", TokenType: "Bearer"})
+
+	start, end := jstTime(10, 19, 9, 0), jstTime(10, 19, 18, 0)
+	busy, err := cal.BusyIntervals(context.Background(), start, end)
+	if err != nil {
+		t.Fatalf("BusyIntervals: %v", err)
+	}
+	assertIntervals(t, busy, []TimeInterval{
+		{Start: jstTime(10, 19, 10, 0), End: jstTime(10, 19, 11, 0)},
+		{Start: jstTime(10, 19, 14, 30), End: jstTime(10, 19, 15, 0)},
+	})
+
+	free, err := GetFreeIntervals(context.Background(), cal, start, end, 60)
+	if err != nil {
+		t.Fatalf("GetFreeIntervals: %v", err)
+	}
+	assertIntervals(t, free, []TimeInterval{
+		{Start: jstTime(10, 19, 9, 0), End: jstTime(10, 19, 10, 0)},
+		{Start: jstTime(10, 19, 11, 0), End: jstTime(10, 19, 14, 30)},
+		{Start: jstTime(10, 19, 15, 0), End: jstTime(10, 19, 18, 0)},
+	})
+}
+
+func TestOutlookCalendarServiceListEvents(t *testing.T) {
+	srv := newGraphStub(t, "access-1")
+	cal := newTestOutlookService(t, srv, &oauth2.Token{AccessToken: "access-1", TokenType: "Bearer"})
+
+	events, err := cal.ListEvents(context.Background(), jstTime(10, 19, 9, 0), jstTime(10, 19, 18, 0))
+	if err != nil {
+		t.Fatalf("ListEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2: %+v", len(events), events)
+	}
+	if events[0].Summary != "定例" || events[0].Location != "会議室B" || events[0].StartTime != "2026-10-19T10:00:00+09:00" {
+		t.Errorf("events[0] = %+v", events[0])
+	}
+	if events[1].Summary != "全社会議" || events[1].EndTime != "2026-10-19T15:00:00+09:00" {
+		t.Errorf("events[1] = %+v", events[1])
+	}
+}
+
+func TestOutlookCalendarServiceRefreshesExpiredToken(t *testing.T) {
+	srv := newGraphStub(t, "access-2")
+	cal := newTestOutlookService(t, srv, &oauth2.Token{
+		AccessToken:  "access-1",
+		TokenType:    "Bearer",
+		RefreshToken: "refresh-1",
+		Expiry:       time.Now().Add(-time.Minute),
+	})
+
+	if _, err := cal.BusyIntervals(context.Background(), jstTime(10, 19, 9, 0), jstTime(10, 19, 18, 0)); err != nil {
+		t.Fatalf("BusyIntervals with expired token: %v", err)
+	}
+}
+
+func TestOutlookCalendarServiceReportsGraphError(t *testing.T) {
+	srv := newGraphStub(t, "access-2")
+	cal := newTestOutlookService(t, srv, &oauth2.Token{AccessToken: "access-1", TokenType: "Bearer"})
+
+	_, err := cal.BusyIntervals(context.Background(), jstTime(10, 19, 9, 0), jstTime(10, 19, 18, 0))
+	if err == nil || !strings.Contains(err.Error(), "InvalidAuthenticationToken") {
+		t.Fatalf("err = %v, want InvalidAuthenticationToken", err)
+	}
+}
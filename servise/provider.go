@@ -0,0 +1,27 @@
+package servise
+
+import (
+	"context"
+	"time"
+)
+
+// CalendarProvider は予定を取得するカレンダーを表す
+// Google カレンダー・CalDAV・.ics ファイルなど、取得元ごとに実装する
+type CalendarProvider interface {
+	// ListEvents は範囲 [start, end) に掛かる予定を返す
+	ListEvents(ctx context.Context, start, end time.Time) ([]*CalendarEvent, error)
+	// BusyIntervals は範囲 [start, end) に掛かる、予定が入っている区間を返す
+	BusyIntervals(ctx context.Context, start, end time.Time) ([]TimeInterval, error)
+}
+
+// GetFreeIntervals は provider の予定から、範囲 [start, end) の中で予定が入っていない時間帯を返す
+func GetFreeIntervals(ctx context.Context, provider CalendarProvider, start, end time.Time, durationMin int) ([]TimeInterval, error) {
+	if !end.After(start) {
+		return []TimeInterval{}, nil
+	}
+	busy, err := provider.BusyIntervals(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return FreeIntervalsFromBusy(busy, start, end, durationMin), nil
+}
@@ -0,0 +1,115 @@
+package servise
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// ErrNonPublicAddress は利用者が指定した URL の接続先が、内部ネットワーク（プライベート・ループバック・リンクローカルなど）であることを表す
+var ErrNonPublicAddress = errors.New("destination is not a public address")
+
+// nonPublicNetworks は IP アドレスの種類の判定で漏れる、外部から使われない予約済みのアドレス帯
+var nonPublicNetworks = mustParseCIDRs(
+	"0.0.0.0/8",     // このネットワーク
+	"100.64.0.0/10", // キャリアグレード NAT
+	"192.0.0.0/24",  // IETF プロトコル割り当て
+	"198.18.0.0/15", // ベンチマーク用
+	"240.0.0.0/4",   // 予約済み（255.255.255.255 を含む）
+	"64:ff9b::/96",  // NAT64（内部の IPv4 アドレスへ変換されうる）
+	"2002::/16",     // 6to4（同上）
+)
+
+// IsPublicIP は ip がインターネット上の宛先として使えるアドレスかを返す
+// ループバック・プライベート・リンクローカル（クラウドのメタデータ 169.254.169.254 を含む）・マルチキャスト・未指定は使えない
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, n := range nonPublicNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidatePublicURL は利用者が指定した URL が http(s) で、ホストがすべて公開アドレスに解決されることを確かめる
+// 登録時の入力チェック用。DNS の応答は後から変わりうるため、接続時にも NewPublicHTTPClient で確かめる
+func ValidatePublicURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("invalid url: %q", rawURL)
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		if !IsPublicIP(ip) {
+			return fmt.Errorf("%w: %s", ErrNonPublicAddress, ip)
+		}
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", u.Hostname(), err)
+	}
+	for _, a := range addrs {
+		if !IsPublicIP(a.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrNonPublicAddress, u.Hostname(), a.IP)
+		}
+	}
+	return nil
+}
+
+// NewPublicHTTPClient は公開アドレスにしか接続しない http.Client を作る
+// 利用者が指定した URL（.ics・CalDAV・Webhook）へのリクエストに使う。名前解決後の実際の接続先を確かめるため、
+// DNS の応答を差し替える攻撃やリダイレクトでも内部ネットワークには接続しない
+func NewPublicHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   publicOnlyControl,
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// 環境変数のプロキシを経由すると接続先の確認がプロキシへの接続に対して行われるため使わない
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ExpectContinueTimeout: time.Second,
+		},
+	}
+}
+
+// publicOnlyControl は名前解決後の接続先が公開アドレスでなければ接続を中止する
+func publicOnlyControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrNonPublicAddress, address)
+	}
+	return nil
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}
@@ -0,0 +1,91 @@
+package servise
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"203.0.113.10", true},
+		{"2001:4860:4860::8888", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.0.0.1", false},
+		{"172.16.5.4", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false}, // クラウドのメタデータ
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"100.64.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+	}
+	for _, tt := range tests {
+		if got := IsPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("IsPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePublicURL(t *testing.T) {
+	ctx := context.Background()
+	for _, u := range []string{
+		"http://127.0.0.1/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"https://[::1]/hook",
+		"http://10.1.2.3:8080/",
+		"http://localhost/hook",
+	} {
+		if err := ValidatePublicURL(ctx, u); !errors.Is(err, ErrNonPublicAddress) {
+			t.Errorf("ValidatePublicURL(%q) = %v, want ErrNonPublicAddress", u, err)
+		}
+	}
+	for _, u := range []string{"ftp://example.com/", "https://", "::not a url"} {
+		if err := ValidatePublicURL(ctx, u); err == nil {
+			t.Errorf("ValidatePublicURL(%q): expected error", u)
+		}
+	}
+	if err := ValidatePublicURL(ctx, "https://203.0.113.10/hook"); err != nil {
+		t.Errorf("ValidatePublicURL(public ip): %v", err)
+	}
+}
+
+func TestPublicHTTPClientRefusesLoopback(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := NewPublicHTTPClient(5 * time.Second).Get(srv.URL)
+	if !errors.Is(err, ErrNonPublicAddress) {
+		t.Fatalf("Get(%s) = %v, want ErrNonPublicAddress", srv.URL, err)
+	}
+	if called {
+		t.Error("request reached the loopback server")
+	}
+}
+
+func TestFetchICSRefusesInternalHosts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/outlook.ics")
+	}))
+	t.Cleanup(srv.Close)
+
+	if _, err := FetchICS(context.Background(), srv.URL+"/outlook.ics"); !errors.Is(err, ErrNonPublicAddress) {
+		t.Fatalf("FetchICS(loopback) = %v, want ErrNonPublicAddress", err)
+	}
+}
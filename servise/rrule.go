@@ -0,0 +1,318 @@
+package servise
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rrule は RRULE のうち空き時間の計算に必要な部分を表す
+// 対応: FREQ=DAILY/WEEKLY/MONTHLY/YEARLY, INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH, WKST
+type rrule struct {
+	freq       string
+	interval   int
+	count      int
+	until      time.Time
+	byDay      []rruleWeekday
+	byMonthDay []int
+	byMonth    []time.Month
+	wkst       time.Weekday
+}
+
+// rruleWeekday は BYDAY の 1 要素を表す（"2MO" なら n=2、"-1FR" なら n=-1、"TU" なら n=0）
+type rruleWeekday struct {
+	n       int
+	weekday time.Weekday
+}
+
+var icsWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// parseRRule は RRULE の値を解釈する。UNTIL が TZID の無い日時の場合は loc の時刻として扱う
+func parseRRule(value string, loc *time.Location) (*rrule, error) {
+	r := &rrule{interval: 1, wkst: time.Monday}
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid RRULE part %q", part)
+		}
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			r.freq = strings.ToUpper(v)
+		case "INTERVAL":
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid RRULE INTERVAL %q", v)
+			}
+			r.interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid RRULE COUNT %q", v)
+			}
+			r.count = n
+		case "UNTIL":
+			t, allDay, err := parseICSTime(icsProperty{Value: v, Params: map[string]string{}}, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid RRULE UNTIL %q: %w", v, err)
+			}
+			if allDay {
+				// 日付のみの UNTIL はその日の終わりまでを含む
+				t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+			r.until = t
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				wd, err := parseRRuleWeekday(d)
+				if err != nil {
+					return nil, err
+				}
+				r.byDay = append(r.byDay, wd)
+			}
+		case "BYMONTHDAY":
+			for _, d := range strings.Split(v, ",") {
+				n, err := strconv.Atoi(d)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid RRULE BYMONTHDAY %q", d)
+				}
+				r.byMonthDay = append(r.byMonthDay, n)
+			}
+		case "BYMONTH":
+			for _, m := range strings.Split(v, ",") {
+				n, err := strconv.Atoi(m)
+				if err != nil || n < 1 || n > 12 {
+					return nil, fmt.Errorf("invalid RRULE BYMONTH %q", m)
+				}
+				r.byMonth = append(r.byMonth, time.Month(n))
+			}
+		case "WKST":
+			wd, ok := icsWeekdays[strings.ToUpper(v)]
+			if !ok {
+				return nil, fmt.Errorf("invalid RRULE WKST %q", v)
+			}
+			r.wkst = wd
+		}
+	}
+
+	switch r.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return nil, fmt.Errorf("unsupported RRULE FREQ %q", r.freq)
+	}
+	return r, nil
+}
+
+func parseRRuleWeekday(s string) (rruleWeekday, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if len(s) < 2 {
+		return rruleWeekday{}, fmt.Errorf("invalid RRULE BYDAY %q", s)
+	}
+	wd, ok := icsWeekdays[s[len(s)-2:]]
+	if !ok {
+		return rruleWeekday{}, fmt.Errorf("invalid RRULE BYDAY %q", s)
+	}
+	n := 0
+	if prefix := s[:len(s)-2]; prefix != "" {
+		var err error
+		n, err = strconv.Atoi(prefix)
+		if err != nil || n == 0 {
+			return rruleWeekday{}, fmt.Errorf("invalid RRULE BYDAY %q", s)
+		}
+	}
+	return rruleWeekday{n: n, weekday: wd}, nil
+}
+
+// expand は dtstart から始まる繰り返しの開始時刻のうち、before より前のものを順に返す
+// COUNT の無い繰り返しは after の直前の周期から展開する（それより前の開始時刻は返さないことがある）
+// COUNT のある繰り返しは回数を数えるため dtstart から展開する
+func (r *rrule) expand(dtstart, after, before time.Time) []time.Time {
+	var occurrences []time.Time
+	n := 0
+	first := 0
+	if r.count == 0 {
+		first = r.periodsBetween(dtstart, after) - 1
+		if first < 0 {
+			first = 0
+		}
+	}
+	for k := first; k < first+maxRecurrenceIterations; k++ {
+		for _, t := range r.candidates(dtstart, k) {
+			if t.Before(dtstart) {
+				continue
+			}
+			if !r.until.IsZero() && t.After(r.until) {
+				return occurrences
+			}
+			if !t.Before(before) {
+				return occurrences
+			}
+			occurrences = append(occurrences, t)
+			n++
+			if r.count > 0 && n >= r.count {
+				return occurrences
+			}
+		}
+	}
+	return occurrences
+}
+
+// periodsBetween は dtstart から t までに経過したおおよその周期の数を返す
+func (r *rrule) periodsBetween(dtstart, t time.Time) int {
+	if !t.After(dtstart) {
+		return 0
+	}
+	var units int
+	switch r.freq {
+	case "DAILY":
+		units = int(t.Sub(dtstart) / (24 * time.Hour))
+	case "WEEKLY":
+		units = int(t.Sub(dtstart) / (7 * 24 * time.Hour))
+	case "MONTHLY":
+		units = (t.Year()-dtstart.Year())*12 + int(t.Month()) - int(dtstart.Month())
+	case "YEARLY":
+		units = t.Year() - dtstart.Year()
+	}
+	return units / r.interval
+}
+
+// candidates は k 番目の周期に含まれる開始時刻の候補を時刻順に返す
+func (r *rrule) candidates(dtstart time.Time, k int) []time.Time {
+	y, m, d := dtstart.Date()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, dtstart.Hour(), dtstart.Minute(), dtstart.Second(), 0, dtstart.Location())
+	}
+
+	var ts []time.Time
+	switch r.freq {
+	case "DAILY":
+		t := at(y, m, d+k*r.interval)
+		if r.matchMonth(t) && r.matchMonthDay(t) && r.matchWeekday(t) {
+			ts = append(ts, t)
+		}
+	case "WEEKLY":
+		offset := (int(dtstart.Weekday()) - int(r.wkst) + 7) % 7
+		weekStart := at(y, m, d-offset+7*k*r.interval)
+		for i := 0; i < 7; i++ {
+			t := weekStart.AddDate(0, 0, i)
+			if !r.matchMonth(t) {
+				continue
+			}
+			if len(r.byDay) == 0 && t.Weekday() != dtstart.Weekday() {
+				continue
+			}
+			if len(r.byDay) > 0 && !r.matchWeekday(t) {
+				continue
+			}
+			ts = append(ts, t)
+		}
+	case "MONTHLY":
+		first := at(y, m+time.Month(k*r.interval), 1)
+		if r.matchMonth(first) {
+			ts = r.daysInMonth(first, d)
+		}
+	case "YEARLY":
+		months := r.byMonth
+		if len(months) == 0 {
+			months = []time.Month{m}
+		}
+		for _, month := range sortedMonths(months) {
+			ts = append(ts, r.daysInMonth(at(y+k*r.interval, month, 1), d)...)
+		}
+	}
+	return ts
+}
+
+// daysInMonth は first の月のうち BYMONTHDAY / BYDAY に合う日を返す。どちらも無ければ dtstart と同じ日（無い月は飛ばす）
+func (r *rrule) daysInMonth(first time.Time, defaultDay int) []time.Time {
+	last := first.AddDate(0, 1, -1).Day()
+	day := func(n int) time.Time { return first.AddDate(0, 0, n-1) }
+
+	var ts []time.Time
+	switch {
+	case len(r.byMonthDay) > 0:
+		for n := 1; n <= last; n++ {
+			t := day(n)
+			if r.matchMonthDay(t) && (len(r.byDay) == 0 || r.matchWeekday(t)) {
+				ts = append(ts, t)
+			}
+		}
+	case len(r.byDay) > 0:
+		for n := 1; n <= last; n++ {
+			t := day(n)
+			for _, wd := range r.byDay {
+				if t.Weekday() != wd.weekday {
+					continue
+				}
+				// 月の中で何番目の曜日か（前から・後ろから）
+				fromStart := (n-1)/7 + 1
+				fromEnd := -((last-n)/7 + 1)
+				if wd.n == 0 || wd.n == fromStart || wd.n == fromEnd {
+					ts = append(ts, t)
+					break
+				}
+			}
+		}
+	default:
+		if defaultDay <= last {
+			ts = append(ts, day(defaultDay))
+		}
+	}
+
+	return ts
+}
+
+func (r *rrule) matchMonth(t time.Time) bool {
+	if len(r.byMonth) == 0 {
+		return true
+	}
+	for _, m := range r.byMonth {
+		if t.Month() == m {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *rrule) matchMonthDay(t time.Time) bool {
+	if len(r.byMonthDay) == 0 {
+		return true
+	}
+	last := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	for _, n := range r.byMonthDay {
+		if n == t.Day() || (n < 0 && last+n+1 == t.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchWeekday は BYDAY の曜日に一致するかを返す（DAILY / WEEKLY では序数を無視する）
+func (r *rrule) matchWeekday(t time.Time) bool {
+	if len(r.byDay) == 0 {
+		return true
+	}
+	for _, wd := range r.byDay {
+		if t.Weekday() == wd.weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedMonths(months []time.Month) []time.Month {
+	sorted := append([]time.Month{}, months...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
@@ -0,0 +1,96 @@
+package servise
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionTTL はアプリのセッショントークンの有効期間
+const SessionTTL = 24 * time.Hour
+
+// SessionHeader はアプリのセッショントークンを受け取るヘッダ
+// Authorization / X-Token は Google のトークンに使われているため別のヘッダを使う
+const SessionHeader = "X-Session-Token"
+
+type sessionClaims struct {
+	Sub string `json:"sub"` // アプリのユーザーID(UUID)
+	Exp int64  `json:"exp"`
+}
+
+// IssueSessionToken はユーザーIDに対する署名付きセッショントークンを発行する
+// 形式は base64url(payload).base64url(HMAC-SHA256)
+func IssueSessionToken(userID string, now time.Time) (string, time.Time, error) {
+	secret, err := sessionSecret()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt := now.Add(SessionTTL)
+	payload, err := json.Marshal(sessionClaims{Sub: userID, Exp: expiresAt.Unix()})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("セッションの作成に失敗しました: %v", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + signSession(secret, encoded), expiresAt, nil
+}
+
+// ParseSessionToken はセッショントークンの署名と有効期限を検証し、ユーザーIDを返す
+func ParseSessionToken(token string, now time.Time) (string, error) {
+	secret, err := sessionSecret()
+	if err != nil {
+		return "", err
+	}
+
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", fmt.Errorf("セッショントークンの形式が不正です")
+	}
+	if !hmac.Equal([]byte(sig), []byte(signSession(secret, encoded))) {
+		return "", fmt.Errorf("セッショントークンの署名が不正です")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("セッショントークンの形式が不正です")
+	}
+	var claims sessionClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("セッショントークンの形式が不正です")
+	}
+	if now.Unix() >= claims.Exp {
+		return "", fmt.Errorf("セッションの有効期限が切れています")
+	}
+	return claims.Sub, nil
+}
+
+// ExtractSessionUserID はリクエストヘッダのセッショントークンからユーザーIDを取り出す
+func ExtractSessionUserID(c *gin.Context) (string, error) {
+	token := c.GetHeader(SessionHeader)
+	if token == "" {
+		return "", fmt.Errorf("リクエストヘッダにセッショントークンが見つかりません")
+	}
+	return ParseSessionToken(token, time.Now())
+}
+
+func signSession(secret []byte, encoded string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func sessionSecret() ([]byte, error) {
+	secret := os.Getenv("APP_SESSION_SECRET")
+	if secret == "" {
+		return nil, fmt.Errorf("APP_SESSION_SECRET が設定されていません")
+	}
+	return []byte(secret), nil
+}
@@ -0,0 +1,100 @@
+package servise
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testUserID = "0b6f7c1e-3a52-4d7a-9d6e-2f1c8a4b5e60"
+
+func TestSessionTokenRoundTrip(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+	now := time.Unix(1792368000, 0)
+
+	token, expiresAt, err := IssueSessionToken(testUserID, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !expiresAt.Equal(now.Add(SessionTTL)) {
+		t.Errorf("expiresAt = %s, want %s", expiresAt, now.Add(SessionTTL))
+	}
+	userID, err := ParseSessionToken(token, now.Add(SessionTTL-time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if userID != testUserID {
+		t.Errorf("userID = %q, want %q", userID, testUserID)
+	}
+}
+
+func TestParseSessionTokenRejects(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+	now := time.Unix(1792368000, 0)
+	token, _, err := IssueSessionToken(testUserID, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, sig, _ := strings.Cut(token, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"someone-else","exp":9999999999}`))
+
+	tests := []struct {
+		name  string
+		token string
+		at    time.Time
+	}{
+		{"expired", token, now.Add(SessionTTL)},
+		{"tampered signature", encoded + "." + sig[:len(sig)-2] + "xx", now},
+		{"tampered payload", forged + "." + sig, now},
+		{"no signature", encoded, now},
+		{"not base64", "!!!." + sig, now},
+		{"empty", "", now},
+	}
+	for _, tt := range tests {
+		if _, err := ParseSessionToken(tt.token, tt.at); err == nil {
+			t.Errorf("%s: token was accepted", tt.name)
+		}
+	}
+
+	t.Setenv("APP_SESSION_SECRET", "other-secret")
+	if _, err := ParseSessionToken(token, now); err == nil {
+		t.Error("token signed with another secret was accepted")
+	}
+	t.Setenv("APP_SESSION_SECRET", "")
+	if _, _, err := IssueSessionToken(testUserID, now); err == nil {
+		t.Error("issued a session without APP_SESSION_SECRET")
+	}
+}
+
+func TestExtractSessionUserID(t *testing.T) {
+	t.Setenv("APP_SESSION_SECRET", "test-secret")
+	gin.SetMode(gin.TestMode)
+	token, _, err := IssueSessionToken(testUserID, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newContext := func(header string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			c.Request.Header.Set(SessionHeader, header)
+		}
+		return c
+	}
+
+	if userID, err := ExtractSessionUserID(newContext(token)); err != nil || userID != testUserID {
+		t.Errorf("ExtractSessionUserID = %q, %v", userID, err)
+	}
+	if _, err := ExtractSessionUserID(newContext("")); err == nil {
+		t.Error("missing header was accepted")
+	}
+	if _, err := ExtractSessionUserID(newContext(token + "x")); err == nil {
+		t.Error("tampered token was accepted")
+	}
+}
@@ -0,0 +1,108 @@
+package servise
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	rows := [][]string{
+		{"参加者", "2026/10/19 10:00-11:00"},
+		{"佐藤, 花子", "○"},
+		{`"引用"`, "×"},
+	}
+	if err := WriteCSV(&buf, rows); err != nil {
+		t.Fatalf("WriteCSV: %v", err)
+	}
+
+	want := "\ufeff" +
+		"参加者,2026/10/19 10:00-11:00\r\n" +
+		"\"佐藤, 花子\",○\r\n" +
+		"\"\"\"引用\"\"\",×\r\n"
+	if got := buf.String(); got != want {
+		t.Errorf("csv = %q, want %q", got, want)
+	}
+}
+
+func TestXLSXColumn(t *testing.T) {
+	tests := map[int]string{0: "A", 1: "B", 25: "Z", 26: "AA", 27: "AB", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"}
+	for n, want := range tests {
+		if got := xlsxColumn(n); got != want {
+			t.Errorf("xlsxColumn(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestXLSXSheetName(t *testing.T) {
+	if got := xlsxSheetName("10/19 [定例]: 候補?"); got != "1019 定例 候補" {
+		t.Errorf("xlsxSheetName = %q", got)
+	}
+	if got := xlsxSheetName("/:*?"); got != "Sheet1" {
+		t.Errorf("xlsxSheetName(all invalid) = %q, want Sheet1", got)
+	}
+	if got := []rune(xlsxSheetName(strings.Repeat("あ", 40))); len(got) != 31 {
+		t.Errorf("xlsxSheetName(long) has %d runes, want 31", len(got))
+	}
+}
+
+func TestBuildXLSX(t *testing.T) {
+	rows := [][]string{
+		{"参加者", "候補1"},
+		{"<佐藤 & 鈴木>", "○"},
+	}
+	data, err := BuildXLSX("定例/会議", rows)
+	if err != nil {
+		t.Fatalf("BuildXLSX: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("xlsx is not a zip: %v", err)
+	}
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		body, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(body)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		body, ok := files[name]
+		if !ok {
+			t.Fatalf("missing %s", name)
+		}
+		// どの部品も整形式の XML であること
+		dec := xml.NewDecoder(strings.NewReader(body))
+		for {
+			if _, err := dec.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s is not well-formed: %v", name, err)
+			}
+		}
+	}
+
+	if !strings.Contains(files["xl/workbook.xml"], `<sheet name="定例会議"`) {
+		t.Errorf("workbook = %s", files["xl/workbook.xml"])
+	}
+	sheet := files["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`state="frozen"`,
+		`<c r="A1" t="inlineStr"><is><t xml:space="preserve">参加者</t></is></c>`,
+		`<c r="B1" t="inlineStr"><is><t xml:space="preserve">候補1</t></is></c>`,
+		`<c r="A2" t="inlineStr"><is><t xml:space="preserve">&lt;佐藤 &amp; 鈴木&gt;</t></is></c>`,
+		`<c r="B2" t="inlineStr"><is><t xml:space="preserve">○</t></is></c>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet does not contain %s:\n%s", want, sheet)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package servise
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// SupabaseUser は Supabase Auth の /auth/v1/user が返すユーザー
+type SupabaseUser struct {
+	ID           string `json:"id"` // アプリのユーザーID(UUID)
+	Email        string `json:"email"`
+	UserMetadata struct {
+		FullName  string `json:"full_name"`
+		Name      string `json:"name"`
+		AvatarURL string `json:"avatar_url"`
+	} `json:"user_metadata"`
+}
+
+// DisplayName はユーザーのメタデータから表示名を返す
+func (u *SupabaseUser) DisplayName() string {
+	if u.UserMetadata.FullName != "" {
+		return u.UserMetadata.FullName
+	}
+	return u.UserMetadata.Name
+}
+
+// VerifySupabaseAccessToken はフロントエンドがサインインで得た Supabase のアクセストークンを Supabase Auth に問い合わせて検証する
+// 署名の鍵を持たずに済むよう、トークンの検証は Supabase Auth に任せる
+// ローカル環境では SUPABASE_URL にスタブサーバーの URL を指定できる
+func VerifySupabaseAccessToken(ctx context.Context, accessToken string) (*SupabaseUser, error) {
+	baseURL := strings.TrimRight(os.Getenv("SUPABASE_URL"), "/")
+	if baseURL == "" {
+		return nil, fmt.Errorf("SUPABASE_URL が設定されていません")
+	}
+	apiKey := os.Getenv("SUPABASE_ANON_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("SUPABASE_ANON_KEY が設定されていません")
+	}
+	if strings.TrimSpace(accessToken) == "" {
+		return nil, fmt.Errorf("アクセストークンが空です")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/auth/v1/user", nil)
+	if err != nil {
+		return nil, fmt.Errorf("アクセストークン検証リクエストの作成に失敗しました: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("apikey", apiKey)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("アクセストークンの検証に失敗しました: %v", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("アクセストークンが無効です: status=%d", res.StatusCode)
+	}
+
+	var user SupabaseUser
+	if err := json.NewDecoder(res.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("ユーザー情報のパースに失敗しました: %v", err)
+	}
+	if user.ID == "" {
+		return nil, fmt.Errorf("ユーザー情報に id が含まれていません")
+	}
+	return &user, nil
+}
@@ -0,0 +1,60 @@
+package servise
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newSupabaseAuthStub は access-1 だけを有効なトークンとして扱う Supabase Auth のスタブ
+func newSupabaseAuthStub(t *testing.T, body string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/auth/v1/user" || r.Header.Get("apikey") != "anon-key" {
+			t.Errorf("unexpected request: %s apikey=%q", r.URL.Path, r.Header.Get("apikey"))
+		}
+		if r.Header.Get("Authorization") != "Bearer access-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"code":401,"msg":"invalid JWT"}`)
+			return
+		}
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("SUPABASE_URL", srv.URL+"/")
+	t.Setenv("SUPABASE_ANON_KEY", "anon-key")
+}
+
+func TestVerifySupabaseAccessToken(t *testing.T) {
+	newSupabaseAuthStub(t, `{"id":"`+testUserID+`","email":"taro@example.com","user_metadata":{"full_name":"山田 太郎","avatar_url":"https://example.com/a.png"}}`)
+
+	user, err := VerifySupabaseAccessToken(context.Background(), "access-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != testUserID || user.DisplayName() != "山田 太郎" || user.UserMetadata.AvatarURL != "https://example.com/a.png" {
+		t.Errorf("user = %+v", user)
+	}
+
+	for _, token := range []string{"other-token", " "} {
+		if _, err := VerifySupabaseAccessToken(context.Background(), token); err == nil {
+			t.Errorf("token %q was accepted", token)
+		}
+	}
+}
+
+func TestVerifySupabaseAccessTokenRequiresUserID(t *testing.T) {
+	newSupabaseAuthStub(t, `{"email":"taro@example.com"}`)
+	if _, err := VerifySupabaseAccessToken(context.Background(), "access-1"); err == nil {
+		t.Error("a response without id was accepted")
+	}
+}
+
+func TestVerifySupabaseAccessTokenRequiresConfig(t *testing.T) {
+	t.Setenv("SUPABASE_URL", "")
+	if _, err := VerifySupabaseAccessToken(context.Background(), "access-1"); err == nil {
+		t.Error("verified a token without SUPABASE_URL")
+	}
+}
@@ -0,0 +1,141 @@
+package servise
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Webhook のリクエストヘッダ
+const (
+	WebhookEventHeader     = "X-AdjuSche-Event"
+	WebhookDeliveryHeader  = "X-AdjuSche-Delivery"
+	WebhookTimestampHeader = "X-AdjuSche-Timestamp"
+	WebhookSignatureHeader = "X-AdjuSche-Signature"
+)
+
+// webhookSignaturePrefix は署名ヘッダの値の接頭辞
+const webhookSignaturePrefix = "sha256="
+
+// WebhookMessage は送信する Webhook 1 件を表す
+type WebhookMessage struct {
+	URL        string
+	Secret     string
+	EventType  string
+	DeliveryID int64
+	Payload    []byte
+}
+
+// WebhookResult は Webhook の送信結果を表す
+type WebhookResult struct {
+	Attempts   int
+	StatusCode int   // 最後の試行の HTTP ステータス（接続できなかった場合は 0）
+	Err        error // 最後まで成功しなかった場合の原因
+}
+
+// WebhookSender は Webhook を送信し、失敗した場合は指数バックオフで再試行する
+type WebhookSender struct {
+	Client      *http.Client
+	MaxAttempts int
+	BaseDelay   time.Duration // 1 回目の再試行までの待ち時間。以降は 2 倍ずつ延ばす
+	MaxDelay    time.Duration
+	Now         func() time.Time
+}
+
+// NewWebhookSender は既定の設定（最大 5 回、1 秒から 2 倍ずつ最大 1 分待つ）の WebhookSender を作る
+// 送信先は主催者が指定するため、内部ネットワークのホストには接続しない
+func NewWebhookSender() *WebhookSender {
+	return &WebhookSender{
+		Client:      NewPublicHTTPClient(10 * time.Second),
+		MaxAttempts: 5,
+		BaseDelay:   time.Second,
+		MaxDelay:    time.Minute,
+		Now:         time.Now,
+	}
+}
+
+// SignWebhookPayload は "タイムスタンプ.本文" の HMAC-SHA256 を 16 進数で返す
+// 受信側は同じ計算をして WebhookSignatureHeader の値と比較し、タイムスタンプが古すぎるものは捨てる
+func SignWebhookPayload(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send は Webhook を送信する。2xx 以外の応答・接続エラーは再試行し、4xx（408 と 429 を除く）と
+// 内部ネットワークへの送信 (ErrNonPublicAddress) は再試行しない
+func (s *WebhookSender) Send(ctx context.Context, msg WebhookMessage) WebhookResult {
+	var res WebhookResult
+	delay := s.BaseDelay
+	for res.Attempts < s.MaxAttempts {
+		if res.Attempts > 0 {
+			select {
+			case <-ctx.Done():
+				res.Err = ctx.Err()
+				return res
+			case <-time.After(delay):
+			}
+			delay *= 2
+			if delay > s.MaxDelay {
+				delay = s.MaxDelay
+			}
+		}
+
+		res.Attempts++
+		res.StatusCode, res.Err = s.post(ctx, msg)
+		if res.Err == nil {
+			return res
+		}
+		if !retryableWebhookStatus(res.StatusCode) || errors.Is(res.Err, ErrNonPublicAddress) {
+			return res
+		}
+	}
+	return res
+}
+
+func (s *WebhookSender) post(ctx context.Context, msg WebhookMessage) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.URL, bytes.NewReader(msg.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	ts := s.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "adjuSche-Webhook/1.0")
+	req.Header.Set(WebhookEventHeader, msg.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(msg.DeliveryID, 10))
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(ts, 10))
+	req.Header.Set(WebhookSignatureHeader, webhookSignaturePrefix+SignWebhookPayload(msg.Secret, ts, msg.Payload))
+
+	res, err := s.Client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return res.StatusCode, fmt.Errorf("webhook endpoint responded with status %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
+
+// retryableWebhookStatus は再試行すべき応答かを返す（0 は接続エラー）
+func retryableWebhookStatus(status int) bool {
+	switch {
+	case status == 0, status == http.StatusRequestTimeout, status == http.StatusTooManyRequests:
+		return true
+	case status >= 500:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package servise
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestWebhookSender はループバックで待ち受けるテストサーバー srv に送信する WebhookSender を作る
+func newTestWebhookSender(srv *httptest.Server) *WebhookSender {
+	s := NewWebhookSender()
+	s.Client = srv.Client()
+	s.BaseDelay = time.Millisecond
+	s.MaxDelay = 4 * time.Millisecond
+	s.Now = func() time.Time { return time.Unix(1792368000, 0) }
+	return s
+}
+
+func TestWebhookSenderSignsPayload(t *testing.T) {
+	payload := []byte(`{"type":"event.created","data":{"eventId":"1"}}`)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != string(payload) {
+			t.Errorf("body = %s", body)
+		}
+		ts, err := strconv.ParseInt(r.Header.Get(WebhookTimestampHeader), 10, 64)
+		if err != nil || ts != 1792368000 {
+			t.Errorf("timestamp = %q", r.Header.Get(WebhookTimestampHeader))
+		}
+		want := "sha256=" + SignWebhookPayload("s3cret", ts, body)
+		if got := r.Header.Get(WebhookSignatureHeader); got != want {
+			t.Errorf("signature = %q, want %q", got, want)
+		}
+		if r.Header.Get(WebhookEventHeader) != "event.created" || r.Header.Get(WebhookDeliveryHeader) != "42" {
+			t.Errorf("headers = %v", r.Header)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	res := newTestWebhookSender(srv).Send(context.Background(), WebhookMessage{
+		URL: srv.URL, Secret: "s3cret", EventType: "event.created", DeliveryID: 42, Payload: payload,
+	})
+	if res.Err != nil || res.Attempts != 1 || res.StatusCode != http.StatusNoContent {
+		t.Fatalf("result = %+v", res)
+	}
+}
+
+func TestWebhookSenderRetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	res := newTestWebhookSender(srv).Send(context.Background(), WebhookMessage{URL: srv.URL, Secret: "s", Payload: []byte(`{}`)})
+	if res.Err != nil || res.Attempts != 3 || res.StatusCode != http.StatusOK {
+		t.Fatalf("result = %+v", res)
+	}
+}
+
+func TestWebhookSenderGivesUp(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+
+	sender := newTestWebhookSender(srv)
+	res := sender.Send(context.Background(), WebhookMessage{URL: srv.URL, Secret: "s", Payload: []byte(`{}`)})
+	if res.Err == nil || res.Attempts != sender.MaxAttempts || res.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("result = %+v", res)
+	}
+
+	// 4xx は再試行しない
+	calls.Store(0)
+	res = sender.Send(context.Background(), WebhookMessage{URL: srv.URL + "/gone", Secret: "s", Payload: []byte(`{}`)})
+	if res.Err == nil || res.Attempts != 1 || calls.Load() != 1 {
+		t.Fatalf("result = %+v, calls = %d", res, calls.Load())
+	}
+}
+
+func TestWebhookSenderRefusesInternalHosts(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	t.Cleanup(srv.Close)
+
+	sender := NewWebhookSender()
+	sender.BaseDelay = time.Millisecond
+	res := sender.Send(context.Background(), WebhookMessage{URL: srv.URL, Secret: "s", Payload: []byte(`{}`)})
+	if !errors.Is(res.Err, ErrNonPublicAddress) || res.Attempts != 1 || res.StatusCode != 0 || calls.Load() != 0 {
+		t.Fatalf("result = %+v, calls = %d", res, calls.Load())
+	}
+}
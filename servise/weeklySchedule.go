@@ -0,0 +1,132 @@
+package servise
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// minutesPerDay は 1 日の分数（WeeklyRule.End の 24:00）
+const minutesPerDay = 24 * 60
+
+// WeeklyRule は毎週繰り返す空いている・空いていない時間帯を表す
+type WeeklyRule struct {
+	Weekday   time.Weekday
+	Start     int  // 0:00 からの分
+	End       int  // 0:00 からの分（1440 は 24:00）
+	Available bool // true なら空いている時間帯、false なら空いていない時間帯
+}
+
+// WeeklySchedule はユーザーの勤務時間と毎週の繰り返しのルールを表す
+// 空いている時間帯のルールがある曜日はその時間帯だけ、ない曜日は勤務時間（未設定なら終日）を空きとみなし、
+// そこから空いていない時間帯のルールを除く
+type WeeklySchedule struct {
+	Location  *time.Location // ルールと勤務時間を解釈するタイムゾーン
+	WorkStart int            // 勤務時間の開始（0:00 からの分）
+	WorkEnd   int            // 勤務時間の終了（0:00 からの分）。0 なら勤務時間は未設定
+	Rules     []WeeklyRule
+}
+
+// IsZero は勤務時間もルールも設定されていないかを返す
+func (s WeeklySchedule) IsZero() bool {
+	return s.WorkEnd == 0 && len(s.Rules) == 0
+}
+
+// BusyIntervals は範囲 [start, end) に掛かる、ルールと勤務時間によって空いていない区間を返す
+func (s WeeklySchedule) BusyIntervals(start, end time.Time) []TimeInterval {
+	if s.IsZero() || !end.After(start) {
+		return []TimeInterval{}
+	}
+	loc := s.Location
+	if loc == nil {
+		loc = start.Location()
+	}
+
+	var busy []TimeInterval
+	first := start.In(loc)
+	for day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc); day.Before(end); day = day.AddDate(0, 0, 1) {
+		for _, r := range s.busyRanges(day.Weekday()) {
+			busy = append(busy, TimeInterval{Start: atMinute(day, r[0]), End: atMinute(day, r[1])})
+		}
+	}
+	return busy
+}
+
+// busyRanges は曜日 wd の空いていない時間帯を [開始, 終了) の分の組で返す
+func (s WeeklySchedule) busyRanges(wd time.Weekday) [][2]int {
+	var allowed, blocked [][2]int
+	for _, r := range s.Rules {
+		if r.Weekday != wd || r.End <= r.Start {
+			continue
+		}
+		if r.Available {
+			allowed = append(allowed, [2]int{r.Start, r.End})
+		} else {
+			blocked = append(blocked, [2]int{r.Start, r.End})
+		}
+	}
+	if len(allowed) == 0 {
+		if s.WorkEnd > s.WorkStart {
+			allowed = [][2]int{{s.WorkStart, s.WorkEnd}}
+		} else {
+			allowed = [][2]int{{0, minutesPerDay}}
+		}
+	}
+
+	// 空いている時間帯の補集合と、空いていない時間帯を合わせる
+	sort.Slice(allowed, func(i, j int) bool { return allowed[i][0] < allowed[j][0] })
+	busy := blocked
+	cursor := 0
+	for _, a := range allowed {
+		if a[0] > cursor {
+			busy = append(busy, [2]int{cursor, a[0]})
+		}
+		cursor = max(cursor, a[1])
+	}
+	if cursor < minutesPerDay {
+		busy = append(busy, [2]int{cursor, minutesPerDay})
+	}
+	return busy
+}
+
+// atMinute は day の 0:00 から minute 分後の時刻を返す（夏時間の切り替わりも壁時計の時刻で扱う）
+func atMinute(day time.Time, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), minute/60, minute%60, 0, 0, day.Location())
+}
+
+// ClipToWeeklySchedule は空き区間 intervals から、ルールと勤務時間によって空いていない区間を除く
+// 手入力の空き時間に適用し、durationMin 分に満たなくなった区間は除く
+func ClipToWeeklySchedule(intervals []TimeInterval, s WeeklySchedule, durationMin int) []TimeInterval {
+	if s.IsZero() {
+		return intervals
+	}
+	clipped := make([]TimeInterval, 0, len(intervals))
+	for _, iv := range intervals {
+		clipped = append(clipped, FreeIntervalsFromBusy(s.BusyIntervals(iv.Start, iv.End), iv.Start, iv.End, durationMin)...)
+	}
+	return clipped
+}
+
+// scheduledProvider はカレンダーの予定に、ルールと勤務時間によって空いていない区間を加える CalendarProvider
+type scheduledProvider struct {
+	CalendarProvider
+	schedule WeeklySchedule
+}
+
+// WithWeeklySchedule は provider の予定に schedule の空いていない区間を加えた CalendarProvider を返す
+// GetFreeIntervals で求める空き時間から、ルールの時間帯が自動的に除かれる
+func WithWeeklySchedule(provider CalendarProvider, schedule WeeklySchedule) CalendarProvider {
+	if schedule.IsZero() {
+		return provider
+	}
+	return scheduledProvider{CalendarProvider: provider, schedule: schedule}
+}
+
+// BusyIntervals はカレンダーの予定が入っている区間に、ルールと勤務時間によって空いていない区間を加えて返す
+func (p scheduledProvider) BusyIntervals(ctx context.Context, start, end time.Time) ([]TimeInterval, error) {
+	busy, err := p.CalendarProvider.BusyIntervals(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return append(busy, p.schedule.BusyIntervals(start, end)...), nil
+}
@@ -0,0 +1,87 @@
+package servise
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// 2026-11-02 は月曜日
+func onDay(day, hour, min int) time.Time {
+	return jstTime(time.November, day, hour, min)
+}
+
+type stubProvider struct {
+	busy []TimeInterval
+}
+
+func (p stubProvider) ListEvents(ctx context.Context, start, end time.Time) ([]*CalendarEvent, error) {
+	return nil, nil
+}
+
+func (p stubProvider) BusyIntervals(ctx context.Context, start, end time.Time) ([]TimeInterval, error) {
+	return p.busy, nil
+}
+
+func TestWithWeeklyScheduleExcludesRecurringBusyBlocks(t *testing.T) {
+	if _, wrapped := WithWeeklySchedule(stubProvider{}, WeeklySchedule{}).(scheduledProvider); wrapped {
+		t.Error("an empty schedule should not wrap the provider")
+	}
+
+	schedule := WeeklySchedule{
+		Location:  tokyo,
+		WorkStart: 10 * 60, // 10:00 より前は不可
+		WorkEnd:   minutesPerDay,
+		Rules: []WeeklyRule{
+			{Weekday: time.Wednesday, Start: 18 * 60, End: minutesPerDay}, // 水曜の夜は不可
+		},
+	}
+	provider := WithWeeklySchedule(stubProvider{busy: []TimeInterval{{Start: onDay(3, 13, 0), End: onDay(3, 14, 0)}}}, schedule)
+
+	// 火曜 0:00 から木曜 0:00 まで
+	free, err := GetFreeIntervals(context.Background(), provider, onDay(3, 0, 0), onDay(5, 0, 0), 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertIntervals(t, free, []TimeInterval{
+		{Start: onDay(3, 10, 0), End: onDay(3, 13, 0)},
+		{Start: onDay(3, 14, 0), End: onDay(4, 0, 0)},
+		{Start: onDay(4, 10, 0), End: onDay(4, 18, 0)},
+	})
+}
+
+func TestWeeklyScheduleAvailableRulesOverrideWorkingHours(t *testing.T) {
+	schedule := WeeklySchedule{
+		Location:  tokyo,
+		WorkStart: 9 * 60,
+		WorkEnd:   18 * 60,
+		Rules: []WeeklyRule{
+			{Weekday: time.Saturday, Start: 13 * 60, End: 15 * 60, Available: true},
+			{Weekday: time.Saturday, Start: 10 * 60, End: 11 * 60, Available: true},
+		},
+	}
+
+	// 土曜はルールの時間帯だけ、日曜は勤務時間を空きとみなす
+	free := ClipToWeeklySchedule([]TimeInterval{{Start: onDay(7, 0, 0), End: onDay(9, 0, 0)}}, schedule, 0)
+	assertIntervals(t, free, []TimeInterval{
+		{Start: onDay(7, 10, 0), End: onDay(7, 11, 0)},
+		{Start: onDay(7, 13, 0), End: onDay(7, 15, 0)},
+		{Start: onDay(8, 9, 0), End: onDay(8, 18, 0)},
+	})
+
+	// 空き時間に満たなくなった区間は除く
+	free = ClipToWeeklySchedule([]TimeInterval{{Start: onDay(7, 10, 30), End: onDay(7, 14, 0)}}, schedule, 60)
+	assertIntervals(t, free, []TimeInterval{{Start: onDay(7, 13, 0), End: onDay(7, 14, 0)}})
+}
+
+func TestWeeklyScheduleUsesItsTimezone(t *testing.T) {
+	// ニューヨークの平日 9:00-17:00 は、日本時間では 23:00-翌 7:00（冬時間）
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database is not available")
+	}
+	schedule := WeeklySchedule{Location: ny, WorkStart: 9 * 60, WorkEnd: 17 * 60}
+
+	free := ClipToWeeklySchedule([]TimeInterval{{Start: onDay(3, 12, 0), End: onDay(4, 12, 0)}}, schedule, 0)
+	assertIntervals(t, free, []TimeInterval{{Start: onDay(3, 23, 0), End: onDay(4, 7, 0)}})
+}